@@ -0,0 +1,212 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/pjy0381/ktop/ui"
+)
+
+// builtinCommands are the commands of the application, shared by its pages
+func (app *Application) builtinCommands() []ui.Command {
+	return []ui.Command{
+		{Name: "quit", Aliases: []string{"q"}, Keys: []string{"Esc"}, Help: "stop ktop", Run: func([]string) { app.Stop() }},
+		{Name: "help", Aliases: []string{"?"}, Keys: []string{"?"}, Help: "list the commands and their keys", Run: func([]string) { app.showHelp() }},
+		{Name: "page", Args: "<n|title>", MinArgs: 1, Help: "show page n, from 1, or the page titled title", Run: app.showPageCommand},
+		{Name: "focus-next", Keys: []string{"Tab"}, Help: "focus the next view of the page", Run: func([]string) { app.focusNext() }},
+		{Name: "down", Keys: []string{"j"}, Help: "move down in the focused view", Run: func([]string) { app.sendKey(tcell.KeyDown) }},
+		{Name: "up", Keys: []string{"k"}, Help: "move up in the focused view", Run: func([]string) { app.sendKey(tcell.KeyUp) }},
+	}
+}
+
+// BindKeys binds the default keys of the registered commands, F1 to F12 to
+// the pages, then the keys of the keymap file, and checks the bound lines.
+// Run binds them once the pages registered their commands.
+func (app *Application) BindKeys() error {
+	for _, cmd := range app.commands.List() {
+		for _, key := range cmd.Keys {
+			if err := app.keymap.Bind(map[string]string{key: cmd.Name}); err != nil {
+				return fmt.Errorf("command %s: %s", cmd.Name, err)
+			}
+		}
+	}
+	pageKeys := make(map[string]string)
+	for i := 0; i < len(app.pages) && i < 12; i++ {
+		pageKeys[fmt.Sprintf("F%d", i+1)] = fmt.Sprintf("page %d", i+1)
+	}
+	if err := app.keymap.Bind(pageKeys); err != nil {
+		return err
+	}
+	if err := app.keymap.Bind(app.keyBindings); err != nil {
+		return err
+	}
+	return app.keymap.Validate(app.commands)
+}
+
+// inputEditKeys are the keys an input field edits its text with
+var inputEditKeys = map[tcell.Key]bool{
+	tcell.KeyRune:       true,
+	tcell.KeyCtrlU:      true,
+	tcell.KeyCtrlK:      true,
+	tcell.KeyCtrlW:      true,
+	tcell.KeyBackspace:  true,
+	tcell.KeyBackspace2: true,
+	tcell.KeyDelete:     true,
+	tcell.KeyCtrlD:      true,
+	tcell.KeyLeft:       true,
+	tcell.KeyCtrlB:      true,
+	tcell.KeyRight:      true,
+	tcell.KeyCtrlF:      true,
+	tcell.KeyHome:       true,
+	tcell.KeyCtrlA:      true,
+	tcell.KeyEnd:        true,
+	tcell.KeyCtrlE:      true,
+}
+
+// inputKey reports whether an input field holding text handles the key of
+// event itself: the keys editing the text and, once some text was typed,
+// Enter and Esc finishing or dropping it
+func inputKey(event *tcell.EventKey, text string) bool {
+	if event.Modifiers()&tcell.ModAlt != 0 {
+		return false
+	}
+	switch event.Key() {
+	case tcell.KeyEnter, tcell.KeyEscape:
+		return text != ""
+	}
+	return inputEditKeys[event.Key()]
+}
+
+// HandleKey is the input capture of the application: it runs the command
+// bound to the key of event. The views holding the focus handle their keys
+// first, such as / searching the logs, and the keys handled by a focused
+// input field are left to the field, see inputKey.
+func (app *Application) HandleKey(event *tcell.EventKey) *tcell.EventKey {
+	if app.panel.helpShown() {
+		app.panel.closeHelp()
+		return nil
+	}
+	if field, ok := app.tviewApp.GetFocus().(*tview.InputField); ok && inputKey(event, field.GetText()) {
+		return event
+	}
+	if app.viewKey(event) == nil {
+		return nil
+	}
+	line, ok := app.keymap.Command(event)
+	if !ok {
+		return event
+	}
+	// bound lines are checked by BindKeys
+	app.commands.Run(line)
+	return nil
+}
+
+// inputCapturer is a view with an input capture, see tview.Box
+type inputCapturer interface {
+	GetInputCapture() func(event *tcell.EventKey) *tcell.EventKey
+}
+
+// viewKey passes event to the input captures of the views from the root to
+// the focused view, as tview delivers it to them, and returns nil when one
+// of them handled it. The captures leave the keys they don't handle as is.
+func (app *Application) viewKey(event *tcell.EventKey) *tcell.EventKey {
+	var root tview.Primitive
+	if app.panel.root != nil {
+		root = app.panel.root
+	}
+	for _, view := range focusPath(root, app.tviewApp.GetFocus()) {
+		if c, ok := view.(inputCapturer); ok && c.GetInputCapture() != nil {
+			if event = c.GetInputCapture()(event); event == nil {
+				return nil
+			}
+		}
+	}
+	return event
+}
+
+// focusPath returns the views from root down to focused, or only focused
+// when it can't be reached through the flexes and pages holding the focus
+func focusPath(root, focused tview.Primitive) []tview.Primitive {
+	if focused == nil {
+		return nil
+	}
+	var path []tview.Primitive
+	for view := root; view != nil; view = focusedChild(view) {
+		path = append(path, view)
+		if view == focused {
+			return path
+		}
+	}
+	return []tview.Primitive{focused}
+}
+
+// focusedChild returns the child of view holding the focus, if any
+func focusedChild(view tview.Primitive) tview.Primitive {
+	switch v := view.(type) {
+	case *tview.Flex:
+		for i := 0; i < v.GetItemCount(); i++ {
+			if item := v.GetItem(i); item.HasFocus() {
+				return item
+			}
+		}
+	case *tview.Pages:
+		if _, page := v.GetFrontPage(); page != nil && page.HasFocus() {
+			return page
+		}
+	}
+	return nil
+}
+
+// showPageCommand shows page args[0], counted from 1, or the page titled args
+func (app *Application) showPageCommand(args []string) {
+	titles := app.getPageTitles()
+	if n, err := strconv.Atoi(args[0]); err == nil {
+		if n >= 1 && n <= len(titles) {
+			app.ShowPage(titles[n-1])
+		}
+		return
+	}
+	for _, title := range titles {
+		if strings.EqualFold(title, strings.Join(args, " ")) {
+			app.ShowPage(title)
+		}
+	}
+}
+
+// focusNext focuses the next view of the visible page, in turn
+func (app *Application) focusNext() {
+	views := app.pages[app.visibleView].Panel.GetChildrenViews()
+	if len(views) == 0 {
+		return
+	}
+	// the views may have changed since the last focused one
+	app.tabIdx = (app.tabIdx + 1) % len(views)
+	app.Focus(views[app.tabIdx])
+}
+
+// sendKey passes key to the focused view, such as to move its selection
+func (app *Application) sendKey(key tcell.Key) {
+	focused := app.tviewApp.GetFocus()
+	if focused == nil {
+		return
+	}
+	if handler := focused.InputHandler(); handler != nil {
+		handler(tcell.NewEventKey(key, 0, tcell.ModNone), func(p tview.Primitive) { app.Focus(p) })
+	}
+}
+
+// showHelp lists the commands with their arguments, keys and help
+func (app *Application) showHelp() {
+	var text strings.Builder
+	w := tabwriter.NewWriter(&text, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, " COMMAND\tKEYS\tHELP")
+	for _, cmd := range app.commands.List() {
+		fmt.Fprintf(w, " %s\t%s\t%s\n", cmd.Usage(), strings.Join(app.keymap.Keys(app.commands, cmd.Name), " "), cmd.Help)
+	}
+	w.Flush()
+	app.panel.showHelp(text.String())
+}
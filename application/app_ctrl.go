@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/pjy0381/ktop/buildinfo"
 
@@ -31,6 +30,13 @@ type Application struct {
 	panel       *appPanel
 	refreshQ    chan struct{}
 	stopCh      chan struct{}
+
+	fleet          *k8s.Fleet
+	clientHandlers []func(*k8s.Client)
+
+	commands    *ui.Commands
+	keymap      *ui.Keymap
+	keyBindings map[string]string // of the keymap file
 }
 
 func New(k8sC *k8s.Client) *Application {
@@ -43,14 +49,73 @@ func New(k8sC *k8s.Client) *Application {
 		refreshQ:  make(chan struct{}, 1),
 		pageIdx:   -1,
 		tabIdx:    -1,
+		commands:  ui.NewCommands(),
+		keymap:    ui.NewKeymap(),
+	}
+	if err := app.commands.Register(app.builtinCommands()...); err != nil {
+		panic(fmt.Sprintf("application: %s", err))
 	}
 	return app
 }
 
+// Commands returns the registry the pages register their commands with
+func (app *Application) Commands() *ui.Commands {
+	return app.commands
+}
+
+// EnableMouse lets the mouse sort the lists by a click on their column
+// headers. The terminal no longer selects text while the mouse is enabled.
+func (app *Application) EnableMouse(enable bool) {
+	app.tviewApp.EnableMouse(enable)
+}
+
+// SetKeyBindings binds keys to command lines on top of the default keys
+// of the commands, such as from the keymap file. See ui.Keymap.
+func (app *Application) SetKeyBindings(bindings map[string]string) {
+	app.keyBindings = bindings
+}
+
 func (app *Application) GetK8sClient() *k8s.Client {
 	return app.k8sClient
 }
 
+// SetK8sClient displays the cluster of k8sC, redrawing the header and
+// notifying the handlers registered with OnK8sClientChanged
+func (app *Application) SetK8sClient(k8sC *k8s.Client) {
+	app.k8sClient = k8sC
+	app.namespace = k8sC.Namespace()
+	app.drawHeader()
+	for _, handler := range app.clientHandlers {
+		handler(k8sC)
+	}
+}
+
+// OnK8sClientChanged registers fn to be called when another cluster is displayed
+func (app *Application) OnK8sClientChanged(fn func(*k8s.Client)) {
+	app.clientHandlers = append(app.clientHandlers, fn)
+}
+
+// SetFleet sets the clusters watched in multi-cluster mode
+func (app *Application) SetFleet(fleet *k8s.Fleet) {
+	app.fleet = fleet
+}
+
+// GetFleet returns the clusters watched in multi-cluster mode, or nil
+func (app *Application) GetFleet() *k8s.Fleet {
+	return app.fleet
+}
+
+// ShowPage switches to the page with title
+func (app *Application) ShowPage(title string) {
+	for i, page := range app.pages {
+		if page.Title == title {
+			app.visibleView = i
+			app.tabIdx = -1
+		}
+	}
+	app.panel.switchToPage(title)
+}
+
 func (app *Application) AddPage(panel ui.PanelController) {
 	app.pages = append(app.pages, AppPage{Title: panel.GetTitle(), Panel: panel})
 }
@@ -67,6 +132,12 @@ func (app *Application) Refresh() {
 	app.refreshQ <- struct{}{}
 }
 
+// QueueUpdateDraw runs fn on the event loop of the application and redraws
+// the screen, for the updates of views from background goroutines
+func (app *Application) QueueUpdateDraw(fn func()) {
+	app.tviewApp.QueueUpdateDraw(fn)
+}
+
 func (app *Application) ShowPanel(i int) {
 	app.visibleView = i
 }
@@ -97,6 +168,22 @@ func (app *Application) setup(ctx context.Context) error {
 	// continue setup rest of UI
 	app.panel.Layout(app.pages)
 
+	app.drawHeader()
+	app.panel.DrawFooter(app.getPageTitles()[app.visibleView])
+
+	if err := app.BindKeys(); err != nil {
+		return fmt.Errorf("keymap: %s", err)
+	}
+	app.tviewApp.SetInputCapture(app.HandleKey)
+
+	return nil
+}
+
+func (app *Application) drawHeader() {
+	if app.panel.header == nil {
+		return
+	}
+
 	var hdr strings.Builder
 	hdr.WriteString("%c [green]API server: [white]%s [green]Version: [white]%s [green]context: [white]%s [green]User: [white]%s  [green] metrics:")
 	if err := app.GetK8sClient().AssertMetricsAvailable(); err != nil {
@@ -110,37 +197,6 @@ func (app *Application) setup(ctx context.Context) error {
 		hdr.String(),
 		ui.Icons.Rocket, client.RESTConfig().Host, client.GetServerVersion(), client.ClusterContext(), client.Username(),
 	))
-
-	app.panel.DrawFooter(app.getPageTitles()[app.visibleView])
-
-	app.tviewApp.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Key() == tcell.KeyEsc {
-			app.Stop()
-		}
-
-		if event.Key() == tcell.KeyTAB {
-			views := app.pages[0].Panel.GetChildrenViews()
-			app.tabIdx++
-			app.Focus(views[app.tabIdx])
-			if app.tabIdx == len(views)-1 {
-				app.tabIdx = -1
-			}
-		}
-
-		if event.Key() < tcell.KeyF1 || event.Key() > tcell.KeyF12 {
-			return event
-		}
-
-		keyPos := event.Key() - tcell.KeyF1
-		titles := app.getPageTitles()
-		if (keyPos >= 0 || keyPos <= 9) && (int(keyPos) <= len(titles)-1) {
-			app.panel.switchToPage(app.getPageTitles()[keyPos])
-		}
-
-		return event
-	})
-
-	return nil
 }
 
 func (app *Application) Run(ctx context.Context) error {
@@ -24,6 +24,8 @@ type appPanel struct {
 	footer   *tview.Table
 	modals   []tview.Primitive
 	root     *tview.Flex
+	// focused before the help was shown
+	helpFocus tview.Primitive
 }
 
 func newPanel(app *tview.Application) *appPanel {
@@ -138,4 +140,28 @@ func (p *appPanel) switchToPage(title string) {
 
 func (p *appPanel) showModalView(t tview.Primitive) {
 	p.tviewApp.SetRoot(t, false)
-}
\ No newline at end of file
+}
+const helpPage = "help"
+
+// showHelp shows text over the pages until a key is pressed
+func (p *appPanel) showHelp(text string) {
+	view := tview.NewTextView().SetText(text)
+	view.SetBorder(true)
+	view.SetTitle(" Help, press any key to close ")
+	view.SetTitleAlign(tview.AlignLeft)
+	p.helpFocus = p.tviewApp.GetFocus()
+	p.pages.AddPage(helpPage, view, true, true)
+	p.tviewApp.SetFocus(view)
+}
+
+func (p *appPanel) helpShown() bool {
+	return p.pages != nil && p.pages.HasPage(helpPage)
+}
+
+// closeHelp removes the help and focuses the view focused before
+func (p *appPanel) closeHelp() {
+	p.pages.RemovePage(helpPage)
+	if p.helpFocus != nil {
+		p.tviewApp.SetFocus(p.helpFocus)
+	}
+}
@@ -6,11 +6,18 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/config"
 	"github.com/pjy0381/ktop/k8s"
+	"github.com/pjy0381/ktop/session"
+	"github.com/pjy0381/ktop/snapshot"
+	fleetview "github.com/pjy0381/ktop/views/fleet"
+	"github.com/pjy0381/ktop/views/model"
 	"github.com/pjy0381/ktop/views/overview"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
@@ -27,16 +34,70 @@ var (
 
 # Start ktop for a specific namespace and context
 %[1]s --namespace <namespace> --context <context>
+
+# Start ktop for several namespaces
+%[1]s --namespace payments-api,payments-db,payments-jobs
+
+# Start ktop for the namespaces labeled team=payments
+%[1]s --namespace-selector team=payments
+
+# Start ktop for the pods labeled app=web, on worker nodes
+%[1]s -A -l app=web,tier!=cache --node-selector node-role.kubernetes.io/worker
+
+# Start ktop for the running pods only
+%[1]s -A --field-selector status.phase=Running
+
+# Start ktop for several clusters, with a fleet summary page (F2)
+%[1]s --contexts prod-a,prod-b,staging
+
+# Start ktop with the host services declared in a config file
+%[1]s --config ./ktop.yaml
+
+# Start ktop without ssh access, reading scini health from a node-problem-detector condition
+%[1]s --host-probe scini=condition:SciniProblem,etcd=none
+
+# Start ktop sorting the lists by a click on their column headers
+%[1]s --mouse
+
+# Start ktop keeping an hour of usage history in the sparklines
+%[1]s --history-window 1h
+
+# Record a session to a file, then replay it offline
+%[1]s --record incident.ktop
+%[1]s --replay incident.ktop
+
+# Keep the snapshots saved with s on disk, then compare two of them without starting the UI
+%[1]s --snapshot-dir ./snapshots
+%[1]s diff ./snapshots/before.json ./snapshots/after.json
 `
 )
 
+// fleetConnectTimeout is how long startup waits for the clusters of --contexts
+const fleetConnectTimeout = 10 * time.Second
+
 type ktopCmdOptions struct {
-	namespace     string
-	allNamespaces bool
+	namespace         string
+	allNamespaces     bool
+	namespaceSelector string
+	selector          string
+	fieldSelector     string
+	nodeSelector      string
 	context       string
 	kubeconfig    string
 	kubeFlags     *genericclioptions.ConfigFlags
 	page          string // future use
+
+	configFile       string
+	keymapFile       string
+	hostProbes       map[string]string
+	hostProbeTimeout time.Duration
+	etcdEndpoints    []string
+	contexts         []string
+	historyWindow    time.Duration
+	recordFile       string
+	replayFile       string
+	snapshotDir      string
+	mouse            bool
 }
 
 // NewKtopCmd returns a command for ktop
@@ -60,31 +121,182 @@ func NewKtopCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "If true, display metrics for all accessible namespaces")
+	cmd.Flags().StringVar(&o.namespaceSelector, "namespace-selector", "", "Label selector of namespaces to display, in addition to any namespaces listed with --namespace")
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "Label selector of the pods to display, filtered by the apiserver")
+	cmd.Flags().StringVar(&o.fieldSelector, "field-selector", "", "Field selector of the pods to display, filtered by the apiserver")
+	cmd.Flags().StringVar(&o.nodeSelector, "node-selector", "", "Label selector of the nodes to display")
+	cmd.Flags().StringVar(&o.configFile, "config", "", "Path to the ktop config file (default \"${HOME}/.ktop/config.yaml\")")
+	cmd.Flags().StringVar(&o.keymapFile, "keymap", "", "Path to the file binding keys to commands (default \"${HOME}/.ktop/keymap.yaml\")")
+	cmd.Flags().StringToStringVar(&o.hostProbes, "host-probe", nil, "Probe used per host service as service=type, where type is one of ssh, kubelet, condition:<type>[=<healthy status>], runtime, none")
+	cmd.Flags().DurationVar(&o.hostProbeTimeout, "host-probe-timeout", 3*time.Second, "Time to wait for a single host service probe")
+	cmd.Flags().StringSliceVar(&o.etcdEndpoints, "etcd-endpoints", nil, "Etcd client URLs to monitor instead of discovering etcd members from the cluster")
+	cmd.Flags().StringSliceVar(&o.contexts, "contexts", nil, "Kubeconfig contexts of the clusters to watch side by side in multi-cluster mode")
+	cmd.Flags().StringVar(&o.recordFile, "record", "", "Path of a session file recording the summary, node and pod refreshes of the cluster ktop starts on")
+	cmd.Flags().StringVar(&o.replayFile, "replay", "", "Path of a session file to replay offline instead of watching a cluster")
+	cmd.Flags().StringVar(&o.snapshotDir, "snapshot-dir", "", "Directory where the snapshots saved with the s command are also written, to load them in later sessions")
+	cmd.Flags().DurationVar(&o.historyWindow, "history-window", k8s.DefaultHistoryConfig().Window, "Span of the cpu and memory usage history drawn as sparklines")
+	cmd.Flags().BoolVar(&o.mouse, "mouse", false, "Sort the pod and node lists by clicking their column headers, which keeps the terminal from selecting text")
 	o.kubeFlags.AddFlags(cmd.Flags())
+	cmd.AddCommand(newDiffCmd())
 	return cmd
 }
 
+// newDiffCmd returns a command printing the differences between two snapshot files
+func newDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "diff <snapshot-a> <snapshot-b>",
+		Short:        "Prints the pods, nodes and workloads added, removed or changed between two snapshot files",
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			from, err := snapshot.Load(args[0])
+			if err != nil {
+				return fmt.Errorf("ktop: diff: %s", err)
+			}
+			to, err := snapshot.Load(args[1])
+			if err != nil {
+				return fmt.Errorf("ktop: diff: %s", err)
+			}
+			return snapshot.WriteDiff(c.OutOrStdout(), from, to)
+		},
+	}
+}
+
 func (o *ktopCmdOptions) runKtop(c *cobra.Command, args []string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if o.snapshotDir != "" {
+		if err := os.MkdirAll(o.snapshotDir, 0755); err != nil {
+			return fmt.Errorf("ktop: snapshot dir: %s", err)
+		}
+	}
+
+	keymap, err := config.LoadKeymap(o.keymapFile)
+	if err != nil {
+		return fmt.Errorf("ktop: failed to load keymap: %s", err)
+	}
+
+	if o.replayFile != "" {
+		if o.recordFile != "" || len(o.contexts) > 0 {
+			return fmt.Errorf("ktop: --replay cannot be combined with --record or --contexts")
+		}
+		return o.runReplay(ctx, keymap)
+	}
+
 	if o.allNamespaces {
 		o.namespace = k8s.AllNamespaces
 	}
 
-	k8sC, err := k8s.New(o.kubeFlags)
+	cfg, err := config.Load(o.configFile)
+	if err != nil {
+		return fmt.Errorf("ktop: failed to load config: %s", err)
+	}
+
+	proberCfg, err := o.hostProberConfig(cfg, c.Flags().Changed("host-probe-timeout"))
 	if err != nil {
-		return fmt.Errorf("ktop: failed to create Kubernetes client: %s", err)
+		return fmt.Errorf("ktop: host probes: %s", err)
+	}
+	selectors, err := k8s.ParseSelectors(o.selector, o.fieldSelector, o.nodeSelector)
+	if err != nil {
+		return fmt.Errorf("ktop: %s", err)
+	}
+	setup := func(k8sC *k8s.Client) error {
+		if o.allNamespaces || o.namespaceSelector != "" {
+			if err := o.setNamespaceScope(k8sC); err != nil {
+				return err
+			}
+		}
+		k8sC.SetSelectors(selectors)
+		if err := k8sC.Controller().SetHostProberConfig(proberCfg); err != nil {
+			return fmt.Errorf("host probes: %s", err)
+		}
+		if err := k8sC.Controller().SetEtcdConfig(o.etcdConfig(cfg)); err != nil {
+			return fmt.Errorf("etcd: %s", err)
+		}
+		historyCfg := k8s.DefaultHistoryConfig()
+		historyCfg.Window = o.historyWindow
+		if err := k8sC.Controller().SetHistoryConfig(historyCfg); err != nil {
+			return fmt.Errorf("metrics history: %s", err)
+		}
+		return nil
+	}
+
+	var k8sC *k8s.Client
+	var fleet *k8s.Fleet
+	if len(o.contexts) > 0 {
+		fleet = k8s.NewFleet(o.kubeFlags, o.contexts, func(k8sC *k8s.Client) error {
+			if err := setup(k8sC); err != nil {
+				return err
+			}
+			if err := k8sC.AssertCoreAuthz(ctx); err != nil {
+				return err
+			}
+			return k8sC.AssertSelectors(ctx, k8sC.Selectors())
+		})
+		fleet.Connect(fleetConnectTimeout)
+		for _, member := range fleet.Members() {
+			switch {
+			case member.Client() != nil:
+				fmt.Printf("Connected to: %s (%s)\n", member.Client().RESTConfig().Host, member.Context)
+			case member.Err() != nil:
+				fmt.Printf("Cluster down: %s: %s\n", member.Context, member.Err())
+			default:
+				fmt.Printf("Still connecting: %s\n", member.Context)
+			}
+		}
+		first := fleet.FirstConnected()
+		if first == nil {
+			return fmt.Errorf("ktop: none of the contexts %s could be reached", strings.Join(o.contexts, ","))
+		}
+		k8sC = first.Client()
+	} else {
+		k8sC, err = k8s.New(o.kubeFlags)
+		if err != nil {
+			return fmt.Errorf("ktop: failed to create Kubernetes client: %s", err)
+		}
+		fmt.Printf("Connected to: %s\n", k8sC.RESTConfig().Host)
+		if err := setup(k8sC); err != nil {
+			return fmt.Errorf("ktop: %s", err)
+		}
+	}
+
+	if o.recordFile != "" {
+		recorder, err := o.startRecording(k8sC)
+		if err != nil {
+			return fmt.Errorf("ktop: record: %s", err)
+		}
+		defer func() {
+			if err := recorder.Close(); err != nil {
+				fmt.Printf("record: %s\n", err)
+			}
+		}()
 	}
-	fmt.Printf("Connected to: %s\n", k8sC.RESTConfig().Host)
 
 	app := application.New(k8sC)
+	app.SetKeyBindings(keymap.Keys)
+	app.EnableMouse(o.mouse)
 	app.WelcomeBanner()
-	app.AddPage(overview.New(app, "Overview"))
+	page := overview.New(app, "Overview")
+	page.SetSnapshotDir(o.snapshotDir)
+	restoreSort(page)
+	if cfg.Columns != nil {
+		if err := page.SetColumns(cfg.Columns.Pods, cfg.Columns.Nodes); err != nil {
+			return fmt.Errorf("ktop: config: %s", err)
+		}
+	}
+	app.AddPage(page)
+	if fleet != nil {
+		app.SetFleet(fleet)
+		app.AddPage(fleetview.New(app, "Fleet", "Overview"))
+	}
 
 	if err := k8sC.AssertCoreAuthz(ctx); err != nil {
 		return fmt.Errorf("ktop: %s", err)
 	}
+	if err := k8sC.AssertSelectors(ctx, k8sC.Selectors()); err != nil {
+		return fmt.Errorf("ktop: %s", err)
+	}
 
 	// launch application
 	appErr := make(chan error)
@@ -103,3 +315,145 @@ func (o *ktopCmdOptions) runKtop(c *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// startRecording records the refreshes of k8sC to the --record file
+func (o *ktopCmdOptions) startRecording(k8sC *k8s.Client) (*session.Recorder, error) {
+	recorder, err := session.Create(o.recordFile, session.Header{
+		Context:        k8sC.ClusterContext(),
+		Host:           k8sC.RESTConfig().Host,
+		ServerVersion:  k8sC.GetServerVersion(),
+		User:           k8sC.Username(),
+		Namespaces:     k8sC.Namespaces(),
+		Metrics:        k8sC.AssertMetricsAvailable() == nil,
+		Services:       k8sC.Controller().HostServices(),
+		HistoryWindow:  k8sC.Controller().HistoryConfig().Window,
+		HistorySamples: k8sC.Controller().HistoryConfig().Samples,
+	})
+	if err != nil {
+		return nil, err
+	}
+	k8sC.Controller().SetRecorder(recorder)
+	fmt.Printf("Recording to: %s\n", o.recordFile)
+	return recorder, nil
+}
+
+// runReplay replays the --replay file in the overview page, without connecting to a cluster
+func (o *ktopCmdOptions) runReplay(ctx context.Context, keymap *config.Keymap) error {
+	recorded, err := session.Load(o.replayFile)
+	if err != nil {
+		return fmt.Errorf("ktop: replay: %s", err)
+	}
+	header := recorded.Header
+	k8sC, err := k8s.NewOffline(k8s.OfflineConfig{
+		Context:       header.Context,
+		Host:          header.Host,
+		ServerVersion: header.ServerVersion,
+		User:          header.User,
+		Namespaces:    header.Namespaces,
+		Metrics:       header.Metrics,
+		Services:      header.Services,
+	})
+	if err != nil {
+		return fmt.Errorf("ktop: replay: %s", err)
+	}
+
+	app := application.New(k8sC)
+	app.SetKeyBindings(keymap.Keys)
+	app.EnableMouse(o.mouse)
+	app.WelcomeBanner()
+	fmt.Printf("Replaying: %s (%s, recorded %s)\n", o.replayFile, header.Context, header.Started.Local().Format(time.RFC1123))
+	page := overview.New(app, "Overview")
+	page.SetPlayer(session.NewPlayer(recorded))
+	page.SetSnapshotDir(o.snapshotDir)
+	restoreSort(page)
+	app.AddPage(page)
+	return app.Run(ctx)
+}
+
+// hostProberConfig applies the --host-probe overrides on top of the configured services
+func (o *ktopCmdOptions) hostProberConfig(cfg *config.Config, timeoutSet bool) (k8s.HostProberConfig, error) {
+	proberCfg, err := cfg.HostProberConfig()
+	if err != nil {
+		return proberCfg, err
+	}
+
+	for svc, probeType := range o.hostProbes {
+		probeCfg := k8s.HostProbeConfig{Type: probeType}
+		if cond := strings.TrimPrefix(probeType, k8s.ProbeCondition+":"); cond != probeType {
+			condition, healthy, _ := strings.Cut(cond, "=")
+			probeCfg = k8s.HostProbeConfig{Type: k8s.ProbeCondition, Condition: condition, HealthyStatus: healthy}
+		}
+		if svc == k8s.EtcdService {
+			proberCfg.Etcd = probeCfg
+			continue
+		}
+		found := false
+		for i := range proberCfg.Services {
+			if proberCfg.Services[i].Name == svc {
+				proberCfg.Services[i].Probe = probeCfg
+				found = true
+			}
+		}
+		if !found {
+			proberCfg.Services = append(proberCfg.Services, k8s.HostService{Name: svc, Probe: probeCfg})
+		}
+	}
+
+	if timeoutSet {
+		proberCfg.Etcd.Timeout = o.hostProbeTimeout
+		for i := range proberCfg.Services {
+			proberCfg.Services[i].Probe.Timeout = o.hostProbeTimeout
+		}
+	}
+	return proberCfg, nil
+}
+
+// etcdConfig applies the etcd flags on top of the configured etcd settings
+func (o *ktopCmdOptions) etcdConfig(cfg *config.Config) k8s.EtcdConfig {
+	etcdCfg := cfg.EtcdConfig()
+	if len(o.etcdEndpoints) > 0 {
+		etcdCfg.Discovery = k8s.EtcdDiscoveryEndpoints
+		etcdCfg.Endpoints = o.etcdEndpoints
+	}
+	if _, ok := o.hostProbes[k8s.EtcdService]; ok {
+		etcdCfg.Health = k8s.EtcdHealthProbe
+	}
+	return etcdCfg
+}
+
+// setNamespaceScope applies --all-namespaces and --namespace-selector on top
+// of the namespaces listed with --namespace
+func (o *ktopCmdOptions) setNamespaceScope(k8sC *k8s.Client) error {
+	namespaces := k8sC.Namespaces()
+	if o.allNamespaces {
+		namespaces = nil
+	}
+	selector, err := labels.Parse(o.namespaceSelector)
+	if err != nil {
+		return fmt.Errorf("namespace selector: %s", err)
+	}
+	k8sC.SetNamespaceScope(namespaces, selector)
+	return nil
+}
+
+// restoreSort sorts the lists of page as in the previous session and saves
+// the sorts changed in this one to the session file
+func restoreSort(page *overview.MainPanel) {
+	path, err := config.DefaultSessionPath()
+	if err != nil {
+		return
+	}
+	state, err := config.LoadSession(path)
+	if err != nil {
+		fmt.Printf("session: %s\n", err)
+		state = &config.Session{}
+	}
+	// sorts of unknown keys, such as from another ktop version, are dropped
+	pods, _ := model.ParseSortOrder(state.PodSort, model.SortOrder{}, model.PodSortKeys)
+	nodes, _ := model.ParseSortOrder(state.NodeSort, model.SortOrder{}, model.NodeSortKeys)
+	page.SetSortOrders(pods, nodes)
+	page.SetSortChangedFunc(func(pods, nodes model.SortOrder) error {
+		state.PodSort, state.NodeSort = pods.String(), nodes.String()
+		return state.Save(path)
+	})
+}
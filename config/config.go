@@ -0,0 +1,182 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pjy0381/ktop/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	configDir  = ".ktop"
+	configFile = "config.yaml"
+)
+
+// Config holds the ktop settings read from the config file
+type Config struct {
+	// Services are the host services shown as node columns, in order.
+	// When empty, ktop monitors kubelet, containerd and scini.
+	Services []Service `json:"services,omitempty"`
+	Etcd     *Etcd     `json:"etcd,omitempty"`
+	Probes   Probes    `json:"probes,omitempty"`
+	Columns  *Columns  `json:"columns,omitempty"`
+}
+
+// Columns lists the ids of the columns of the pod and node lists, in order.
+// An empty list draws the default columns.
+type Columns struct {
+	Pods  []string `json:"pods,omitempty"`
+	Nodes []string `json:"nodes,omitempty"`
+}
+
+// Service declares a host service and the nodes it runs on
+type Service struct {
+	Name string `json:"name"`
+	// NodeSelector is a label selector, i.e. storage=powerflex, matching the nodes to probe
+	NodeSelector string `json:"nodeSelector,omitempty"`
+	Probe        Probe  `json:"probe"`
+}
+
+// Probe selects the backend used to check a service
+type Probe struct {
+	Type          string          `json:"type"`
+	Timeout       metav1.Duration `json:"timeout,omitempty"`
+	User          string          `json:"user,omitempty"`
+	Port          int             `json:"port,omitempty"`
+	Condition     string          `json:"condition,omitempty"`
+	HealthyStatus string          `json:"healthyStatus,omitempty"`
+	Runtime       string          `json:"runtime,omitempty"`
+}
+
+// Etcd selects how etcd members are discovered and checked
+type Etcd struct {
+	Discovery string          `json:"discovery,omitempty"`
+	Endpoints []string        `json:"endpoints,omitempty"`
+	Health    string          `json:"health,omitempty"`
+	CAFile    string          `json:"caFile,omitempty"`
+	CertFile  string          `json:"certFile,omitempty"`
+	KeyFile   string          `json:"keyFile,omitempty"`
+	Timeout   metav1.Duration `json:"timeout,omitempty"`
+	// Probe is the host probe used when health is "probe"
+	Probe *Probe `json:"probe,omitempty"`
+}
+
+// Probes holds the limits shared by all probes
+type Probes struct {
+	Concurrency int             `json:"concurrency,omitempty"`
+	TTL         metav1.Duration `json:"ttl,omitempty"`
+}
+
+// DefaultPath returns $HOME/.ktop/config.yaml
+func DefaultPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, configDir, configFile), nil
+}
+
+// Load reads the config file at path. An empty path loads the file at
+// DefaultPath, if any, and falls back to an empty config.
+func Load(path string) (*Config, error) {
+	explicit := path != ""
+	if !explicit {
+		defPath, err := DefaultPath()
+		if err != nil {
+			return &Config{}, nil
+		}
+		path = defPath
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return &Config{}, nil
+		}
+		return nil, err
+	}
+
+	var cfg Config
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// HostProberConfig converts the configured services into the host prober
+// configuration, keeping ktop defaults for anything left unset.
+func (c *Config) HostProberConfig() (k8s.HostProberConfig, error) {
+	cfg := k8s.DefaultHostProberConfig()
+	if c.Probes.Concurrency > 0 {
+		cfg.Concurrency = c.Probes.Concurrency
+	}
+	if c.Probes.TTL.Duration > 0 {
+		cfg.TTL = c.Probes.TTL.Duration
+	}
+	if c.Etcd != nil && c.Etcd.Probe != nil {
+		cfg.Etcd = c.Etcd.Probe.hostProbeConfig()
+	}
+	if len(c.Services) == 0 {
+		return cfg, nil
+	}
+
+	cfg.Services = nil
+	for _, svc := range c.Services {
+		selector := labels.Everything()
+		if svc.NodeSelector != "" {
+			sel, err := labels.Parse(svc.NodeSelector)
+			if err != nil {
+				return cfg, fmt.Errorf("service %s: node selector: %w", svc.Name, err)
+			}
+			selector = sel
+		}
+		cfg.Services = append(cfg.Services, k8s.HostService{
+			Name:         svc.Name,
+			Probe:        svc.Probe.hostProbeConfig(),
+			NodeSelector: selector,
+		})
+	}
+	return cfg, nil
+}
+
+// EtcdConfig converts the etcd settings, keeping ktop defaults for anything left unset
+func (c *Config) EtcdConfig() k8s.EtcdConfig {
+	cfg := k8s.DefaultEtcdConfig()
+	if c.Probes.TTL.Duration > 0 {
+		cfg.TTL = c.Probes.TTL.Duration
+	}
+	if c.Etcd == nil {
+		return cfg
+	}
+	if c.Etcd.Discovery != "" {
+		cfg.Discovery = c.Etcd.Discovery
+	}
+	if c.Etcd.Health != "" {
+		cfg.Health = c.Etcd.Health
+	}
+	if c.Etcd.Timeout.Duration > 0 {
+		cfg.Timeout = c.Etcd.Timeout.Duration
+	}
+	cfg.Endpoints = c.Etcd.Endpoints
+	cfg.CAFile = c.Etcd.CAFile
+	cfg.CertFile = c.Etcd.CertFile
+	cfg.KeyFile = c.Etcd.KeyFile
+	return cfg
+}
+
+func (p Probe) hostProbeConfig() k8s.HostProbeConfig {
+	return k8s.HostProbeConfig{
+		Type:          p.Type,
+		Timeout:       p.Timeout.Duration,
+		User:          p.User,
+		Port:          p.Port,
+		Condition:     p.Condition,
+		HealthyStatus: p.HealthyStatus,
+		Runtime:       p.Runtime,
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pjy0381/ktop/k8s"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestLoadHostProberConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+services:
+- name: ceph
+  nodeSelector: storage=ceph
+  probe:
+    type: condition
+    condition: CephProblem
+- name: kubelet
+  probe:
+    type: kubelet
+    timeout: 5s
+etcd:
+  discovery: endpoints
+  endpoints:
+  - https://10.0.0.10:2379
+  health: probe
+  probe:
+    type: none
+probes:
+  concurrency: 4
+columns:
+  pods: [name, qos, cpu]
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected load error: %s", err)
+	}
+	proberCfg, err := cfg.HostProberConfig()
+	if err != nil {
+		t.Fatalf("unexpected conversion error: %s", err)
+	}
+	if cfg.Columns == nil || len(cfg.Columns.Pods) != 3 || cfg.Columns.Pods[1] != "qos" || cfg.Columns.Nodes != nil {
+		t.Errorf("expecting pod columns [name qos cpu] and default node columns, got %+v", cfg.Columns)
+	}
+
+	if len(proberCfg.Services) != 2 || proberCfg.Services[0].Name != "ceph" || proberCfg.Services[1].Name != "kubelet" {
+		t.Fatalf("expecting services [ceph kubelet] in order, got %+v", proberCfg.Services)
+	}
+	if !proberCfg.Services[0].NodeSelector.Matches(labels.Set{"storage": "ceph"}) {
+		t.Errorf("expecting ceph selector to match storage=ceph")
+	}
+	if proberCfg.Services[0].NodeSelector.Matches(labels.Set{"storage": "longhorn"}) {
+		t.Errorf("expecting ceph selector not to match storage=longhorn")
+	}
+	if proberCfg.Services[1].Probe.Timeout.String() != "5s" {
+		t.Errorf("expecting kubelet timeout 5s, got %s", proberCfg.Services[1].Probe.Timeout)
+	}
+	if proberCfg.Etcd.Type != k8s.ProbeNone {
+		t.Errorf("expecting etcd probe %s, got %s", k8s.ProbeNone, proberCfg.Etcd.Type)
+	}
+	etcdCfg := cfg.EtcdConfig()
+	if etcdCfg.Discovery != k8s.EtcdDiscoveryEndpoints || len(etcdCfg.Endpoints) != 1 || etcdCfg.Health != k8s.EtcdHealthProbe {
+		t.Errorf("unexpected etcd config %+v", etcdCfg)
+	}
+	if proberCfg.Concurrency != 4 {
+		t.Errorf("expecting concurrency 4, got %d", proberCfg.Concurrency)
+	}
+}
+
+func TestLoadMissingExplicitFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expecting error for missing config file")
+	}
+}
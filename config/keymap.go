@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+const keymapFile = "keymap.yaml"
+
+// Keymap holds the key bindings read from the keymap file, on top of the
+// ktop defaults
+type Keymap struct {
+	// Keys binds key names, such as j, Ctrl-R or F3, to command lines such
+	// as "pods -cpu". An empty line removes the default binding of a key.
+	Keys map[string]string `json:"keys,omitempty"`
+}
+
+// DefaultKeymapPath returns $HOME/.ktop/keymap.yaml
+func DefaultKeymapPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, configDir, keymapFile), nil
+}
+
+// LoadKeymap reads the keymap file at path. An empty path loads the file at
+// DefaultKeymapPath, if any, and falls back to an empty keymap.
+func LoadKeymap(path string) (*Keymap, error) {
+	explicit := path != ""
+	if !explicit {
+		defPath, err := DefaultKeymapPath()
+		if err != nil {
+			return &Keymap{}, nil
+		}
+		path = defPath
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return &Keymap{}, nil
+		}
+		return nil, err
+	}
+
+	var keymap Keymap
+	if err := yaml.UnmarshalStrict(data, &keymap); err != nil {
+		return nil, fmt.Errorf("keymap %s: %w", path, err)
+	}
+	return &keymap, nil
+}
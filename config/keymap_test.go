@@ -0,0 +1,38 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadKeymap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keymap.yaml")
+	if _, err := LoadKeymap(path); err == nil {
+		t.Error("expecting an error for a missing explicit keymap file")
+	}
+
+	data := `
+keys:
+  "?": help
+  Ctrl-P: pods -cpu
+  Esc: ""
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	keymap, err := LoadKeymap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keymap.Keys) != 3 || keymap.Keys["Ctrl-P"] != "pods -cpu" || keymap.Keys["Esc"] != "" {
+		t.Errorf("unexpected keys %v", keymap.Keys)
+	}
+
+	if err := os.WriteFile(path, []byte("bindings:\n  j: down\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadKeymap(path); err == nil {
+		t.Error("expecting an error for an unknown field")
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+const sessionFile = "session.yaml"
+
+// Session holds the view settings ktop keeps from one run to the next. Unlike
+// the config file, it is written by ktop.
+type Session struct {
+	// PodSort and NodeSort are sort arguments such as -cpu, see model.ParseSortOrder
+	PodSort  string `json:"podSort,omitempty"`
+	NodeSort string `json:"nodeSort,omitempty"`
+}
+
+// DefaultSessionPath returns $HOME/.ktop/session.yaml
+func DefaultSessionPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, configDir, sessionFile), nil
+}
+
+// LoadSession reads the session file at path, returning an empty session
+// when the file does not exist yet
+func LoadSession(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Session{}, nil
+		}
+		return nil, err
+	}
+
+	var session Session
+	if err := yaml.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("session %s: %w", path, err)
+	}
+	return &session, nil
+}
+
+// Save writes the session to the file at path, creating its directory
+func (s *Session) Save(path string) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveAndLoadSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ktop", "session.yaml")
+	session, err := LoadSession(path)
+	if err != nil || *session != (Session{}) {
+		t.Fatalf("expecting an empty session before the file exists, got %+v %v", session, err)
+	}
+
+	session.PodSort, session.NodeSort = "-cpu", "name"
+	if err := session.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSession(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *loaded != *session {
+		t.Errorf("expecting %+v, got %+v", session, loaded)
+	}
+}
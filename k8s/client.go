@@ -3,13 +3,17 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/pjy0381/ktop/views/model"
 	appsV1 "k8s.io/api/apps/v1"
 	authzV1 "k8s.io/api/authorization/v1"
 	batchV1 "k8s.io/api/batch/v1"
+	storageV1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -35,21 +39,23 @@ var (
 		"pods":                   {Group: "", Version: "v1", Resource: "pods"},
 		"persistentvolumes":      {Group: "", Version: "v1", Resource: "persistentvolumes"},
 		"persistentvolumeclaims": {Group: "", Version: "v1", Resource: "persistentvolumeclaims"},
+		"events":                 {Group: "", Version: "v1", Resource: "events"},
 		"deployments":            {Group: appsV1.GroupName, Version: "v1", Resource: "deployments"},
 		"daemonsets":             {Group: appsV1.GroupName, Version: "v1", Resource: "daemonsets"},
 		"replicasets":            {Group: appsV1.GroupName, Version: "v1", Resource: "replicasets"},
 		"statefulsets":           {Group: appsV1.GroupName, Version: "v1", Resource: "statefulsets"},
 		"jobs":                   {Group: batchV1.GroupName, Version: "v1", Resource: "jobs"},
 		"cronjobs":               {Group: batchV1.GroupName, Version: "v1", Resource: "cronjobs"},
+		"storageclasses":         {Group: storageV1.GroupName, Version: "v1", Resource: "storageclasses"},
 	}
-
-	authzdTable = make(map[string]bool)
 )
 
 type Client struct {
 	sync.RWMutex
 	clusterVersion    *version.Info
-	namespace         string
+	namespaces        []string
+	namespaceSelector labels.Selector
+	selectors         Selectors
 	config            *restclient.Config
 	apiConfig         api.Config
 	clusterContext    string
@@ -61,6 +67,50 @@ type Client struct {
 	refreshTimeout    time.Duration
 	controller        *Controller
 	selectedNode	  string
+	authzd            map[string]bool // authorization results by resource/namespace/verb
+	flags             *genericclioptions.ConfigFlags
+
+	// offline clients stand for a cluster without connecting to it
+	offline        bool
+	offlineMetrics bool
+}
+
+// OfflineConfig describes a cluster that is not connected to, such as the
+// cluster a replayed session was recorded from
+type OfflineConfig struct {
+	Context       string
+	Host          string
+	ServerVersion string
+	User          string
+	Namespaces    []string
+	Metrics       bool     // whether metrics were available
+	Services      []string // host services shown as node columns
+}
+
+// NewOffline returns a client standing for the cluster of cfg without
+// connecting to it. Its controller lists nothing and must not be started.
+func NewOffline(cfg OfflineConfig) (*Client, error) {
+	client := &Client{
+		clusterVersion: &version.Info{GitVersion: cfg.ServerVersion},
+		namespaces:     cfg.Namespaces,
+		config:         &restclient.Config{Host: cfg.Host},
+		clusterContext: cfg.Context,
+		username:       cfg.User,
+		offline:        true,
+		offlineMetrics: cfg.Metrics,
+	}
+	client.controller = newController(client)
+
+	proberCfg := DefaultHostProberConfig()
+	proberCfg.Etcd = HostProbeConfig{Type: ProbeNone}
+	proberCfg.Services = nil
+	for _, name := range cfg.Services {
+		proberCfg.Services = append(proberCfg.Services, HostService{Name: name, Probe: HostProbeConfig{Type: ProbeNone}})
+	}
+	if err := client.controller.SetHostProberConfig(proberCfg); err != nil {
+		return nil, err
+	}
+	return client, nil
 }
 
 func New(flags *genericclioptions.ConfigFlags) (*Client, error) {
@@ -96,8 +146,14 @@ func New(flags *genericclioptions.ConfigFlags) (*Client, error) {
 		return nil, err
 	}
 
+	// --context overrides the current context of the kubeconfig
+	contextName := apiCfg.CurrentContext
+	if flags.Context != nil && *flags.Context != "" {
+		contextName = *flags.Context
+	}
+
 	username := "<empty>"
-	currCtx, ok := apiCfg.Contexts[apiCfg.CurrentContext]
+	currCtx, ok := apiCfg.Contexts[contextName]
 	if ok {
 		username = currCtx.AuthInfo
 	}
@@ -110,10 +166,11 @@ func New(flags *genericclioptions.ConfigFlags) (*Client, error) {
 
 	client := &Client{
 		clusterVersion: version,
-		namespace:      namespace,
+		namespaces:     ParseNamespaces(namespace),
 		config:         config,
 		apiConfig:      apiCfg,
-		clusterContext: apiCfg.CurrentContext,
+		clusterContext: contextName,
+		flags:          flags,
 		username:       username,
 		kubeClient:     kubeClient,
 		discoClient:    disco,
@@ -123,13 +180,101 @@ func New(flags *genericclioptions.ConfigFlags) (*Client, error) {
 	client.controller = newController(client)
 	return client, nil
 }
+// ForContext returns a client for the kubeconfig context name, watching the
+// same namespaces with the same host service probes and history as k8s
+func (k8s *Client) ForContext(name string) (*Client, error) {
+	if k8s.flags == nil {
+		return nil, fmt.Errorf("context %s: client has no configuration flags", name)
+	}
+	if _, ok := k8s.apiConfig.Contexts[name]; !ok {
+		return nil, fmt.Errorf("context %s not found", name)
+	}
+
+	client, err := New(ContextFlags(k8s.flags, name))
+	if err != nil {
+		return nil, err
+	}
+	client.SetNamespaceScope(k8s.namespaces, k8s.namespaceSelector)
+	client.SetSelectors(k8s.selectors)
+	if err := client.controller.SetHostProberConfig(k8s.controller.hostProberConfig); err != nil {
+		return nil, err
+	}
+	if err := client.controller.SetEtcdConfig(k8s.controller.etcdConfig); err != nil {
+		return nil, err
+	}
+	if err := client.controller.SetHistoryConfig(k8s.controller.history.cfg); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// GetContextModels returns the contexts of the kubeconfig sorted by name
+func (k8s *Client) GetContextModels() []model.ContextModel {
+	models := make([]model.ContextModel, 0, len(k8s.apiConfig.Contexts))
+	for name, kubeCtx := range k8s.apiConfig.Contexts {
+		models = append(models, model.ContextModel{
+			Name:      name,
+			Cluster:   kubeCtx.Cluster,
+			User:      kubeCtx.AuthInfo,
+			Namespace: kubeCtx.Namespace,
+			Current:   name == k8s.clusterContext,
+		})
+	}
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].Name < models[j].Name
+	})
+	return models
+}
+
+// NewNamespace watches the comma-separated namespaces in name, or all
+// namespaces when name is empty, and drops any namespace selector
 func (k8s *Client) NewNamespace(name string) string {
-	k8s.namespace = name
-        return k8s.namespace
+	k8s.SetNamespaceScope(ParseNamespaces(name), nil)
+	return k8s.Namespace()
+}
+
+// SetNamespaceScope watches the listed namespaces and the namespaces whose
+// labels match selector. Both empty means all namespaces.
+func (k8s *Client) SetNamespaceScope(namespaces []string, selector labels.Selector) {
+	if selector != nil && selector.Empty() {
+		selector = nil
+	}
+	k8s.namespaces = namespaces
+	k8s.namespaceSelector = selector
 }
 
+// Namespace returns the namespace the informers are scoped to, which is
+// AllNamespaces unless exactly one namespace is watched
 func (k8s *Client) Namespace() string {
-	return k8s.namespace
+	if len(k8s.namespaces) == 1 && k8s.namespaceSelector == nil {
+		return k8s.namespaces[0]
+	}
+	return AllNamespaces
+}
+
+// Namespaces returns the explicitly watched namespaces
+func (k8s *Client) Namespaces() []string {
+	return k8s.namespaces
+}
+
+// NamespaceSelector returns the label selector of watched namespaces, or nil
+func (k8s *Client) NamespaceSelector() labels.Selector {
+	return k8s.namespaceSelector
+}
+
+// SetSelectors narrows the watched pods and nodes to those selected by sel
+func (k8s *Client) SetSelectors(sel Selectors) {
+	k8s.selectors = sel.normalize()
+}
+
+// Selectors returns the selectors of the watched pods and nodes
+func (k8s *Client) Selectors() Selectors {
+	return k8s.selectors
+}
+
+// Offline reports whether the client stands for a cluster it is not connected to
+func (k8s *Client) Offline() bool {
+	return k8s.offline
 }
 
 func (k8s *Client) RESTConfig() *restclient.Config {
@@ -151,6 +296,12 @@ func (k8s *Client) GetServerVersion() string {
 // AssertMetricsAvailable checks for available metrics server every 10th invocation.
 // Otherwise, it returns the last known registration state of metrics server.
 func (k8s *Client) AssertMetricsAvailable() error {
+	if k8s.offline {
+		if !k8s.offlineMetrics {
+			return fmt.Errorf("metrics were not available")
+		}
+		return nil
+	}
 	if k8s.metricsAvailCount != 0 {
 		if k8s.metricsAvailCount%10 != 0 {
 			k8s.metricsAvailCount++
@@ -210,22 +361,25 @@ func (k8s *Client) IsAuthz(ctx context.Context, resource string, verbs []string)
 		}
 	}
 
+	if k8s.authzd == nil {
+		k8s.authzd = make(map[string]bool)
+	}
 	arClient := k8s.kubeClient.AuthorizationV1().SelfSubjectAccessReviews()
 	result := true
 	for _, verb := range verbs {
 		key := fmt.Sprintf("%s/%s/%s", gvr.String(), k8s.Namespace(), verb)
-		if authzd, ok := authzdTable[key]; ok {
+		if authzd, ok := k8s.authzd[key]; ok {
 			result = result && authzd
 			continue
 		}
 		ar := makeAccessReview(gvr, verb)
 		arResult, err := arClient.Create(ctx, ar, metav1.CreateOptions{})
 		if err != nil {
-			delete(authzdTable, key)
+			delete(k8s.authzd, key)
 			return false, err
 		}
 		allowed := arResult.Status.Allowed
-		authzdTable[key] = allowed
+		k8s.authzd[key] = allowed
 		result = result && allowed
 	}
 
@@ -3,21 +3,44 @@ package k8s
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/pjy0381/ktop/views/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
 	appsV1Informers "k8s.io/client-go/informers/apps/v1"
 	batchV1Informers "k8s.io/client-go/informers/batch/v1"
 	coreV1Informers "k8s.io/client-go/informers/core/v1"
+	storageV1Informers "k8s.io/client-go/informers/storage/v1"
 	"k8s.io/client-go/tools/cache"
 )
 
 type RefreshNodesFunc func(ctx context.Context, items []model.NodeModel) error
 type RefreshPodsFunc func(ctx context.Context, items []model.PodModel) error
 type RefreshSummaryFunc func(ctx context.Context, items model.ClusterSummary) error
+type RefreshControlPlaneFunc func(ctx context.Context, item model.ControlPlaneModel) error
+type RefreshEventsFunc func(ctx context.Context, items []model.EventModel) error
+type RefreshWorkloadsFunc func(ctx context.Context, items []model.WorkloadModel) error
+type RefreshStorageFunc func(ctx context.Context, item model.StorageModel) error
+
+// Recorder receives the models refreshed by a controller, such as to write them to a session file
+type Recorder interface {
+	RecordSummary(summary model.ClusterSummary)
+	RecordNodes(nodes []model.NodeModel)
+	RecordPods(pods []model.PodModel)
+}
+
+// ErrControllerStarted is returned when starting a controller that is already running
+var ErrControllerStarted = errors.New("controller already started")
+
+// coreSyncTimeout bounds the wait for the namespaces, nodes and pods to sync
+// when starting, informers retrying the lists the apiserver keeps failing
+const coreSyncTimeout = time.Minute
 
 type Controller struct {
+	sync.RWMutex
 	client *Client
 
 	nodeMetricsInformer *NodeMetricsInformer
@@ -27,6 +50,7 @@ type Controller struct {
 	podInformer         coreV1Informers.PodInformer
 	pvInformer          coreV1Informers.PersistentVolumeInformer
 	pvcInformer         coreV1Informers.PersistentVolumeClaimInformer
+	eventInformer       coreV1Informers.EventInformer
 
 	jobInformer     batchV1Informers.JobInformer
 	cronJobInformer batchV1Informers.CronJobInformer
@@ -36,16 +60,76 @@ type Controller struct {
 	replicaSetInformer  appsV1Informers.ReplicaSetInformer
 	statefulSetInformer appsV1Informers.StatefulSetInformer
 
+	storageClassInformer storageV1Informers.StorageClassInformer
+
 	nodeRefreshFunc    RefreshNodesFunc
 	podRefreshFunc     RefreshPodsFunc
 	summaryRefreshFunc RefreshSummaryFunc
+	controlPlaneRefreshFunc RefreshControlPlaneFunc
+	eventRefreshFunc        RefreshEventsFunc
+	workloadRefreshFunc     RefreshWorkloadsFunc
+	storageRefreshFunc      RefreshStorageFunc
+	recorder                Recorder
+
+	hostProber       *HostProber
+	hostProberConfig HostProberConfig
+	etcdMonitor      *etcdMonitor
+	etcdConfig       EtcdConfig
+	controlPlane *model.ControlPlaneModel
+	summary      *model.ClusterSummary
+	summaryTime  time.Time
+	history      *metricsHistory
+
+	// models kept up to date from informer events, owned by the refresh loop
+	dirty      *dirtySet
+	podModels  map[string]model.PodModel
+	nodeModels map[string]model.NodeModel
+
+	// lifecycle of the running informers and refresh loops, guarded by runLock
+	runLock sync.Mutex
+	parent  context.Context
+	resync  time.Duration
+	cancel  context.CancelFunc
+	loops   sync.WaitGroup
 }
 
 func newController(client *Client) *Controller {
-	ctrl := &Controller{client: client}
+	ctrl := &Controller{client: client, dirty: newDirtySet()}
+	ctrl.hostProberConfig = DefaultHostProberConfig()
+	ctrl.etcdConfig = DefaultEtcdConfig()
+	ctrl.hostProber, _ = NewHostProber(client, ctrl.hostProberConfig)
+	ctrl.etcdMonitor, _ = newEtcdMonitor(ctrl, ctrl.etcdConfig)
+	ctrl.history, _ = newMetricsHistory(DefaultHistoryConfig())
 	return ctrl
 }
 
+// SetEtcdConfig replaces how etcd members are discovered and checked
+func (c *Controller) SetEtcdConfig(cfg EtcdConfig) error {
+	monitor, err := newEtcdMonitor(c, cfg)
+	if err != nil {
+		return err
+	}
+	c.etcdMonitor = monitor
+	c.etcdConfig = cfg
+	return nil
+}
+
+// HostServices returns the names of the host services monitored on nodes
+func (c *Controller) HostServices() []string {
+	return c.hostProber.Services()
+}
+
+// SetHostProberConfig replaces the probes used to check host services
+func (c *Controller) SetHostProberConfig(cfg HostProberConfig) error {
+	prober, err := NewHostProber(c.client, cfg)
+	if err != nil {
+		return err
+	}
+	c.hostProber = prober
+	c.hostProberConfig = cfg
+	return nil
+}
+
 func (c *Controller) SetNodeRefreshFunc(fn RefreshNodesFunc) *Controller {
 	c.nodeRefreshFunc = fn
 	return c
@@ -60,23 +144,172 @@ func (c *Controller) SetClusterSummaryRefreshFunc(fn RefreshSummaryFunc) *Contro
 	return c
 }
 
+func (c *Controller) SetControlPlaneRefreshFunc(fn RefreshControlPlaneFunc) *Controller {
+	c.controlPlaneRefreshFunc = fn
+	return c
+}
+
+func (c *Controller) SetEventRefreshFunc(fn RefreshEventsFunc) *Controller {
+	c.eventRefreshFunc = fn
+	return c
+}
+
+func (c *Controller) SetWorkloadRefreshFunc(fn RefreshWorkloadsFunc) *Controller {
+	c.workloadRefreshFunc = fn
+	return c
+}
+
+func (c *Controller) SetStorageRefreshFunc(fn RefreshStorageFunc) *Controller {
+	c.storageRefreshFunc = fn
+	return c
+}
+
+// SetRecorder sets the recorder of the summary, node and pod refreshes, nil to stop recording
+func (c *Controller) SetRecorder(recorder Recorder) *Controller {
+	c.recorder = recorder
+	return c
+}
+
+// Start launches the informers and refresh loops and waits for the core
+// resources to sync. The controller runs until ctx is done or Stop is called.
 func (c *Controller) Start(ctx context.Context, resync time.Duration) error {
 	if ctx == nil {
 		return errors.New("context cannot be nil")
 	}
 
+	c.runLock.Lock()
+	defer c.runLock.Unlock()
+	if c.cancel != nil {
+		return ErrControllerStarted
+	}
+	c.parent = ctx
+	c.resync = resync
+	return c.start()
+}
+
+// Stop cancels the informers and refresh loops and waits for the loops to exit
+func (c *Controller) Stop() {
+	c.runLock.Lock()
+	defer c.runLock.Unlock()
+	c.stop()
+}
+
+// Restart stops the controller and starts it again watching namespace, which
+// is a comma-separated list or "" for all namespaces. If the user is not authorized for the new
+// namespace, or the controller fails to start on it, the controller is restarted on the
+// previous one and an error is returned.
+func (c *Controller) Restart(namespace string) error {
+	c.runLock.Lock()
+	defer c.runLock.Unlock()
+	if c.parent == nil {
+		return errors.New("controller not started")
+	}
+
+	c.stop()
+	previous, previousSelector := c.client.Namespaces(), c.client.NamespaceSelector()
+	c.client.NewNamespace(namespace)
+	err := c.client.AssertCoreAuthz(c.parent)
+	if err == nil {
+		if err = c.start(); err == nil {
+			return nil
+		}
+	}
+	c.client.SetNamespaceScope(previous, previousSelector)
+	if startErr := c.start(); startErr != nil {
+		return fmt.Errorf("namespace %q: %w, restarting with the previous namespaces: %s", namespace, err, startErr)
+	}
+	return fmt.Errorf("namespace %q: %w", namespace, err)
+}
+
+// RestartSelecting stops the controller and starts it again watching the
+// pods and nodes selected by sel. A controller not started only
+// records sel for when it starts. Selectors rejected by the apiserver leave
+// the controller running, and selectors failing to start it are rolled back.
+func (c *Controller) RestartSelecting(sel Selectors) error {
+	c.runLock.Lock()
+	defer c.runLock.Unlock()
+	if c.parent == nil {
+		c.client.SetSelectors(sel)
+		return nil
+	}
+	if err := c.client.AssertSelectors(c.parent, sel); err != nil {
+		return err
+	}
+
+	c.stop()
+	previous := c.client.Selectors()
+	c.client.SetSelectors(sel)
+	if err := c.start(); err != nil {
+		c.client.SetSelectors(previous)
+		if startErr := c.start(); startErr != nil {
+			return startErr
+		}
+		return fmt.Errorf("selectors %s: %w", sel, err)
+	}
+	return nil
+}
+
+// Resync redraws all node and pod models through the refresh funcs, such as
+// after the funcs were replaced to display this controller
+func (c *Controller) Resync() {
+	c.dirty.markAllNodes()
+	c.dirty.markAllPods()
+}
+
+func (c *Controller) stop() {
+	if c.cancel == nil {
+		return
+	}
+	c.cancel()
+	c.cancel = nil
+	c.loops.Wait()
+
+	c.nodeMetricsInformer = nil
+	c.podMetricsInformer = nil
+	c.dirty.reset()
+	c.Lock()
+	c.controlPlane = nil
+	c.summary = nil
+	c.Unlock()
+}
+
+func (c *Controller) start() error {
+	ctx, cancel := context.WithCancel(c.parent)
+	c.cancel = cancel
+	if err := c.run(ctx, c.resync); err != nil {
+		c.cancel = nil
+		cancel()
+		c.loops.Wait()
+		return err
+	}
+	return nil
+}
+
+// runLoop runs fn in a goroutine tracked by Stop
+func (c *Controller) runLoop(ctx context.Context, fn func(stopCh <-chan struct{})) {
+	c.loops.Add(1)
+	go func() {
+		defer c.loops.Done()
+		fn(ctx.Done())
+	}()
+}
+
+func (c *Controller) run(ctx context.Context, resync time.Duration) error {
 	// initialize
 	if err := c.client.AssertMetricsAvailable(); err == nil {
 		c.nodeMetricsInformer = NewNodeMetricsInformer(c.client.metricsClient, resync)
 		nodeMetricsInformerHasSynced := c.nodeMetricsInformer.Informer().HasSynced
 
-		c.podMetricsInformer = NewPodMetricsInformer(c.client.metricsClient, resync, c.client.namespace)
+		c.podMetricsInformer = NewPodMetricsInformer(c.client.metricsClient, resync, c.client.Namespace())
 		podMetricsInformerHasSynced := c.podMetricsInformer.Informer().HasSynced
 
-		go c.nodeMetricsInformer.Informer().Run(ctx.Done())
-		go c.podMetricsInformer.Informer().Run(ctx.Done())
+		c.runLoop(ctx, c.nodeMetricsInformer.Informer().Run)
+		c.runLoop(ctx, c.podMetricsInformer.Informer().Run)
 
 		if ok := cache.WaitForCacheSync(ctx.Done(), nodeMetricsInformerHasSynced, podMetricsInformerHasSynced); !ok {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			panic("metrics resources failed to sync [nodes, pods, containers]")
 		}
 
@@ -84,12 +317,12 @@ func (c *Controller) Start(ctx context.Context, resync time.Duration) error {
 
 
 	// 네임스페이
-	// initialize informer factories
-	var factory informers.SharedInformerFactory
-	if c.client.namespace == AllNamespaces {
-		factory = informers.NewSharedInformerFactory(c.client.kubeClient, resync)
-	} else {
-		factory = informers.NewSharedInformerFactoryWithOptions(c.client.kubeClient, resync, informers.WithNamespace(c.client.namespace))
+	// initialize informer factories, pods coming from a factory listing only
+	// the selected pods when selectors are set
+	factory := c.newInformerFactory(resync, nil)
+	podFactory := factory
+	if selectors := c.client.Selectors(); selectors.serverSide() {
+		podFactory = c.newInformerFactory(resync, selectors.tweakListOptions())
 	}
 
 	// NOTE: the followings captures each informer
@@ -102,12 +335,17 @@ func (c *Controller) Start(ctx context.Context, resync time.Duration) error {
 	namespaceHasSynced := c.namespaceInformer.Informer().HasSynced
 	c.nodeInformer = coreInformers.Nodes()
 	nodeHasSynced := c.nodeInformer.Informer().HasSynced
-	c.podInformer = coreInformers.Pods()
+	c.podInformer = podFactory.Core().V1().Pods()
+	if err := c.podInformer.Informer().AddIndexers(cache.Indexers{podNodeIndex: podNodeIndexFunc}); err != nil {
+		return err
+	}
 	podHasSynced := c.podInformer.Informer().HasSynced
 	c.pvInformer = coreInformers.PersistentVolumes()
 	pvHasSynced := c.pvInformer.Informer().HasSynced
 	c.pvcInformer = coreInformers.PersistentVolumeClaims()
 	pvcHasSynced := c.pvcInformer.Informer().HasSynced
+	c.eventInformer = coreInformers.Events()
+	eventHasSynced := c.eventInformer.Informer().HasSynced
 
 	// Apps/v1 Informers
 	appsInformers := factory.Apps().V1()
@@ -127,38 +365,69 @@ func (c *Controller) Start(ctx context.Context, resync time.Duration) error {
 	c.cronJobInformer = batchInformers.CronJobs()
 	cronJobHasSynced := c.cronJobInformer.Informer().HasSynced
 
+	// Storage informers
+	storageInformers := factory.Storage().V1()
+	c.storageClassInformer = storageInformers.StorageClasses()
+	storageClassHasSynced := c.storageClassInformer.Informer().HasSynced
+
+	c.installEventHandlers(ctx)
 	factory.Start(ctx.Done())
+	podFactory.Start(ctx.Done())
 
-	// wait immediately for core resources to syn
-	// wait for core resources to sync
-	if ok := cache.WaitForCacheSync(ctx.Done(),
+	// wait for core resources to sync, informers failing to list retrying forever
+	syncCtx, cancelSync := context.WithTimeout(ctx, coreSyncTimeout)
+	defer cancelSync()
+	if ok := cache.WaitForCacheSync(syncCtx.Done(),
 		namespaceHasSynced,
 		nodeHasSynced,
 		podHasSynced,
 	); !ok {
-		panic("core resources failed to sync [namespaces, nodes, pods]")
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("core resources did not sync within %s [namespaces, nodes, pods]", coreSyncTimeout)
 	}
 
 	// defer waiting for non-core resources to sync
+	c.loops.Add(1)
 	go func() {
+		defer c.loops.Done()
 		ok := cache.WaitForCacheSync(ctx.Done(),
 			pvHasSynced,
 			pvcHasSynced,
+			eventHasSynced,
 			deploymentHasSynced,
 			daemonsetHasSynced,
 			replicasetHasSynced,
 			statefulsetHasSynced,
 			jobHasSynced,
 			cronJobHasSynced,
+			storageClassHasSynced,
 		)
-		if !ok {
+		// a stopped controller abandons the sync
+		if !ok && ctx.Err() == nil {
 			panic("resource failed to sync")
 		}
 	}()
 
-	c.setupSummaryHandler(ctx, c.summaryRefreshFunc)
-	c.setupNodeHandler(ctx, c.nodeRefreshFunc)
-	c.installPodsHandler(ctx, c.podRefreshFunc)
+	c.setupModelRefresh(ctx)
+	c.setupControlPlaneHandler(ctx)
+	c.setupEventsHandler(ctx)
+	c.setupWorkloadsHandler(ctx)
+	c.setupStorageHandler(ctx)
 
 	return nil
 }
+
+// newInformerFactory returns an informer factory scoped to the watched
+// namespace, with tweak, when not nil, applied to the list options
+func (c *Controller) newInformerFactory(resync time.Duration, tweak func(*metav1.ListOptions)) informers.SharedInformerFactory {
+	var options []informers.SharedInformerOption
+	if c.client.Namespace() != AllNamespaces {
+		options = append(options, informers.WithNamespace(c.client.Namespace()))
+	}
+	if tweak != nil {
+		options = append(options, informers.WithTweakListOptions(tweak))
+	}
+	return informers.NewSharedInformerFactoryWithOptions(c.client.kubeClient, resync, options...)
+}
@@ -6,6 +6,7 @@ import (
 	appsV1 "k8s.io/api/apps/v1"
 	batchV1 "k8s.io/api/batch/v1"
 	coreV1 "k8s.io/api/core/v1"
+	storageV1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
@@ -18,6 +19,7 @@ func (c *Controller) GetNamespaceList(ctx context.Context) ([]*coreV1.Namespace,
 	if err != nil {
 		return nil, err
 	}
+	list = filterNamespaced(c, list)
 
 	return list, nil
 }
@@ -32,6 +34,7 @@ func (c *Controller) GetDeploymentList(ctx context.Context) ([]*appsV1.Deploymen
 	if err != nil {
 		return nil, err
 	}
+	items = filterNamespaced(c, items)
 
 	return items, nil
 }
@@ -46,6 +49,7 @@ func (c *Controller) GetDaemonSetList(ctx context.Context) ([]*appsV1.DaemonSet,
 	if err != nil {
 		return nil, err
 	}
+	items = filterNamespaced(c, items)
 
 	return items, nil
 }
@@ -61,6 +65,7 @@ func (c *Controller) GetReplicaSetList(ctx context.Context) ([]*appsV1.ReplicaSe
 	if err != nil {
 		return nil, err
 	}
+	items = filterNamespaced(c, items)
 
 	return items, nil
 }
@@ -73,6 +78,7 @@ func (c *Controller) GetStatefulSetList(ctx context.Context) ([]*appsV1.Stateful
 	if err != nil {
 		return nil, err
 	}
+	items = filterNamespaced(c, items)
 	return items, nil
 }
 
@@ -84,6 +90,7 @@ func (c *Controller) GetJobList(ctx context.Context) ([]*batchV1.Job, error) {
 	if err != nil {
 		return nil, err
 	}
+	items = filterNamespaced(c, items)
 	return items, nil
 }
 
@@ -95,6 +102,7 @@ func (c *Controller) GetCronJobList(ctx context.Context) ([]*batchV1.CronJob, er
 	if err != nil {
 		return nil, err
 	}
+	items = filterNamespaced(c, items)
 	return items, nil
 }
 
@@ -109,6 +117,17 @@ func (c *Controller) GetPVList(ctx context.Context) ([]*coreV1.PersistentVolume,
 	return items, nil
 }
 
+func (c *Controller) GetStorageClassList(ctx context.Context) ([]*storageV1.StorageClass, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	items, err := c.storageClassInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 func (c *Controller) GetPVCList(ctx context.Context) ([]*coreV1.PersistentVolumeClaim, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
@@ -117,5 +136,6 @@ func (c *Controller) GetPVCList(ctx context.Context) ([]*coreV1.PersistentVolume
 	if err != nil {
 		return nil, err
 	}
+	items = filterNamespaced(c, items)
 	return items, nil
 }
@@ -0,0 +1,36 @@
+package k8s
+
+import (
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestGetContextModels(t *testing.T) {
+	client := &Client{
+		clusterContext: "staging",
+		apiConfig: api.Config{Contexts: map[string]*api.Context{
+			"staging": {Cluster: "staging-cluster", AuthInfo: "dev", Namespace: "web"},
+			"prod-a":  {Cluster: "prod-a-cluster", AuthInfo: "ops"},
+		}},
+	}
+
+	models := client.GetContextModels()
+	if len(models) != 2 || models[0].Name != "prod-a" || models[1].Name != "staging" {
+		t.Fatalf("expecting contexts sorted by name, got %+v", models)
+	}
+	if models[0].Current || !models[1].Current {
+		t.Errorf("expecting only staging to be current, got %+v", models)
+	}
+	if models[1].Cluster != "staging-cluster" || models[1].User != "dev" || models[1].Namespace != "web" {
+		t.Errorf("unexpected staging context %+v", models[1])
+	}
+}
+
+func TestForUnknownContext(t *testing.T) {
+	client := &Client{flags: genericclioptions.NewConfigFlags(false)}
+	if _, err := client.ForContext("missing"); err == nil {
+		t.Error("expecting error for a context missing from the kubeconfig")
+	}
+}
@@ -0,0 +1,181 @@
+package k8s
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	controlPlanePodSelector = "tier=control-plane,component in (kube-apiserver,kube-scheduler,kube-controller-manager)"
+	apiServerComponent      = "kube-apiserver"
+)
+
+var controlPlaneHealthEndpoints = []string{"/readyz", "/livez"}
+
+// GetControlPlaneModel collects the API server health checks and the
+// readiness of the control plane static pods in kube-system.
+func (c *Controller) GetControlPlaneModel(ctx context.Context) (*model.ControlPlaneModel, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var cp model.ControlPlaneModel
+	apiServerReady := true
+	for _, endpoint := range controlPlaneHealthEndpoints {
+		checks, passed := c.getAPIServerChecks(ctx, endpoint)
+		cp.Checks = append(cp.Checks, checks...)
+		apiServerReady = apiServerReady && passed
+	}
+
+	// static pods are queried directly since the pod informer may not be watching kube-system
+	pods, err := c.client.kubeClient.CoreV1().Pods(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{LabelSelector: controlPlanePodSelector})
+	if err == nil {
+		for _, pod := range pods.Items {
+			healthy := podIsReady(&pod)
+			status := "NotReady"
+			if healthy {
+				status = "Ready"
+			}
+			cp.Components = append(cp.Components, model.ControlPlaneComponent{
+				Name:    pod.Labels["component"],
+				Node:    pod.Spec.NodeName,
+				Healthy: healthy,
+				Status:  status,
+			})
+		}
+	}
+
+	// managed control planes do not expose their pods, so fall back to the API server checks
+	if !hasComponent(cp.Components, apiServerComponent) {
+		status := "Ready"
+		if !apiServerReady {
+			status = "NotReady"
+		}
+		cp.Components = append([]model.ControlPlaneComponent{{
+			Name:    apiServerComponent,
+			Node:    c.client.RESTConfig().Host,
+			Healthy: apiServerReady,
+			Status:  status,
+		}}, cp.Components...)
+	}
+
+	return &cp, nil
+}
+
+// getAPIServerChecks queries the verbose form of an API server health
+// endpoint, which lists each named check, and reports whether all passed.
+func (c *Controller) getAPIServerChecks(ctx context.Context, endpoint string) ([]model.ControlPlaneCheck, bool) {
+	// failed checks are reported with a non-200 status, still carrying the check list
+	body, err := c.client.kubeClient.Discovery().RESTClient().Get().
+		AbsPath(endpoint).
+		Param("verbose", "true").
+		DoRaw(ctx)
+	checks := parseHealthChecks(endpoint, body)
+	if len(checks) == 0 && err != nil {
+		checks = append(checks, model.ControlPlaneCheck{Endpoint: endpoint, Name: "request", Message: err.Error()})
+	}
+	return checks, err == nil
+}
+
+// parseHealthChecks parses lines such as "[+]ping ok" and
+// "[-]etcd failed: reason withheld" from a verbose health response
+func parseHealthChecks(endpoint string, body []byte) []model.ControlPlaneCheck {
+	var checks []model.ControlPlaneCheck
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		var passed bool
+		switch {
+		case strings.HasPrefix(line, "[+]"):
+			passed = true
+		case strings.HasPrefix(line, "[-]"):
+			passed = false
+		default:
+			continue
+		}
+		fields := strings.SplitN(line[3:], " ", 2)
+		check := model.ControlPlaneCheck{Endpoint: endpoint, Name: fields[0], Passed: passed}
+		if len(fields) > 1 {
+			check.Message = fields[1]
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func hasComponent(comps []model.ControlPlaneComponent, name string) bool {
+	for _, comp := range comps {
+		if comp.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func podIsReady(pod *coreV1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == coreV1.PodReady && cond.Status == coreV1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Controller) setupControlPlaneHandler(ctx context.Context) {
+	c.loops.Add(1)
+	go func() {
+		defer c.loops.Done()
+		c.refreshControlPlane(ctx, c.controlPlaneRefreshFunc) // initial refresh
+		c.etcdMonitor.Status(ctx)
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// renews the etcd counts when stale, which marks the summary on change
+				c.etcdMonitor.Status(ctx)
+				if err := c.refreshControlPlane(ctx, c.controlPlaneRefreshFunc); err != nil {
+					continue
+				}
+			}
+		}
+	}()
+}
+
+func (c *Controller) refreshControlPlane(ctx context.Context, handlerFunc RefreshControlPlaneFunc) error {
+	cp, err := c.GetControlPlaneModel(ctx)
+	if err != nil {
+		return err
+	}
+	c.Lock()
+	previous := c.controlPlane
+	c.controlPlane = cp
+	c.Unlock()
+	if !sameHealth(previous, cp) {
+		c.dirty.markSummary()
+	}
+	if handlerFunc != nil {
+		handlerFunc(ctx, *cp)
+	}
+	return nil
+}
+
+// sameHealth reports whether control planes a and b count the same healthy
+// and unhealthy components, as shown by the cluster summary
+func sameHealth(a, b *model.ControlPlaneModel) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	aHealthy, aUnhealthy := a.Healthy()
+	bHealthy, bUnhealthy := b.Healthy()
+	return aHealthy == bHealthy && aUnhealthy == bUnhealthy
+}
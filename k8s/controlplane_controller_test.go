@@ -0,0 +1,25 @@
+package k8s
+
+import "testing"
+
+func TestParseHealthChecks(t *testing.T) {
+	body := []byte(`[+]ping ok
+[+]log ok
+[-]etcd failed: reason withheld
+[+]poststarthook/start-kube-apiserver-admission-initializer ok
+readyz check failed
+`)
+	checks := parseHealthChecks("/readyz", body)
+	if len(checks) != 4 {
+		t.Fatalf("expecting 4 checks, got %d", len(checks))
+	}
+
+	etcd := checks[2]
+	if etcd.Name != "etcd" || etcd.Passed || etcd.Message != "failed: reason withheld" {
+		t.Errorf("unexpected etcd check %+v", etcd)
+	}
+	hook := checks[3]
+	if hook.Name != "poststarthook/start-kube-apiserver-admission-initializer" || !hook.Passed || hook.Endpoint != "/readyz" {
+		t.Errorf("unexpected post start hook check %+v", hook)
+	}
+}
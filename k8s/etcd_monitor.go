@@ -0,0 +1,318 @@
+package k8s
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	EtcdDiscoveryAuto      = "auto"
+	EtcdDiscoveryPods      = "pods"
+	EtcdDiscoveryNodes     = "nodes"
+	EtcdDiscoveryEndpoints = "endpoints"
+
+	EtcdHealthAPIServer = "apiserver"
+	EtcdHealthEndpoint  = "endpoint"
+	EtcdHealthProbe     = "probe"
+
+	etcdPodSelector          = "component=etcd"
+	etcdClientURLsAnnotation = "kubeadm.kubernetes.io/etcd.advertise-client-urls"
+	etcdClientPort           = "2379"
+)
+
+// EtcdConfig selects how etcd members are discovered and how their health is checked
+type EtcdConfig struct {
+	// Discovery is one of auto, pods, nodes or endpoints. Auto looks for the
+	// static etcd pods in kube-system and falls back to control-plane nodes.
+	Discovery string
+	Endpoints []string
+
+	// Health is one of apiserver (the /readyz/etcd check, which reports on
+	// the etcd cluster as a whole), endpoint (the /health endpoint of each
+	// member) or probe (the host probe configured for etcd).
+	Health   string
+	CAFile   string
+	CertFile string
+	KeyFile  string
+	Timeout  time.Duration
+	TTL      time.Duration
+}
+
+// DefaultEtcdConfig discovers etcd automatically and asks the API server for its health
+func DefaultEtcdConfig() EtcdConfig {
+	return EtcdConfig{
+		Discovery: EtcdDiscoveryAuto,
+		Health:    EtcdHealthAPIServer,
+		Timeout:   defaultProbeTimeout,
+		TTL:       defaultProbeTTL,
+	}
+}
+
+// EtcdMember is a discovered etcd cluster member
+type EtcdMember struct {
+	Name     string
+	Address  string
+	Endpoint string
+	Node     *coreV1.Node
+}
+
+// etcdMonitor discovers etcd members and checks their health in the
+// background, keeping the last counts for the cluster summary.
+type etcdMonitor struct {
+	sync.RWMutex
+	ctrl       *Controller
+	cfg        EtcdConfig
+	httpClient *http.Client
+
+	ready     int
+	count     int
+	checkedAt time.Time
+	pending   bool
+}
+
+func newEtcdMonitor(ctrl *Controller, cfg EtcdConfig) (*etcdMonitor, error) {
+	if cfg.Discovery == "" {
+		cfg.Discovery = EtcdDiscoveryAuto
+	}
+	if cfg.Health == "" {
+		cfg.Health = EtcdHealthAPIServer
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultProbeTimeout
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultProbeTTL
+	}
+
+	switch cfg.Discovery {
+	case EtcdDiscoveryAuto, EtcdDiscoveryPods, EtcdDiscoveryNodes:
+	case EtcdDiscoveryEndpoints:
+		if len(cfg.Endpoints) == 0 {
+			return nil, fmt.Errorf("etcd endpoints discovery requires at least one endpoint")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported etcd discovery %q", cfg.Discovery)
+	}
+
+	m := &etcdMonitor{ctrl: ctrl, cfg: cfg}
+	switch cfg.Health {
+	case EtcdHealthAPIServer, EtcdHealthProbe:
+	case EtcdHealthEndpoint:
+		tlsCfg, err := etcdTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		m.httpClient = &http.Client{Timeout: cfg.Timeout, Transport: &http.Transport{TLSClientConfig: tlsCfg}}
+	default:
+		return nil, fmt.Errorf("unsupported etcd health check %q", cfg.Health)
+	}
+	return m, nil
+}
+
+// Status returns the last known count of healthy and discovered etcd members
+// without blocking, scheduling a background refresh when the counts are stale.
+func (m *etcdMonitor) Status(ctx context.Context) (ready, count int) {
+	if m.cfg.Health == EtcdHealthProbe && !m.ctrl.hostProber.Enabled(EtcdService) {
+		return 0, 0
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	if !m.pending && time.Since(m.checkedAt) > m.cfg.TTL {
+		m.pending = true
+		go m.refresh(ctx)
+	}
+	return m.ready, m.count
+}
+
+func (m *etcdMonitor) refresh(ctx context.Context) {
+	defer func() {
+		m.Lock()
+		m.pending = false
+		m.Unlock()
+	}()
+
+	members, err := m.discover(ctx)
+	if err != nil {
+		return
+	}
+
+	ready := 0
+	switch m.cfg.Health {
+	case EtcdHealthAPIServer:
+		if m.apiServerHealthy(ctx) {
+			ready = len(members)
+		}
+	case EtcdHealthEndpoint:
+		for _, member := range members {
+			if m.endpointHealthy(ctx, member.Endpoint) {
+				ready++
+			}
+		}
+	case EtcdHealthProbe:
+		for _, member := range members {
+			target := HostTarget{Name: member.Name, Address: member.Address, Node: member.Node}
+			if m.ctrl.hostProber.Status(ctx, target, EtcdService) == model.ServiceActive {
+				ready++
+			}
+		}
+	}
+
+	m.Lock()
+	changed := m.ready != ready || m.count != len(members)
+	m.ready, m.count, m.checkedAt = ready, len(members), time.Now()
+	m.Unlock()
+	if changed {
+		m.ctrl.dirty.markSummary()
+	}
+}
+
+func (m *etcdMonitor) discover(ctx context.Context) ([]EtcdMember, error) {
+	switch m.cfg.Discovery {
+	case EtcdDiscoveryPods:
+		return m.discoverPods(ctx)
+	case EtcdDiscoveryNodes:
+		return m.discoverNodes(ctx)
+	case EtcdDiscoveryEndpoints:
+		return endpointMembers(m.cfg.Endpoints), nil
+	default:
+		members, err := m.discoverPods(ctx)
+		if err == nil && len(members) > 0 {
+			return members, nil
+		}
+		return m.discoverNodes(ctx)
+	}
+}
+
+// discoverPods lists the static etcd pods, queried directly since the pod
+// informer may not be watching kube-system.
+func (m *etcdMonitor) discoverPods(ctx context.Context) ([]EtcdMember, error) {
+	listCtx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	defer cancel()
+	pods, err := m.ctrl.client.kubeClient.CoreV1().Pods(metav1.NamespaceSystem).List(listCtx, metav1.ListOptions{LabelSelector: etcdPodSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	var members []EtcdMember
+	for _, pod := range pods.Items {
+		member := EtcdMember{Name: pod.Spec.NodeName, Address: pod.Status.HostIP}
+		if member.Name == "" {
+			member.Name = pod.Name
+		}
+		if urls := pod.Annotations[etcdClientURLsAnnotation]; urls != "" {
+			member.Endpoint = strings.Split(urls, ",")[0]
+		} else if pod.Status.PodIP != "" {
+			member.Endpoint = "https://" + pod.Status.PodIP + ":" + etcdClientPort
+		}
+		if node, err := m.ctrl.GetNode(ctx, pod.Spec.NodeName); err == nil {
+			member.Node = node
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// discoverNodes treats every control-plane node as an etcd member, whether
+// or not selected by the node selector
+func (m *etcdMonitor) discoverNodes(ctx context.Context) ([]EtcdMember, error) {
+	nodes, err := m.ctrl.listNodes(ctx, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var members []EtcdMember
+	for _, node := range nodes {
+		if !model.IsNodeController(model.GetNodeControlRoles(node)) {
+			continue
+		}
+		addr := GetNodeIp(node, coreV1.NodeInternalIP)
+		members = append(members, EtcdMember{
+			Name:     node.Name,
+			Address:  addr,
+			Endpoint: "https://" + addr + ":" + etcdClientPort,
+			Node:     node,
+		})
+	}
+	return members, nil
+}
+
+func endpointMembers(endpoints []string) []EtcdMember {
+	var members []EtcdMember
+	for _, endpoint := range endpoints {
+		member := EtcdMember{Name: endpoint, Address: endpoint, Endpoint: endpoint}
+		if u, err := url.Parse(endpoint); err == nil && u.Hostname() != "" {
+			member.Name = u.Hostname()
+			member.Address = u.Hostname()
+		}
+		members = append(members, member)
+	}
+	return members
+}
+
+func (m *etcdMonitor) apiServerHealthy(ctx context.Context) bool {
+	reqCtx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	defer cancel()
+	body, err := m.ctrl.client.kubeClient.Discovery().RESTClient().Get().AbsPath("/readyz/etcd").DoRaw(reqCtx)
+	return err == nil && strings.TrimSpace(string(body)) == "ok"
+}
+
+func (m *etcdMonitor) endpointHealthy(ctx context.Context, endpoint string) bool {
+	if endpoint == "" {
+		return false
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(endpoint, "/")+"/health", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	var health struct {
+		Health string `json:"health"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return false
+	}
+	return resp.StatusCode == http.StatusOK && health.Health == "true"
+}
+
+func etcdTLSConfig(cfg EtcdConfig) (*tls.Config, error) {
+	tlsCfg := &tls.Config{}
+	if cfg.CAFile != "" {
+		ca, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("etcd ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("etcd ca: no certificates found in %s", cfg.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("etcd client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
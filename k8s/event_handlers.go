@@ -0,0 +1,566 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	metricsV1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+const (
+	// refreshDebounce is how long a burst of informer events is collected before models are rebuilt
+	refreshDebounce = 500 * time.Millisecond
+	// probeInterval is how often the host probes of the nodes are renewed, the
+	// nodes being refreshed only when a probe changes the state of a service
+	probeInterval = 10 * time.Second
+	// ageInterval is how often the models whose age text went stale are refreshed
+	ageInterval = 30 * time.Second
+
+	podNodeIndex = "spec.nodeName"
+)
+
+// dirtySet collects the pods and nodes changed since the last model refresh
+type dirtySet struct {
+	sync.Mutex
+	pods     map[string]struct{}
+	nodes    map[string]struct{}
+	allPods  bool
+	allNodes bool
+	summary  bool
+	signal   chan struct{}
+}
+
+func newDirtySet() *dirtySet {
+	return &dirtySet{
+		pods:   make(map[string]struct{}),
+		nodes:  make(map[string]struct{}),
+		signal: make(chan struct{}, 1),
+	}
+}
+
+func (d *dirtySet) notify() {
+	select {
+	case d.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (d *dirtySet) markPod(key string) {
+	d.Lock()
+	d.pods[key] = struct{}{}
+	d.Unlock()
+	d.notify()
+}
+
+func (d *dirtySet) markNode(name string) {
+	if name == "" {
+		return
+	}
+	d.Lock()
+	d.nodes[name] = struct{}{}
+	d.Unlock()
+	d.notify()
+}
+
+func (d *dirtySet) markAllPods() {
+	d.Lock()
+	d.allPods = true
+	d.Unlock()
+	d.notify()
+}
+
+func (d *dirtySet) markAllNodes() {
+	d.Lock()
+	d.allNodes = true
+	d.Unlock()
+	d.notify()
+}
+
+// markSummary marks the cluster summary, such as after a workload change
+func (d *dirtySet) markSummary() {
+	d.Lock()
+	d.summary = true
+	d.Unlock()
+	d.notify()
+}
+
+// take returns the collected changes and resets the set
+func (d *dirtySet) take() (pods, nodes map[string]struct{}, allPods, allNodes, summary bool) {
+	d.Lock()
+	defer d.Unlock()
+	pods, nodes, allPods, allNodes, summary = d.pods, d.nodes, d.allPods, d.allNodes, d.summary
+	d.pods = make(map[string]struct{})
+	d.nodes = make(map[string]struct{})
+	d.allPods, d.allNodes, d.summary = false, false, false
+	return
+}
+
+// reset drops the collected changes and a pending signal. The set lives as
+// long as the controller, so that handlers of a stopped run never write to a
+// set replaced under them.
+func (d *dirtySet) reset() {
+	d.take()
+	select {
+	case <-d.signal:
+	default:
+	}
+}
+
+// whileRunning returns handler h ignoring the events delivered once ctx is
+// done, such as by the informers of a stopped run
+func whileRunning(ctx context.Context, h cache.ResourceEventHandlerFuncs) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if ctx.Err() == nil {
+				h.OnAdd(obj)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if ctx.Err() == nil {
+				h.OnUpdate(oldObj, newObj)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if ctx.Err() == nil {
+				h.OnDelete(obj)
+			}
+		},
+	}
+}
+
+// installEventHandlers marks the objects touched by informer events of the
+// run of ctx as dirty. Updates that do not change the resource version are
+// informer resyncs and are ignored, as are metrics updates that do not change
+// the sample time.
+func (c *Controller) installEventHandlers(ctx context.Context) {
+	c.podInformer.Informer().AddEventHandler(whileRunning(ctx, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.markPodDirty(obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if sameResourceVersion(oldObj, newObj) {
+				return
+			}
+			c.markPodDirty(oldObj)
+			c.markPodDirty(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.markPodDirty(obj)
+		},
+	}))
+
+	// namespace labels decide which pods a namespace selector watches
+	c.namespaceInformer.Informer().AddEventHandler(whileRunning(ctx, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.markNamespaceDirty()
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if sameResourceVersion(oldObj, newObj) {
+				return
+			}
+			c.markNamespaceDirty()
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.markNamespaceDirty()
+		},
+	}))
+
+	nodeHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.markNodeDirty(obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if sameResourceVersion(oldObj, newObj) {
+				return
+			}
+			c.markNodeDirty(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.markNodeDirty(obj)
+		},
+	}
+	c.nodeInformer.Informer().AddEventHandler(whileRunning(ctx, nodeHandler))
+
+	if c.nodeMetricsInformer != nil {
+		c.nodeMetricsInformer.Informer().AddEventHandler(whileRunning(ctx, cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.markNodeDirty(obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				if sameMetricsSample(oldObj, newObj) {
+					return
+				}
+				c.markNodeDirty(newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				c.markNodeDirty(obj)
+			},
+		}))
+	}
+	if c.podMetricsInformer != nil {
+		c.podMetricsInformer.Informer().AddEventHandler(whileRunning(ctx, cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.markObjectDirty(obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				if sameMetricsSample(oldObj, newObj) {
+					return
+				}
+				c.markObjectDirty(newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				c.markObjectDirty(obj)
+			},
+		}))
+	}
+
+	// workloads, volumes and events only count in the cluster summary
+	summaryHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.dirty.markSummary()
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if sameResourceVersion(oldObj, newObj) {
+				return
+			}
+			c.dirty.markSummary()
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.dirty.markSummary()
+		},
+	}
+	for _, informer := range []cache.SharedIndexInformer{
+		c.deploymentInformer.Informer(),
+		c.daemonSetInformer.Informer(),
+		c.replicaSetInformer.Informer(),
+		c.statefulSetInformer.Informer(),
+		c.jobInformer.Informer(),
+		c.cronJobInformer.Informer(),
+		c.pvInformer.Informer(),
+		c.pvcInformer.Informer(),
+		c.eventInformer.Informer(),
+	} {
+		informer.AddEventHandler(whileRunning(ctx, summaryHandler))
+	}
+}
+
+func (c *Controller) markPodDirty(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	pod, ok := obj.(*coreV1.Pod)
+	if !ok || !c.inNamespaceScope(pod.Namespace) {
+		return
+	}
+	c.dirty.markPod(pod.Namespace + "/" + pod.Name)
+	// the pod counts toward its node requests
+	c.dirty.markNode(pod.Spec.NodeName)
+}
+
+func (c *Controller) markNamespaceDirty() {
+	if c.client.NamespaceSelector() != nil {
+		c.dirty.markAllPods()
+	}
+}
+
+func (c *Controller) markNodeDirty(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	c.dirty.markNode(objMeta.GetName())
+}
+
+func (c *Controller) markObjectDirty(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+	if namespace, _, err := cache.SplitMetaNamespaceKey(key); err != nil || !c.inNamespaceScope(namespace) {
+		return
+	}
+	c.dirty.markPod(key)
+}
+
+func sameResourceVersion(oldObj, newObj interface{}) bool {
+	oldMeta, err := meta.Accessor(oldObj)
+	if err != nil {
+		return false
+	}
+	newMeta, err := meta.Accessor(newObj)
+	if err != nil {
+		return false
+	}
+	rv := newMeta.GetResourceVersion()
+	return rv != "" && oldMeta.GetResourceVersion() == rv
+}
+
+// sameMetricsSample reports whether metrics updates carry the same sample,
+// the metrics informers resyncing the samples they polled
+func sameMetricsSample(oldObj, newObj interface{}) bool {
+	switch newMetrics := newObj.(type) {
+	case *metricsV1beta1.NodeMetrics:
+		oldMetrics, ok := oldObj.(*metricsV1beta1.NodeMetrics)
+		return ok && oldMetrics.Timestamp.Equal(&newMetrics.Timestamp)
+	case *metricsV1beta1.PodMetrics:
+		oldMetrics, ok := oldObj.(*metricsV1beta1.PodMetrics)
+		return ok && oldMetrics.Timestamp.Equal(&newMetrics.Timestamp)
+	}
+	return false
+}
+
+// setupModelRefresh rebuilds the pod and node models affected by informer
+// events, waiting refreshDebounce after the first event of a burst.
+func (c *Controller) setupModelRefresh(ctx context.Context) {
+	c.podModels = make(map[string]model.PodModel)
+	c.nodeModels = make(map[string]model.NodeModel)
+	c.dirty.markAllPods()
+	c.dirty.markAllNodes()
+	c.dirty.markSummary()
+	c.hostProber.SetChangedFunc(c.dirty.markNode)
+
+	c.loops.Add(1)
+	go func() {
+		defer c.loops.Done()
+		probeTicker := time.NewTicker(probeInterval)
+		defer probeTicker.Stop()
+		ageTicker := time.NewTicker(ageInterval)
+		defer ageTicker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-probeTicker.C:
+				c.renewProbes(ctx)
+			case <-ageTicker.C:
+				c.markAgesDirty()
+			case <-c.dirty.signal:
+				timer := time.NewTimer(refreshDebounce)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+				c.refreshDirtyModels(ctx)
+			}
+		}
+	}()
+}
+
+// renewProbes asks the host prober for the services of the nodes, which
+// renews stale probe results in the background. Nodes are marked dirty by
+// the prober when a state changes.
+func (c *Controller) renewProbes(ctx context.Context) {
+	for name := range c.nodeModels {
+		if node, err := c.GetNode(ctx, name); err == nil {
+			c.hostProber.NodeStatuses(ctx, node)
+		}
+	}
+}
+
+// markAgesDirty marks the pods and nodes whose age text went stale
+func (c *Controller) markAgesDirty() {
+	for key, m := range c.podModels {
+		if m.AgeChanged() {
+			c.dirty.markPod(key)
+		}
+	}
+	for name, m := range c.nodeModels {
+		if m.AgeChanged() {
+			c.dirty.markNode(name)
+		}
+	}
+}
+
+func (c *Controller) refreshDirtyModels(ctx context.Context) {
+	pods, nodes, allPods, allNodes, summary := c.dirty.take()
+
+	if allNodes {
+		if err := c.rebuildNodeModels(ctx); err != nil {
+			allNodes = false
+		}
+	} else {
+		for name := range nodes {
+			c.updateNodeModel(ctx, name)
+		}
+	}
+
+	podsChanged := len(pods) > 0
+	if allPods {
+		if err := c.rebuildPodModels(ctx); err == nil {
+			podsChanged = true
+		}
+	} else {
+		for key := range pods {
+			c.updatePodModel(ctx, key)
+		}
+		// node allocatable and usage are part of the models of pods on a changed node
+		nodeInfo := newNodeInfoCache()
+		for name := range nodes {
+			if c.updateNodeInfo(ctx, name, nodeInfo) {
+				podsChanged = true
+			}
+		}
+	}
+	nodesChanged := allNodes || len(nodes) > 0
+
+	c.recordHistory(nodesChanged, podsChanged)
+	if summary || nodesChanged || podsChanged {
+		c.refreshSummary(ctx, c.summaryRefreshFunc)
+	}
+
+	recorder := c.recorder
+	if nodesChanged && (c.nodeRefreshFunc != nil || recorder != nil) {
+		models := make([]model.NodeModel, 0, len(c.nodeModels))
+		for _, m := range c.nodeModels {
+			models = append(models, m)
+		}
+		if recorder != nil {
+			recorder.RecordNodes(models)
+		}
+		if c.nodeRefreshFunc != nil {
+			c.nodeRefreshFunc(ctx, models)
+		}
+	}
+	if podsChanged && (c.podRefreshFunc != nil || recorder != nil) {
+		models := make([]model.PodModel, 0, len(c.podModels))
+		for _, m := range c.podModels {
+			models = append(models, m)
+		}
+		if recorder != nil {
+			recorder.RecordPods(models)
+		}
+		if c.podRefreshFunc != nil {
+			c.podRefreshFunc(ctx, models)
+		}
+	}
+}
+
+// recordHistory adds the usage of the nodes and pods kept up to date to their
+// metrics history, which the models then carry
+func (c *Controller) recordHistory(nodes, pods bool) {
+	if c.client.AssertMetricsAvailable() != nil {
+		return
+	}
+	now := time.Now()
+	if nodes {
+		for key, m := range c.nodeModels {
+			m.History = c.history.record(nodeHistoryKey(m.Name), now, m.UsageCpuQty, m.UsageMemQty)
+			c.nodeModels[key] = m
+		}
+	}
+	if pods {
+		for key, m := range c.podModels {
+			m.History = c.history.record(podHistoryKey(m.Namespace, m.Name), now, m.PodUsageCpuQty, m.PodUsageMemQty)
+			c.podModels[key] = m
+		}
+	}
+}
+
+func (c *Controller) rebuildNodeModels(ctx context.Context) error {
+	models, err := c.GetNodeModels(ctx)
+	if err != nil {
+		return err
+	}
+	c.nodeModels = make(map[string]model.NodeModel, len(models))
+	for _, m := range models {
+		c.nodeModels[m.Name] = m
+	}
+	return nil
+}
+
+func (c *Controller) rebuildPodModels(ctx context.Context) error {
+	models, err := c.GetPodModels(ctx)
+	if err != nil {
+		return err
+	}
+	c.podModels = make(map[string]model.PodModel, len(models))
+	for _, m := range models {
+		c.podModels[m.Namespace+"/"+m.Name] = m
+	}
+	return nil
+}
+
+func (c *Controller) updateNodeModel(ctx context.Context, name string) {
+	node, err := c.GetNode(ctx, name)
+	if err != nil || !c.client.Selectors().NodeSelector().Matches(labels.Set(node.Labels)) {
+		delete(c.nodeModels, name)
+		return
+	}
+	c.nodeModels[name] = *c.buildNodeModel(ctx, node, c.getPodsOnNode(name))
+}
+
+func (c *Controller) updatePodModel(ctx context.Context, key string) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+	pod, err := c.podInformer.Lister().Pods(namespace).Get(name)
+	if err != nil || pod.Status.Phase == coreV1.PodSucceeded || !c.inNamespaceScope(namespace) ||
+		!c.client.Selectors().LabelSelector().Matches(labels.Set(pod.Labels)) {
+		delete(c.podModels, key)
+		return
+	}
+	c.podModels[key] = *c.buildPodModel(ctx, pod, newNodeInfoCache())
+}
+
+// updateNodeInfo copies the allocatable resources and usage of node name to
+// the models of its pods, reporting whether there were any
+func (c *Controller) updateNodeInfo(ctx context.Context, name string, nodeInfo *nodeInfoCache) bool {
+	updated := false
+	for _, key := range c.podKeysOnNode(name) {
+		m, ok := c.podModels[key]
+		if !ok {
+			continue
+		}
+		alloc, metrics := c.nodeResources(ctx, name, nodeInfo)
+		m.NodeAllocatableCpuQty, m.NodeAllocatableMemQty = alloc.Cpu(), alloc.Memory()
+		m.NodeUsageCpuQty, m.NodeUsageMemQty = metrics.Usage.Cpu(), metrics.Usage.Memory()
+		c.podModels[key] = m
+		updated = true
+	}
+	return updated
+}
+
+// getPodsOnNode returns the pods of node nodeName listed by GetPodList: the
+// pods of the namespaces in scope selected by the label selector
+func (c *Controller) getPodsOnNode(nodeName string) []*coreV1.Pod {
+	objs, err := c.podInformer.Informer().GetIndexer().ByIndex(podNodeIndex, nodeName)
+	if err != nil {
+		return nil
+	}
+	selector := c.client.Selectors().LabelSelector()
+	pods := make([]*coreV1.Pod, 0, len(objs))
+	for _, obj := range objs {
+		if pod, ok := obj.(*coreV1.Pod); ok && selector.Matches(labels.Set(pod.Labels)) {
+			pods = append(pods, pod)
+		}
+	}
+	return filterNamespaced(c, pods)
+}
+
+func (c *Controller) podKeysOnNode(nodeName string) []string {
+	keys, err := c.podInformer.Informer().GetIndexer().IndexKeys(podNodeIndex, nodeName)
+	if err != nil {
+		return nil
+	}
+	return keys
+}
+
+func podNodeIndexFunc(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*coreV1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return nil, nil
+	}
+	return []string{pod.Spec.NodeName}, nil
+}
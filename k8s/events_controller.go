@@ -0,0 +1,93 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// objectEventsLimit is the number of most recent events kept per object
+	objectEventsLimit = 20
+	eventsTimeout     = 5 * time.Second
+	// eventsRefreshInterval is how often the events refresh func is called
+	eventsRefreshInterval = 3 * time.Second
+)
+
+// GetEventList returns the events of the watched namespaces cached by the events informer
+func (c *Controller) GetEventList(ctx context.Context) ([]*coreV1.Event, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	items, err := c.eventInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	items = filterNamespaced(c, items)
+	return items, nil
+}
+
+// GetEventModels returns the events of the watched namespaces most recent first
+func (c *Controller) GetEventModels(ctx context.Context) ([]model.EventModel, error) {
+	events, err := c.GetEventList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewEventModels(events), nil
+}
+
+func (c *Controller) setupEventsHandler(ctx context.Context) {
+	c.loops.Add(1)
+	go func() {
+		defer c.loops.Done()
+		ticker := time.NewTicker(eventsRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// the events are only listed while a panel displays them
+				handlerFunc := c.eventRefreshFunc
+				if handlerFunc == nil {
+					continue
+				}
+				events, err := c.GetEventModels(ctx)
+				if err != nil {
+					continue
+				}
+				handlerFunc(ctx, events)
+			}
+		}
+	}()
+}
+
+// GetObjectEvents returns the events about the object uid in namespace. They
+// are listed from the API server, which also finds the events outside the
+// namespaces watched by the events informer, such as the events of nodes.
+func (c *Controller) GetObjectEvents(ctx context.Context, namespace string, uid types.UID) ([]*coreV1.Event, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, eventsTimeout)
+	defer cancel()
+	list, err := c.client.kubeClient.CoreV1().Events(namespace).List(listCtx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.uid=%s", uid),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*coreV1.Event, 0, len(list.Items))
+	for i := range list.Items {
+		events = append(events, &list.Items[i])
+	}
+	return events, nil
+}
@@ -0,0 +1,238 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const (
+	// fleetRetryInterval is how often unreachable clusters are reconnected
+	fleetRetryInterval = 30 * time.Second
+	// fleetStaleAfter is how old a cluster summary gets before the cluster is reported stale
+	fleetStaleAfter = 30 * time.Second
+)
+
+// ContextFlags returns a copy of flags selecting the kubeconfig context
+// name. The cluster and user come from the context, not from flags.
+func ContextFlags(flags *genericclioptions.ConfigFlags, name string) *genericclioptions.ConfigFlags {
+	return &genericclioptions.ConfigFlags{
+		CacheDir:         flags.CacheDir,
+		KubeConfig:       flags.KubeConfig,
+		Context:          &name,
+		Namespace:        flags.Namespace,
+		TLSServerName:    flags.TLSServerName,
+		Insecure:         flags.Insecure,
+		Impersonate:      flags.Impersonate,
+		ImpersonateUID:   flags.ImpersonateUID,
+		ImpersonateGroup: flags.ImpersonateGroup,
+		Timeout:          flags.Timeout,
+		WrapConfigFn:     flags.WrapConfigFn,
+	}
+}
+
+// FleetMember is a cluster of the fleet, named after its kubeconfig context
+type FleetMember struct {
+	sync.RWMutex
+	Context    string
+	client     *Client
+	err        error
+	connecting bool
+	started    bool
+}
+
+// Client returns the member client, or nil while the cluster was never reached
+func (m *FleetMember) Client() *Client {
+	m.RLock()
+	defer m.RUnlock()
+	return m.client
+}
+
+// Err returns why the cluster is down, or nil
+func (m *FleetMember) Err() error {
+	m.RLock()
+	defer m.RUnlock()
+	return m.err
+}
+
+// Fleet watches several clusters side by side, one Client and Controller
+// per kubeconfig context. Unreachable clusters are reported as down and
+// retried instead of failing the whole fleet.
+type Fleet struct {
+	flags   *genericclioptions.ConfigFlags
+	setup   func(*Client) error
+	members []*FleetMember
+}
+
+// NewFleet returns a fleet of the clusters of contexts. Setup is applied to
+// each client once connected, before its controller is started.
+func NewFleet(flags *genericclioptions.ConfigFlags, contexts []string, setup func(*Client) error) *Fleet {
+	f := &Fleet{flags: flags, setup: setup}
+	for _, name := range contexts {
+		f.members = append(f.members, &FleetMember{Context: name})
+	}
+	return f
+}
+
+// Members returns the fleet clusters in the order of their contexts
+func (f *Fleet) Members() []*FleetMember {
+	return f.members
+}
+
+// Member returns the cluster of context name, or nil
+func (f *Fleet) Member(name string) *FleetMember {
+	for _, m := range f.members {
+		if m.Context == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// Connect connects the clusters concurrently, waiting up to timeout.
+// Clusters still connecting afterwards keep connecting in the background.
+func (f *Fleet) Connect(timeout time.Duration) {
+	var wg sync.WaitGroup
+	for _, m := range f.members {
+		wg.Add(1)
+		go func(m *FleetMember) {
+			defer wg.Done()
+			f.connect(m)
+		}(m)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
+// FirstConnected returns the first cluster that was reached, or nil
+func (f *Fleet) FirstConnected() *FleetMember {
+	for _, m := range f.members {
+		if m.Client() != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+func (f *Fleet) connect(m *FleetMember) {
+	m.Lock()
+	if m.client != nil || m.connecting {
+		m.Unlock()
+		return
+	}
+	m.connecting = true
+	m.Unlock()
+
+	client, err := New(ContextFlags(f.flags, m.Context))
+	if err == nil && f.setup != nil {
+		err = f.setup(client)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	m.connecting = false
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.client, m.err = client, nil
+}
+
+// Start starts the controller of each connected cluster and retries the
+// unreachable ones until ctx is done
+func (f *Fleet) Start(ctx context.Context, resync time.Duration) {
+	f.startMembers(ctx, resync)
+	go func() {
+		ticker := time.NewTicker(fleetRetryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				f.startMembers(ctx, resync)
+			}
+		}
+	}()
+}
+
+func (f *Fleet) startMembers(ctx context.Context, resync time.Duration) {
+	for _, m := range f.members {
+		go f.startMember(ctx, m, resync)
+	}
+}
+
+func (f *Fleet) startMember(ctx context.Context, m *FleetMember, resync time.Duration) {
+	if m.Client() == nil {
+		f.connect(m)
+	}
+
+	m.Lock()
+	client := m.client
+	if client == nil || m.started {
+		m.Unlock()
+		return
+	}
+	m.started = true
+	m.Unlock()
+
+	err := client.AssertCoreAuthz(ctx)
+	if err == nil {
+		err = client.Controller().Start(ctx, resync)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	if err != nil && !errors.Is(err, ErrControllerStarted) {
+		m.err = err
+		m.started = false
+		return
+	}
+	m.err = nil
+}
+
+// GetFleetModels returns the summary row of each cluster, marking the
+// cluster of current
+func (f *Fleet) GetFleetModels(current *Client) []model.FleetClusterModel {
+	models := make([]model.FleetClusterModel, 0, len(f.members))
+	for _, m := range f.members {
+		item := model.FleetClusterModel{Context: m.Context, State: model.ClusterDown}
+		client, err := m.Client(), m.Err()
+		switch {
+		case err != nil:
+			item.Error = err.Error()
+		case client == nil:
+			item.State = model.ClusterConnecting
+		}
+		if client != nil {
+			item.Current = client == current
+			item.Version = client.GetServerVersion()
+			summary, refreshed := client.Controller().GetClusterSummary()
+			item.Summary = summary
+			if err == nil {
+				switch {
+				case summary == nil:
+					item.State = model.ClusterSyncing
+				case time.Since(refreshed) > fleetStaleAfter:
+					item.State = model.ClusterStale
+				default:
+					item.State = model.ClusterUp
+				}
+			}
+		}
+		models = append(models, item)
+	}
+	return models
+}
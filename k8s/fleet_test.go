@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	authzV1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestFleetUnreachableCluster(t *testing.T) {
+	flags := genericclioptions.NewConfigFlags(false)
+	kubeconfig := filepath.Join(t.TempDir(), "missing-kubeconfig")
+	flags.KubeConfig = &kubeconfig
+
+	setupCalled := false
+	fleet := NewFleet(flags, []string{"prod-a", "prod-b"}, func(*Client) error {
+		setupCalled = true
+		return nil
+	})
+	fleet.Connect(5 * time.Second)
+
+	if fleet.FirstConnected() != nil {
+		t.Fatal("expecting no reachable cluster")
+	}
+	if setupCalled {
+		t.Error("expecting setup to be skipped for unreachable clusters")
+	}
+	models := fleet.GetFleetModels(nil)
+	if len(models) != 2 {
+		t.Fatalf("expecting a row per context, got %d", len(models))
+	}
+	for _, m := range models {
+		if m.State != model.ClusterDown || m.Error == "" {
+			t.Errorf("expecting cluster %s down with an error, got %+v", m.Context, m)
+		}
+	}
+}
+
+func TestContextFlags(t *testing.T) {
+	flags := genericclioptions.NewConfigFlags(false)
+	other := "other"
+	flags.Context = &other
+
+	ctxFlags := ContextFlags(flags, "prod-a")
+	if *ctxFlags.Context != "prod-a" || *flags.Context != "other" {
+		t.Errorf("expecting context prod-a without changing the base flags, got %s and %s", *ctxFlags.Context, *flags.Context)
+	}
+	if ctxFlags.KubeConfig != flags.KubeConfig {
+		t.Error("expecting kubeconfig to be shared")
+	}
+}
+
+func TestFleetRecoveredCluster(t *testing.T) {
+	reachable := false
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if !reachable {
+			return true, nil, errors.New("connection refused")
+		}
+		review := action.(k8stesting.CreateAction).GetObject().(*authzV1.SelfSubjectAccessReview)
+		review.Status.Allowed = true
+		return true, review, nil
+	})
+	client := &Client{kubeClient: kubeClient, offline: true}
+	client.controller = newController(client)
+	m := &FleetMember{Context: "prod-a", client: client}
+	fleet := &Fleet{members: []*FleetMember{m}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fleet.startMember(ctx, m, time.Minute)
+	if m.Err() == nil {
+		t.Fatal("expecting the cluster down while unreachable")
+	}
+	reachable = true
+	fleet.startMember(ctx, m, time.Minute)
+	defer client.Controller().Stop()
+	if err := m.Err(); err != nil {
+		t.Errorf("expecting the cluster error cleared once started, got %s", err)
+	}
+}
@@ -0,0 +1,314 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	ProbeSSH       = "ssh"
+	ProbeKubelet   = "kubelet"
+	ProbeCondition = "condition"
+	ProbeRuntime   = "runtime"
+	ProbeNone      = "none"
+
+	// EtcdService is the probe name reserved for etcd members,
+	// used when etcd health is checked with a host probe
+	EtcdService = "etcd"
+
+	defaultProbeTimeout     = 3 * time.Second
+	defaultProbeTTL         = 15 * time.Second
+	defaultProbeConcurrency = 10
+)
+
+// HostTarget identifies the host a service probe runs against.
+// Node is nil when the host is not a registered cluster node.
+type HostTarget struct {
+	Name    string
+	Address string
+	Node    *coreV1.Node
+}
+
+// ProbeResult is the outcome of a single service probe
+type ProbeResult struct {
+	State     model.ServiceState
+	Err       error
+	CheckedAt time.Time
+}
+
+// HostProbe checks the health of a named service on a host
+type HostProbe interface {
+	Probe(ctx context.Context, target HostTarget, service string) ProbeResult
+}
+
+// HostProbeConfig selects and configures the probe backend for a service
+type HostProbeConfig struct {
+	Type          string
+	Timeout       time.Duration
+	User          string // ssh
+	Port          int    // kubelet
+	Condition     string // condition
+	HealthyStatus string // condition, defaults to True for Ready and False for the node-problem-detector conditions
+	Runtime       string // runtime
+}
+
+// HostService is a service monitored on every node matched by NodeSelector
+type HostService struct {
+	Name         string
+	Probe        HostProbeConfig
+	NodeSelector labels.Selector
+}
+
+// HostProberConfig configures the probe backend of every monitored service
+// along with the limits shared by all probes.
+type HostProberConfig struct {
+	Services    []HostService
+	Etcd        HostProbeConfig
+	Concurrency int
+	TTL         time.Duration
+}
+
+// DefaultHostProberConfig returns the services ktop has always monitored:
+// kubelet and containerd from the node status, scini with systemctl over ssh.
+// Etcd is checked over ssh only when its health is set to use a host probe.
+func DefaultHostProberConfig() HostProberConfig {
+	return HostProberConfig{
+		Services: []HostService{
+			{Name: "kubelet", Probe: HostProbeConfig{Type: ProbeCondition, Condition: string(coreV1.NodeReady), HealthyStatus: string(coreV1.ConditionTrue)}},
+			{Name: "containerd", Probe: HostProbeConfig{Type: ProbeRuntime}},
+			{Name: "scini", Probe: HostProbeConfig{Type: ProbeSSH}},
+		},
+		Etcd:        HostProbeConfig{Type: ProbeSSH},
+		Concurrency: defaultProbeConcurrency,
+		TTL:         defaultProbeTTL,
+	}
+}
+
+// NewHostProbe returns the probe backend named by cfg.Type
+func NewHostProbe(client *Client, cfg HostProbeConfig) (HostProbe, error) {
+	switch cfg.Type {
+	case ProbeSSH:
+		return &sshProbe{user: cfg.User}, nil
+	case ProbeKubelet:
+		return &kubeletProbe{client: client, port: cfg.Port}, nil
+	case ProbeCondition:
+		if cfg.Condition == "" {
+			return nil, fmt.Errorf("condition probe requires a condition type")
+		}
+		healthy := coreV1.ConditionFalse
+		switch coreV1.ConditionStatus(cfg.HealthyStatus) {
+		case "":
+			if coreV1.NodeConditionType(cfg.Condition) == coreV1.NodeReady {
+				healthy = coreV1.ConditionTrue
+			}
+		case coreV1.ConditionTrue, coreV1.ConditionFalse, coreV1.ConditionUnknown:
+			healthy = coreV1.ConditionStatus(cfg.HealthyStatus)
+		default:
+			return nil, fmt.Errorf("condition probe healthy status %q is not True, False or Unknown", cfg.HealthyStatus)
+		}
+		return &conditionProbe{condition: coreV1.NodeConditionType(cfg.Condition), healthy: healthy}, nil
+	case ProbeRuntime:
+		return &runtimeProbe{runtime: cfg.Runtime}, nil
+	case ProbeNone, "":
+		return noopProbe{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported probe type %q", cfg.Type)
+	}
+}
+
+type probeEntry struct {
+	probe   HostProbe
+	timeout time.Duration
+	// inline probes only read the node object and run on every call
+	inline bool
+}
+
+// HostProber runs service probes in the background and caches their
+// results per host so that callers never wait on a slow or unreachable host.
+type HostProber struct {
+	sync.RWMutex
+	services []HostService
+	entries  map[string]probeEntry
+	ttl      time.Duration
+	sem      chan struct{}
+	cache    map[string]ProbeResult
+	pending  map[string]bool
+	onChange func(host string)
+}
+
+func NewHostProber(client *Client, cfg HostProberConfig) (*HostProber, error) {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaultProbeConcurrency
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultProbeTTL
+	}
+
+	entries := make(map[string]probeEntry)
+	addEntry := func(name string, probeCfg HostProbeConfig) error {
+		if _, exists := entries[name]; exists {
+			return fmt.Errorf("service %s declared more than once", name)
+		}
+		probe, err := NewHostProbe(client, probeCfg)
+		if err != nil {
+			return fmt.Errorf("service %s: %w", name, err)
+		}
+		timeout := probeCfg.Timeout
+		if timeout <= 0 {
+			timeout = defaultProbeTimeout
+		}
+		inline := probeCfg.Type == ProbeCondition || probeCfg.Type == ProbeRuntime
+		entries[name] = probeEntry{probe: probe, timeout: timeout, inline: inline}
+		return nil
+	}
+
+	if err := addEntry(EtcdService, cfg.Etcd); err != nil {
+		return nil, err
+	}
+	services := make([]HostService, len(cfg.Services))
+	for i, svc := range cfg.Services {
+		if svc.Name == "" {
+			return nil, fmt.Errorf("service name is required")
+		}
+		if err := addEntry(svc.Name, svc.Probe); err != nil {
+			return nil, err
+		}
+		if svc.NodeSelector == nil {
+			svc.NodeSelector = labels.Everything()
+		}
+		services[i] = svc
+	}
+
+	return &HostProber{
+		services: services,
+		entries:  entries,
+		ttl:      cfg.TTL,
+		sem:      make(chan struct{}, cfg.Concurrency),
+		cache:    make(map[string]ProbeResult),
+		pending:  make(map[string]bool),
+	}, nil
+}
+
+// SetChangedFunc sets the function called with the name of a host when a
+// background probe changes the state of one of its services
+func (p *HostProber) SetChangedFunc(fn func(host string)) {
+	p.Lock()
+	p.onChange = fn
+	p.Unlock()
+}
+
+// Services returns the names of the node services, in configured order
+func (p *HostProber) Services() []string {
+	names := make([]string, len(p.services))
+	for i, svc := range p.services {
+		names[i] = svc.Name
+	}
+	return names
+}
+
+// Enabled reports whether the service is probed by anything other than the no-op backend
+func (p *HostProber) Enabled(service string) bool {
+	entry, ok := p.entries[service]
+	if !ok {
+		return false
+	}
+	_, noop := entry.probe.(noopProbe)
+	return !noop
+}
+
+// NodeStatuses returns the state of every node service on node.
+// Services whose node selector does not match the node are reported as disabled.
+func (p *HostProber) NodeStatuses(ctx context.Context, node *coreV1.Node) map[string]model.ServiceState {
+	statuses := make(map[string]model.ServiceState, len(p.services))
+	target := nodeHostTarget(node)
+	for _, svc := range p.services {
+		if !svc.NodeSelector.Matches(labels.Set(node.Labels)) {
+			statuses[svc.Name] = model.ServiceDisabled
+			continue
+		}
+		statuses[svc.Name] = p.Status(ctx, target, svc.Name)
+	}
+	return statuses
+}
+
+// Status returns the last known state of service on target without blocking.
+// A stale or missing result schedules a background probe, and until it
+// completes the state is reported as unknown.
+func (p *HostProber) Status(ctx context.Context, target HostTarget, service string) model.ServiceState {
+	if !p.Enabled(service) {
+		return model.ServiceDisabled
+	}
+	if entry := p.entries[service]; entry.inline {
+		return entry.probe.Probe(ctx, target, service).State
+	}
+
+	key := target.Name + "/" + service
+	p.RLock()
+	result, cached := p.cache[key]
+	pending := p.pending[key]
+	p.RUnlock()
+
+	if (!cached || time.Since(result.CheckedAt) > p.ttl) && !pending {
+		p.schedule(ctx, key, target, service)
+	}
+
+	if !cached {
+		return model.ServiceUnknown
+	}
+	return result.State
+}
+
+func (p *HostProber) schedule(ctx context.Context, key string, target HostTarget, service string) {
+	p.Lock()
+	if p.pending[key] {
+		p.Unlock()
+		return
+	}
+	p.pending[key] = true
+	p.Unlock()
+
+	entry := p.entries[service]
+	go func() {
+		defer func() {
+			p.Lock()
+			delete(p.pending, key)
+			p.Unlock()
+		}()
+
+		select {
+		case p.sem <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		defer func() { <-p.sem }()
+
+		probeCtx, cancel := context.WithTimeout(ctx, entry.timeout)
+		defer cancel()
+		result := entry.probe.Probe(probeCtx, target, service)
+		if result.Err != nil && probeCtx.Err() != nil {
+			result.State = model.ServiceUnknown
+		}
+		result.CheckedAt = time.Now()
+
+		p.Lock()
+		previous, cached := p.cache[key]
+		p.cache[key] = result
+		onChange := p.onChange
+		p.Unlock()
+		if onChange != nil && (!cached || previous.State != result.State) {
+			onChange(target.Name)
+		}
+	}()
+}
+
+type noopProbe struct{}
+
+func (noopProbe) Probe(_ context.Context, _ HostTarget, _ string) ProbeResult {
+	return ProbeResult{State: model.ServiceDisabled}
+}
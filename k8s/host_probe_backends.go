@@ -0,0 +1,128 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/pjy0381/ktop/views/model"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// sshProbe runs `systemctl status <service>` on the host over ssh
+type sshProbe struct {
+	user string
+}
+
+func (p *sshProbe) Probe(ctx context.Context, target HostTarget, service string) ProbeResult {
+	host := target.Address
+	if p.user != "" {
+		host = p.user + "@" + host
+	}
+	cmd := exec.CommandContext(ctx, "ssh", "-o", "StrictHostKeyChecking=no", "-o", "BatchMode=yes", host, "sudo", "systemctl", "status", service)
+
+	// systemctl exits non-zero for inactive units but still reports their state
+	output, err := cmd.Output()
+	status := extractStatus(string(output))
+	if status == "" {
+		if err == nil {
+			err = fmt.Errorf("no status reported for %s", service)
+		}
+		return ProbeResult{State: model.ServiceUnknown, Err: err}
+	}
+	if status != "active" {
+		return ProbeResult{State: model.ServiceInactive}
+	}
+	return ProbeResult{State: model.ServiceActive}
+}
+
+// kubeletProbe queries the kubelet /healthz endpoint through the API server node proxy
+type kubeletProbe struct {
+	client *Client
+	port   int
+}
+
+func (p *kubeletProbe) Probe(ctx context.Context, target HostTarget, _ string) ProbeResult {
+	node := target.Name
+	if p.port > 0 {
+		node = fmt.Sprintf("%s:%d", node, p.port)
+	}
+	body, err := p.client.kubeClient.CoreV1().RESTClient().Get().
+		AbsPath("/api/v1/nodes", node, "proxy", "healthz").
+		DoRaw(ctx)
+	if err != nil {
+		return ProbeResult{State: model.ServiceInactive, Err: err}
+	}
+	if strings.TrimSpace(string(body)) != "ok" {
+		return ProbeResult{State: model.ServiceInactive, Err: fmt.Errorf("healthz: %s", body)}
+	}
+	return ProbeResult{State: model.ServiceActive}
+}
+
+// conditionProbe reads a node condition, such as those reported by
+// node-problem-detector where a True condition signals a problem.
+type conditionProbe struct {
+	condition coreV1.NodeConditionType
+	healthy   coreV1.ConditionStatus
+}
+
+func (p *conditionProbe) Probe(_ context.Context, target HostTarget, _ string) ProbeResult {
+	if target.Node == nil {
+		return ProbeResult{State: model.ServiceUnknown, Err: fmt.Errorf("%s is not a cluster node", target.Name)}
+	}
+	for _, cond := range target.Node.Status.Conditions {
+		if cond.Type != p.condition {
+			continue
+		}
+		switch cond.Status {
+		case p.healthy:
+			return ProbeResult{State: model.ServiceActive}
+		case coreV1.ConditionUnknown:
+			return ProbeResult{State: model.ServiceUnknown}
+		default:
+			return ProbeResult{State: model.ServiceInactive}
+		}
+	}
+	return ProbeResult{State: model.ServiceUnknown}
+}
+
+// runtimeProbe checks the container runtime version reported by the kubelet,
+// optionally requiring a specific runtime such as containerd.
+type runtimeProbe struct {
+	runtime string
+}
+
+func (p *runtimeProbe) Probe(_ context.Context, target HostTarget, _ string) ProbeResult {
+	if target.Node == nil {
+		return ProbeResult{State: model.ServiceUnknown, Err: fmt.Errorf("%s is not a cluster node", target.Name)}
+	}
+	version := target.Node.Status.NodeInfo.ContainerRuntimeVersion
+	if p.runtime != "" && !strings.HasPrefix(version, p.runtime+"://") {
+		return ProbeResult{State: model.ServiceInactive}
+	}
+	if len(removeNumbersAndDotRegex(version)) == 0 {
+		return ProbeResult{State: model.ServiceInactive}
+	}
+	return ProbeResult{State: model.ServiceActive}
+}
+
+func removeNumbersAndDotRegex(input string) string {
+	re := regexp.MustCompile(`[^0-9]`)
+	return re.ReplaceAllString(input, "")
+}
+
+func extractStatus(output string) string {
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if strings.Contains(line, "Active:") {
+			fields := strings.Fields(line)
+			if len(fields) >= 2 {
+				// "Active:" 다음에 상태가 오므로, 그 다음에 있는 단어가 상태
+				return fields[1]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package k8s
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+type countingProbe struct {
+	calls int32
+	state model.ServiceState
+}
+
+func (p *countingProbe) Probe(_ context.Context, _ HostTarget, _ string) ProbeResult {
+	atomic.AddInt32(&p.calls, 1)
+	return ProbeResult{State: p.state}
+}
+
+func TestHostProberStatus(t *testing.T) {
+	probe := &countingProbe{state: model.ServiceActive}
+	prober := &HostProber{
+		entries: map[string]probeEntry{"scini": {probe: probe, timeout: time.Second}},
+		ttl:     time.Hour,
+		sem:     make(chan struct{}, 1),
+		cache:   make(map[string]ProbeResult),
+		pending: make(map[string]bool),
+	}
+	ctx := context.Background()
+	target := HostTarget{Name: "node-1", Address: "10.0.0.1"}
+
+	if state := prober.Status(ctx, target, "scini"); state != model.ServiceUnknown {
+		t.Fatalf("expecting %s before first probe completes, got %s", model.ServiceUnknown, state)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for prober.Status(ctx, target, "scini") != model.ServiceActive {
+		if time.Now().After(deadline) {
+			t.Fatal("probe result never cached")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if calls := atomic.LoadInt32(&probe.calls); calls != 1 {
+		t.Errorf("expecting a single probe call while result is fresh, got %d", calls)
+	}
+
+	if state := prober.Status(ctx, target, "etcd"); state != model.ServiceDisabled {
+		t.Errorf("expecting unconfigured service to be %s, got %s", model.ServiceDisabled, state)
+	}
+}
+
+func TestConditionProbeHealthyStatus(t *testing.T) {
+	node := &coreV1.Node{Status: coreV1.NodeStatus{Conditions: []coreV1.NodeCondition{
+		{Type: coreV1.NodeReady, Status: coreV1.ConditionTrue},
+		{Type: "SciniProblem", Status: coreV1.ConditionFalse},
+	}}}
+	target := HostTarget{Name: "node-1", Node: node}
+	tests := []struct {
+		cfg   HostProbeConfig
+		state model.ServiceState
+	}{
+		{cfg: HostProbeConfig{Condition: "Ready"}, state: model.ServiceActive},
+		{cfg: HostProbeConfig{Condition: "Ready", HealthyStatus: "False"}, state: model.ServiceInactive},
+		{cfg: HostProbeConfig{Condition: "SciniProblem"}, state: model.ServiceActive},
+		{cfg: HostProbeConfig{Condition: "SciniProblem", HealthyStatus: "True"}, state: model.ServiceInactive},
+	}
+	for _, test := range tests {
+		test.cfg.Type = ProbeCondition
+		probe, err := NewHostProbe(nil, test.cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := probe.Probe(context.Background(), target, "svc"); result.State != test.state {
+			t.Errorf("%+v: expecting %s, got %s", test.cfg, test.state, result.State)
+		}
+	}
+	if _, err := NewHostProbe(nil, HostProbeConfig{Type: ProbeCondition, Condition: "Ready", HealthyStatus: "yes"}); err == nil {
+		t.Error("expecting an invalid healthy status error")
+	}
+}
@@ -0,0 +1,58 @@
+package k8s
+
+import (
+	"context"
+	"io"
+
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// LogOptions selects the log stream of a pod container
+type LogOptions struct {
+	Container  string
+	Follow     bool
+	Previous   bool // logs of the previous, terminated, container instance
+	Timestamps bool
+	TailLines  int64 // number of most recent lines, 0 for all
+}
+
+// GetPodLogs streams the logs of pod namespace/name from the API server. The
+// stream ends when ctx is done, and must be closed by the caller.
+func (c *Controller) GetPodLogs(ctx context.Context, namespace, name string, opts LogOptions) (io.ReadCloser, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	logOpts := &coreV1.PodLogOptions{
+		Container:  opts.Container,
+		Follow:     opts.Follow,
+		Previous:   opts.Previous,
+		Timestamps: opts.Timestamps,
+	}
+	if opts.TailLines > 0 {
+		tail := opts.TailLines
+		logOpts.TailLines = &tail
+	}
+	return c.client.kubeClient.CoreV1().Pods(namespace).GetLogs(name, logOpts).Stream(ctx)
+}
+
+// GetPodContainerNames returns the containers of pod namespace/name followed
+// by its init containers
+func (c *Controller) GetPodContainerNames(ctx context.Context, namespace, name string) ([]string, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	pod, err := c.podInformer.Lister().Pods(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(pod.Spec.Containers)+len(pod.Spec.InitContainers))
+	for _, container := range pod.Spec.Containers {
+		names = append(names, container.Name)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		names = append(names, container.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,35 @@
+package k8s
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetPodLogs(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&coreV1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "api-1"}})
+	ctrl := &Controller{client: &Client{kubeClient: kubeClient}}
+
+	logs, err := ctrl.GetPodLogs(context.Background(), "web", "api-1", LogOptions{Container: "api", TailLines: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logs.Close()
+	data, err := ioutil.ReadAll(logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) == 0 {
+		t.Error("expecting logs from the stream")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ctrl.GetPodLogs(ctx, "web", "api-1", LogOptions{}); err == nil {
+		t.Error("expecting error for a cancelled context")
+	}
+}
@@ -14,6 +14,9 @@ func (c *Controller) GetNodeMetrics(ctx context.Context, nodeName string) (*metr
 	if err := c.client.AssertMetricsAvailable(); err != nil {
 		return nil, fmt.Errorf("node metrics: %s", err)
 	}
+	if c.nodeMetricsInformer == nil {
+		return nil, fmt.Errorf("node metrics: informer not running")
+	}
 
 	metrics, err := c.nodeMetricsInformer.Lister().Get(nodeName)
 	if err != nil {
@@ -28,6 +31,9 @@ func (c *Controller) GetPodMetricsByName(ctx context.Context, pod *v1.Pod) (*met
 	if err := c.client.AssertMetricsAvailable(); err != nil {
 		return nil, fmt.Errorf("pod metrics by name: %s", err)
 	}
+	if c.podMetricsInformer == nil {
+		return nil, fmt.Errorf("pod metrics by name: informer not running")
+	}
 
 	metrics, err := c.podMetricsInformer.Lister().Get(pod)
 	if err != nil {
@@ -42,11 +48,15 @@ func (c *Controller) GetAllPodMetrics(ctx context.Context) ([]*metricsV1beta1.Po
 	if err := c.client.AssertMetricsAvailable(); err != nil {
 		return nil, fmt.Errorf("all pod metrics: %s", err)
 	}
+	if c.podMetricsInformer == nil {
+		return nil, fmt.Errorf("all pod metrics: informer not running")
+	}
 
 	metricsList, err := c.podMetricsInformer.Lister().List(labels.Everything())
 	if err != nil {
 		return nil, err
 	}
+	metricsList = filterNamespaced(c, metricsList)
 
 	return metricsList, nil
 }
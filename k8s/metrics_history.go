@@ -0,0 +1,101 @@
+package k8s
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	defaultHistoryWindow  = 15 * time.Minute
+	defaultHistorySamples = 60
+
+	// clusterHistoryKey is the history of the cluster summary
+	clusterHistoryKey = "cluster"
+)
+
+// HistoryConfig sets how much metrics history is kept per node, pod and for
+// the cluster: Samples samples spread over Window
+type HistoryConfig struct {
+	Window  time.Duration
+	Samples int
+}
+
+// DefaultHistoryConfig keeps 15 minutes of history, a sample every 15 seconds
+func DefaultHistoryConfig() HistoryConfig {
+	return HistoryConfig{Window: defaultHistoryWindow, Samples: defaultHistorySamples}
+}
+
+// metricsHistory holds the metrics history of objects by key
+type metricsHistory struct {
+	sync.Mutex
+	cfg    HistoryConfig
+	series map[string]*model.MetricsHistory
+}
+
+func newMetricsHistory(cfg HistoryConfig) (*metricsHistory, error) {
+	if cfg.Window <= 0 {
+		return nil, fmt.Errorf("history window must be positive, got %s", cfg.Window)
+	}
+	if cfg.Samples < 1 {
+		return nil, fmt.Errorf("history must keep at least a sample, got %d", cfg.Samples)
+	}
+	return &metricsHistory{cfg: cfg, series: make(map[string]*model.MetricsHistory)}, nil
+}
+
+// record adds the cpu and memory used at now to the history of key and returns the history
+func (h *metricsHistory) record(key string, now time.Time, cpu, mem *resource.Quantity) model.MetricsSamples {
+	h.Lock()
+	defer h.Unlock()
+	series, ok := h.series[key]
+	if !ok {
+		series = model.NewMetricsHistory(h.cfg.Samples, h.cfg.Window/time.Duration(h.cfg.Samples))
+		h.series[key] = series
+	}
+	sample := model.MetricsSample{Time: now}
+	if cpu != nil {
+		sample.Cpu = cpu.MilliValue()
+	}
+	if mem != nil {
+		sample.Mem = mem.Value()
+	}
+	series.Add(sample)
+	return series.Samples()
+}
+
+// prune forgets the objects without samples within the window before now
+func (h *metricsHistory) prune(now time.Time) {
+	h.Lock()
+	defer h.Unlock()
+	for key, series := range h.series {
+		if last, ok := series.Last(); !ok || now.Sub(last.Time) > h.cfg.Window {
+			delete(h.series, key)
+		}
+	}
+}
+
+func nodeHistoryKey(name string) string {
+	return "node/" + name
+}
+
+func podHistoryKey(namespace, name string) string {
+	return "pod/" + namespace + "/" + name
+}
+
+// HistoryConfig returns how much metrics history is kept
+func (c *Controller) HistoryConfig() HistoryConfig {
+	return c.history.cfg
+}
+
+// SetHistoryConfig replaces how much metrics history is kept, dropping the history kept so far
+func (c *Controller) SetHistoryConfig(cfg HistoryConfig) error {
+	history, err := newMetricsHistory(cfg)
+	if err != nil {
+		return err
+	}
+	c.history = history
+	return nil
+}
@@ -0,0 +1,34 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestMetricsHistory(t *testing.T) {
+	if _, err := newMetricsHistory(HistoryConfig{Window: time.Minute}); err == nil {
+		t.Error("expecting an error for a history without samples")
+	}
+
+	history, err := newMetricsHistory(HistoryConfig{Window: time.Minute, Samples: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	cpu, mem := resource.MustParse("250m"), resource.MustParse("1Gi")
+	history.record(nodeHistoryKey("node-1"), now.Add(-2*time.Minute), &cpu, &mem)
+	samples := history.record(podHistoryKey("web", "api"), now, &cpu, nil)
+	if len(samples) != 1 || samples[0].Cpu != 250 || samples[0].Mem != 0 {
+		t.Errorf("unexpected samples %+v", samples)
+	}
+
+	history.prune(now)
+	if _, ok := history.series[nodeHistoryKey("node-1")]; ok {
+		t.Error("expecting the history of a node not sampled within the window pruned")
+	}
+	if _, ok := history.series[podHistoryKey("web", "api")]; !ok {
+		t.Error("expecting the pod history kept")
+	}
+}
@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ParseNamespaces splits a comma-separated list of namespaces, dropping
+// blanks and duplicates. An empty list means all namespaces.
+func ParseNamespaces(value string) []string {
+	seen := make(map[string]bool)
+	var namespaces []string
+	for _, ns := range strings.Split(value, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == "" || seen[ns] {
+			continue
+		}
+		seen[ns] = true
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces
+}
+
+// namespaceFiltered reports whether the informers watch more namespaces than
+// selected, in which case their objects are filtered by inNamespaceScope
+func (c *Controller) namespaceFiltered() bool {
+	return c.client.Namespace() == AllNamespaces &&
+		(len(c.client.Namespaces()) > 0 || c.client.NamespaceSelector() != nil)
+}
+
+// inNamespaceScope reports whether namespace is listed or has labels
+// matching the namespace selector
+func (c *Controller) inNamespaceScope(namespace string) bool {
+	if !c.namespaceFiltered() {
+		return true
+	}
+	for _, ns := range c.client.Namespaces() {
+		if ns == namespace {
+			return true
+		}
+	}
+	selector := c.client.NamespaceSelector()
+	if selector == nil || c.namespaceInformer == nil {
+		return false
+	}
+	obj, err := c.namespaceInformer.Lister().Get(namespace)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(obj.Labels))
+}
+
+// filterNamespaced drops the items outside the namespace scope of c, in
+// place. Namespaces, being cluster scoped, are scoped by their own name.
+func filterNamespaced[T metav1.Object](c *Controller, items []T) []T {
+	if !c.namespaceFiltered() {
+		return items
+	}
+	filtered := items[:0]
+	for _, item := range items {
+		namespace := item.GetNamespace()
+		if namespace == "" {
+			namespace = item.GetName()
+		}
+		if c.inNamespaceScope(namespace) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// ActiveNamespaces returns the sorted namespaces currently watched, or nil
+// when all namespaces are watched
+func (c *Controller) ActiveNamespaces() []string {
+	if c.client.Namespace() != AllNamespaces {
+		return []string{c.client.Namespace()}
+	}
+	if !c.namespaceFiltered() {
+		return nil
+	}
+
+	// listed namespaces are shown even before they exist
+	active := make(map[string]bool)
+	for _, ns := range c.client.Namespaces() {
+		active[ns] = true
+	}
+	if c.client.NamespaceSelector() != nil && c.namespaceInformer != nil {
+		if list, err := c.namespaceInformer.Lister().List(c.client.NamespaceSelector()); err == nil {
+			for _, ns := range list {
+				active[ns.Name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(active))
+	for ns := range active {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+	return names
+}
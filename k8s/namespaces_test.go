@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseNamespaces(t *testing.T) {
+	got := ParseNamespaces(" payments-api, ,payments-db,payments-api")
+	want := []string{"payments-api", "payments-db"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expecting %v, got %v", want, got)
+	}
+	if got := ParseNamespaces(""); got != nil {
+		t.Errorf("expecting nil for all namespaces, got %v", got)
+	}
+}
+
+func TestInNamespaceScope(t *testing.T) {
+	factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	nsInformer := factory.Core().V1().Namespaces()
+	for name, team := range map[string]string{"payments-api": "payments", "payments-db": "payments", "search": "search", "tools": ""} {
+		ns := &coreV1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"team": team}}}
+		if err := nsInformer.Informer().GetIndexer().Add(ns); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := &Client{}
+	ctrl := &Controller{client: client, namespaceInformer: nsInformer}
+
+	client.SetNamespaceScope([]string{"tools"}, labels.SelectorFromSet(labels.Set{"team": "payments"}))
+	for ns, expected := range map[string]bool{"payments-api": true, "payments-db": true, "tools": true, "search": false} {
+		if got := ctrl.inNamespaceScope(ns); got != expected {
+			t.Errorf("namespace %s: expecting in scope %t, got %t", ns, expected, got)
+		}
+	}
+	want := []string{"payments-api", "payments-db", "tools"}
+	if got := ctrl.ActiveNamespaces(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expecting active namespaces %v, got %v", want, got)
+	}
+
+	pods := []*coreV1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "search", Name: "indexer"}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "tools", Name: "toolbox"}},
+	}
+	if got := filterNamespaced(ctrl, pods); len(got) != 1 || got[0].Name != "toolbox" {
+		t.Errorf("expecting only the pods of namespaces in scope, got %v", got)
+	}
+
+	client.NewNamespace("search")
+	if ctrl.namespaceFiltered() || client.Namespace() != "search" {
+		t.Errorf("expecting a single namespace to be watched by scoped informers")
+	}
+
+	client.NewNamespace("")
+	if !ctrl.inNamespaceScope("search") || ctrl.ActiveNamespaces() != nil {
+		t.Errorf("expecting all namespaces to be watched")
+	}
+}
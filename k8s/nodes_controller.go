@@ -1,10 +1,8 @@
 package k8s
 
 import (
-		"sync"
 		"context"
 		"fmt"
-		"time"
 
 		"github.com/pjy0381/ktop/views/model"
 		coreV1 "k8s.io/api/core/v1"
@@ -24,7 +22,12 @@ func (c *Controller) GetNode(ctx context.Context, nodeName string) (*coreV1.Node
 	return node, nil
 }
 
+// GetNodeList returns the nodes selected by the node selector
 func (c *Controller) GetNodeList(ctx context.Context) ([]*coreV1.Node, error) {
+	return c.listNodes(ctx, c.client.Selectors().NodeSelector())
+}
+
+func (c *Controller) listNodes(ctx context.Context, selector labels.Selector) ([]*coreV1.Node, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
@@ -33,7 +36,7 @@ func (c *Controller) GetNodeList(ctx context.Context) ([]*coreV1.Node, error) {
 		return nil, err
 	}
 
-	items, err := c.nodeInformer.Lister().List(labels.Everything())
+	items, err := c.nodeInformer.Lister().List(selector)
 		if err != nil {
 			return nil, err
 		}
@@ -51,49 +54,30 @@ func (c *Controller) GetNodeModels(ctx context.Context) (models []model.NodeMode
 			return nil, err
 		}
 
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-
-	nodeStatusMap := make(map[string]string)
-        for _, node := range nodes {
-		wg.Add(1)
-		go func(node *coreV1.Node) {
-			defer wg.Done()
-			status := getKubeletStatus(GetNodeIp(node, coreV1.NodeInternalIP), "scini")
-			mu.Lock()
-			defer mu.Unlock()
-			nodeStatusMap[node.Name] = status
-		}(node)
-	}
-	wg.Wait()
-
 	for _, node := range nodes {
-		metrics, err := c.GetNodeMetrics(ctx, node.Name)
-		if err != nil {
-			metrics = new(metricsV1beta1.NodeMetrics)
-		}
-		nodePods := getPodNodes(node.Name, pods)
-		podsCount := len(nodePods)
-		nodeModel := model.NewNodeModel(node, metrics)
-		nodeModel.PodsCount = podsCount
-		nodeModel.RequestedPodMemQty = resource.NewQuantity(0, resource.DecimalSI)
-		nodeModel.RequestedPodCpuQty = resource.NewQuantity(0, resource.DecimalSI)
-		for _, pod := range nodePods {
-			summary := model.GetPodContainerSummary(pod)
-			nodeModel.RequestedPodMemQty.Add(*summary.RequestedMemQty)
-			nodeModel.RequestedPodCpuQty.Add(*summary.RequestedCpuQty)
-		}
-
-		nodeModel.Kubelet = isKubeletHealthy(node)
-		nodeModel.Containerd = len(removeNumbersAndDotRegex(node.Status.NodeInfo.ContainerRuntimeVersion)) != 0
+		models = append(models, *c.buildNodeModel(ctx, node, getPodNodes(node.Name, pods)))
+	}
 
-		status := nodeStatusMap[node.Name]
-		nodeModel.Scini = (status == "active")
+	return
+}
 
-		models = append(models, *nodeModel)
+func (c *Controller) buildNodeModel(ctx context.Context, node *coreV1.Node, nodePods []*coreV1.Pod) *model.NodeModel {
+	metrics, err := c.GetNodeMetrics(ctx, node.Name)
+	if err != nil {
+		metrics = new(metricsV1beta1.NodeMetrics)
+	}
+	nodeModel := model.NewNodeModel(node, metrics)
+	nodeModel.PodsCount = len(nodePods)
+	nodeModel.RequestedPodMemQty = resource.NewQuantity(0, resource.DecimalSI)
+	nodeModel.RequestedPodCpuQty = resource.NewQuantity(0, resource.DecimalSI)
+	for _, pod := range nodePods {
+		summary := model.GetPodContainerSummary(pod)
+		nodeModel.RequestedPodMemQty.Add(*summary.RequestedMemQty)
+		nodeModel.RequestedPodCpuQty.Add(*summary.RequestedCpuQty)
 	}
 
-	return
+	nodeModel.Services = c.hostProber.NodeStatuses(ctx, node)
+	return nodeModel
 }
 
 func (c *Controller) assertNodeAuthz(ctx context.Context) error {
@@ -107,33 +91,6 @@ func (c *Controller) assertNodeAuthz(ctx context.Context) error {
 	return nil
 }
 
-func (c *Controller) setupNodeHandler(ctx context.Context, handlerFunc RefreshNodesFunc) {
-	go func() {
-		c.refreshNodes(ctx, handlerFunc) // initial refresh
-		ticker := time.NewTicker(5 * time.Second)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				if err := c.refreshNodes(ctx, handlerFunc); err != nil {
-					continue
-				}
-			}
-		}
-	}()
-}
-
-func (c *Controller) refreshNodes(ctx context.Context, handlerFunc RefreshNodesFunc) error {
-	models, err := c.GetNodeModels(ctx)
-	if err != nil {
-		return err
-	}
-	handlerFunc(ctx, models)
-	return nil
-}
-
 func getPodNodes(nodeName string, pods []*coreV1.Pod) []*coreV1.Pod {
 	var result []*coreV1.Pod
 
@@ -145,6 +102,11 @@ func getPodNodes(nodeName string, pods []*coreV1.Pod) []*coreV1.Pod {
 	return result
 }
 
+// nodeHostTarget returns the probe target for a node, addressed by its internal IP
+func nodeHostTarget(node *coreV1.Node) HostTarget {
+	return HostTarget{Name: node.Name, Address: GetNodeIp(node, coreV1.NodeInternalIP), Node: node}
+}
+
 func GetNodeIp(node *coreV1.Node, addrType coreV1.NodeAddressType) string {
 	for _, addr := range node.Status.Addresses {
 		if addr.Type == addrType {
@@ -153,3 +115,30 @@ func GetNodeIp(node *coreV1.Node, addrType coreV1.NodeAddressType) string {
 	}
 	return "<none>"
 }
+
+// GetNodeDetailModel returns the node name described with the pods scheduled on it
+func (c *Controller) GetNodeDetailModel(ctx context.Context, name string) (*model.NodeDetailModel, error) {
+	node, err := c.GetNode(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := c.GetPodList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodePods := getPodNodes(node.Name, pods)
+
+	nodeInfo := newNodeInfoCache()
+	podModels := make([]model.PodModel, 0, len(nodePods))
+	for _, pod := range nodePods {
+		podModels = append(podModels, *c.buildPodModel(ctx, pod, nodeInfo))
+	}
+	model.SortPods(podModels, model.SortOrder{})
+
+	// usage is left unset when node metrics are not available
+	metrics, err := c.GetNodeMetrics(ctx, node.Name)
+	if err != nil {
+		metrics = nil
+	}
+	return model.NewNodeDetailModel(node, *c.buildNodeModel(ctx, node, nodePods), metrics, podModels), nil
+}
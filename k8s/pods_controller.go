@@ -2,11 +2,9 @@ package k8s
 
 import (
 	"context"
-	"time"
 
 	"github.com/pjy0381/ktop/views/model"
 	coreV1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	metricsV1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 )
 
@@ -14,10 +12,11 @@ func (c *Controller) GetPodList(ctx context.Context) ([]*coreV1.Pod, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
-	items, err := c.podInformer.Lister().List(labels.Everything())
+	items, err := c.podInformer.Lister().List(c.client.Selectors().LabelSelector())
 	if err != nil {
 		return nil, err
 	}
+	items = filterNamespaced(c, items)
 	return items, nil
 }
 
@@ -26,74 +25,91 @@ func (c *Controller) GetPodModels(ctx context.Context) (models []model.PodModel,
 	if err != nil {
 		return
 	}
-	nodeMetricsCache := make(map[string]*metricsV1beta1.NodeMetrics)
-	nodeAllocResMap := make(map[string]coreV1.ResourceList)
+	nodeInfo := newNodeInfoCache()
 	for _, pod := range pods {
 		if pod.Status.Phase == coreV1.PodSucceeded {
 			continue
 		}
+		models = append(models, *c.buildPodModel(ctx, pod, nodeInfo))
+	}
+	return
+}
 
-		// retrieve metrics per pod
-		podMetrics, err := c.GetPodMetricsByName(ctx, pod)
-		if err != nil {
-			podMetrics = new(metricsV1beta1.PodMetrics)
-		}
+// nodeInfoCache keeps the node metrics and allocatable resources looked up
+// while building the models of pods sharing a node
+type nodeInfoCache struct {
+	metrics map[string]*metricsV1beta1.NodeMetrics
+	alloc   map[string]coreV1.ResourceList
+}
 
-		// retrieve and cache node metrics for related pod-node
-		if metrics, ok := nodeMetricsCache[pod.Spec.NodeName]; !ok {
-			metrics, err = c.GetNodeMetrics(ctx, pod.Spec.NodeName)
-			if err != nil {
-				metrics = new(metricsV1beta1.NodeMetrics)
-			}
-			nodeMetricsCache[pod.Spec.NodeName] = metrics
-		}
-		nodeMetrics := nodeMetricsCache[pod.Spec.NodeName]
+func newNodeInfoCache() *nodeInfoCache {
+	return &nodeInfoCache{
+		metrics: make(map[string]*metricsV1beta1.NodeMetrics),
+		alloc:   make(map[string]coreV1.ResourceList),
+	}
+}
 
-		model := model.NewPodModel(pod, podMetrics, nodeMetrics)
-		// retrieve pod's node allocatable resources
-		if alloc, ok := nodeAllocResMap[pod.Spec.NodeName]; !ok {
-			node, err := c.GetNode(ctx, pod.Spec.NodeName)
-			if err != nil {
-				alloc = coreV1.ResourceList{}
-			} else {
-				alloc = node.Status.Allocatable
-			}
-			nodeAllocResMap[pod.Spec.NodeName] = alloc
-		}
-		alloc := nodeAllocResMap[pod.Spec.NodeName]
-		model.NodeAllocatableMemQty = alloc.Memory()
-		model.NodeAllocatableCpuQty = alloc.Cpu()
-		models = append(models, *model)
+func (c *Controller) buildPodModel(ctx context.Context, pod *coreV1.Pod, nodeInfo *nodeInfoCache) *model.PodModel {
+	// retrieve metrics per pod
+	podMetrics, err := c.GetPodMetricsByName(ctx, pod)
+	if err != nil {
+		podMetrics = new(metricsV1beta1.PodMetrics)
 	}
-	return
+
+	alloc, nodeMetrics := c.nodeResources(ctx, pod.Spec.NodeName, nodeInfo)
+	controllers := c.podControllers(pod)
+
+	model := model.NewPodModel(pod, podMetrics, nodeMetrics)
+	model.Controllers = controllers
+	model.NodeAllocatableMemQty = alloc.Memory()
+	model.NodeAllocatableCpuQty = alloc.Cpu()
+	return model
 }
 
-func (c *Controller) installPodsHandler(ctx context.Context, refreshFunc RefreshPodsFunc) {
-	if refreshFunc == nil {
-		return
+// nodeResources returns the allocatable resources and metrics of node
+// nodeName, looked up once per nodeInfo
+func (c *Controller) nodeResources(ctx context.Context, nodeName string, nodeInfo *nodeInfoCache) (coreV1.ResourceList, *metricsV1beta1.NodeMetrics) {
+	metrics, ok := nodeInfo.metrics[nodeName]
+	if !ok {
+		var err error
+		if metrics, err = c.GetNodeMetrics(ctx, nodeName); err != nil {
+			metrics = new(metricsV1beta1.NodeMetrics)
+		}
+		nodeInfo.metrics[nodeName] = metrics
 	}
-	go func() {
-		c.refreshPods(ctx, refreshFunc) // initial refresh
-		ticker := time.NewTicker(3 * time.Second)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				if err := c.refreshPods(ctx, refreshFunc); err != nil {
-					continue
-				}
-			}
+	alloc, ok := nodeInfo.alloc[nodeName]
+	if !ok {
+		if node, err := c.GetNode(ctx, nodeName); err != nil {
+			alloc = coreV1.ResourceList{}
+		} else {
+			alloc = node.Status.Allocatable
 		}
-	}()
+		nodeInfo.alloc[nodeName] = alloc
+	}
+	return alloc, metrics
 }
 
-func (c *Controller) refreshPods(ctx context.Context, refreshFunc RefreshPodsFunc) error {
-	models, err := c.GetPodModels(ctx)
+// GetPodDetailModel returns the pod namespace/name described container by
+// container, with its most recent events
+func (c *Controller) GetPodDetailModel(ctx context.Context, namespace, name string) (*model.PodDetailModel, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	pod, err := c.podInformer.Lister().Pods(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	podMetrics, err := c.GetPodMetricsByName(ctx, pod)
 	if err != nil {
-		return err
+		podMetrics = new(metricsV1beta1.PodMetrics)
+	}
+	// a pod without events is still described
+	events, _ := c.GetObjectEvents(ctx, pod.Namespace, pod.UID)
+
+	detail := model.NewPodDetailModel(pod, podMetrics, events)
+	if len(detail.Events) > objectEventsLimit {
+		detail.Events = detail.Events[:objectEventsLimit]
 	}
-	refreshFunc(ctx, models)
-	return nil
+	return detail, nil
 }
@@ -0,0 +1,177 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Prefixes of the sel command arguments selecting pods by field and nodes by label
+const (
+	FieldSelectorPrefix = "field:"
+	NodeSelectorPrefix  = "node:"
+)
+
+// Selectors narrow the objects watched by a controller. Labels and Fields
+// select pods, both sent to the apiserver with the list and watch requests of
+// the pod informer. Workloads are all watched, their labels differing from
+// the labels of their pods. Nodes selects nodes by label and is applied
+// client side, all nodes being watched to resolve the nodes of pods. A nil
+// selector selects everything.
+type Selectors struct {
+	Labels labels.Selector
+	Fields fields.Selector
+	Nodes  labels.Selector
+}
+
+// ParseSelectors parses the label selector of pods, the field
+// selector of pods and the label selector of nodes, any of which may be empty
+func ParseSelectors(labelSelector, fieldSelector, nodeSelector string) (Selectors, error) {
+	var sel Selectors
+	var err error
+	if sel.Labels, err = labels.Parse(labelSelector); err != nil {
+		return Selectors{}, fmt.Errorf("label selector %q: %w", labelSelector, err)
+	}
+	if sel.Fields, err = fields.ParseSelector(fieldSelector); err != nil {
+		return Selectors{}, fmt.Errorf("field selector %q: %w", fieldSelector, err)
+	}
+	if sel.Nodes, err = labels.Parse(nodeSelector); err != nil {
+		return Selectors{}, fmt.Errorf("node selector %q: %w", nodeSelector, err)
+	}
+	return sel.normalize(), nil
+}
+
+// ParseSelectorArgs parses the arguments of the sel command. Arguments are
+// label selectors of pods, or field selectors of pods when
+// prefixed with field:, or label selectors of nodes when prefixed with node:.
+// Arguments of the same kind are joined with commas, such as app=web tier=db
+// selecting app=web,tier=db, the arguments of a set based requirement such
+// as env in (prod) being kept together.
+func ParseSelectorArgs(args []string) (Selectors, error) {
+	var labelTerms, fieldTerms, nodeTerms []string
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, FieldSelectorPrefix):
+			fieldTerms = append(fieldTerms, strings.TrimPrefix(arg, FieldSelectorPrefix))
+		case strings.HasPrefix(arg, NodeSelectorPrefix):
+			nodeTerms = append(nodeTerms, strings.TrimPrefix(arg, NodeSelectorPrefix))
+		default:
+			labelTerms = append(labelTerms, arg)
+		}
+	}
+	return ParseSelectors(joinTerms(labelTerms), joinTerms(fieldTerms), joinTerms(nodeTerms))
+}
+
+// joinTerms joins the requirements of terms with commas, the terms of the
+// command line split within a requirement being joined back with spaces
+func joinTerms(terms []string) string {
+	var reqs []string
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term == "" {
+			continue
+		}
+		if n := len(reqs); n > 0 && continuesTerm(reqs[n-1], term) {
+			reqs[n-1] += " " + term
+			continue
+		}
+		reqs = append(reqs, term)
+	}
+	return strings.Join(reqs, ",")
+}
+
+// continuesTerm reports whether term continues the set based requirement
+// ending prev, such as in or (prod) after env
+func continuesTerm(prev, term string) bool {
+	return term == "in" || term == "notin" || strings.HasPrefix(term, "(") ||
+		strings.Count(prev, "(") > strings.Count(prev, ")")
+}
+
+// normalize replaces the selectors selecting everything with nil
+func (s Selectors) normalize() Selectors {
+	if s.Labels != nil && s.Labels.Empty() {
+		s.Labels = nil
+	}
+	if s.Fields != nil && s.Fields.Empty() {
+		s.Fields = nil
+	}
+	if s.Nodes != nil && s.Nodes.Empty() {
+		s.Nodes = nil
+	}
+	return s
+}
+
+// Empty reports whether the selectors select everything
+func (s Selectors) Empty() bool {
+	return s.Labels == nil && s.Fields == nil && s.Nodes == nil
+}
+
+// String returns the selectors in the form of the sel command arguments
+func (s Selectors) String() string {
+	var terms []string
+	if s.Labels != nil {
+		terms = append(terms, s.Labels.String())
+	}
+	if s.Fields != nil {
+		terms = append(terms, FieldSelectorPrefix+s.Fields.String())
+	}
+	if s.Nodes != nil {
+		terms = append(terms, NodeSelectorPrefix+s.Nodes.String())
+	}
+	return strings.Join(terms, " ")
+}
+
+// LabelSelector returns the label selector of pods, selecting everything when nil
+func (s Selectors) LabelSelector() labels.Selector {
+	if s.Labels == nil {
+		return labels.Everything()
+	}
+	return s.Labels
+}
+
+// NodeSelector returns the label selector of nodes, selecting everything when nil
+func (s Selectors) NodeSelector() labels.Selector {
+	if s.Nodes == nil {
+		return labels.Everything()
+	}
+	return s.Nodes
+}
+
+// serverSide reports whether the pod informer needs list options
+func (s Selectors) serverSide() bool {
+	return s.Labels != nil || s.Fields != nil
+}
+
+// tweakListOptions returns the list options func of the pod informer
+func (s Selectors) tweakListOptions() func(*metav1.ListOptions) {
+	return func(options *metav1.ListOptions) {
+		if s.Labels != nil {
+			options.LabelSelector = s.Labels.String()
+		}
+		if s.Fields != nil {
+			options.FieldSelector = s.Fields.String()
+		}
+	}
+}
+
+// AssertSelectors checks sel with the apiserver, which rejects the fields it
+// cannot select pods by, such as metadata.labels, only when listing. A single
+// pod is listed with the selectors of the pod informer.
+func (k8s *Client) AssertSelectors(ctx context.Context, sel Selectors) error {
+	if !sel.serverSide() {
+		return nil
+	}
+	namespace := k8s.Namespace()
+	if namespace == AllNamespaces && len(k8s.Namespaces()) > 0 {
+		namespace = k8s.Namespaces()[0]
+	}
+	options := metav1.ListOptions{Limit: 1}
+	sel.tweakListOptions()(&options)
+	if _, err := k8s.kubeClient.CoreV1().Pods(namespace).List(ctx, options); err != nil {
+		return fmt.Errorf("selectors %s: %w", sel, err)
+	}
+	return nil
+}
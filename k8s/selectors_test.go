@@ -0,0 +1,155 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	authzV1 "k8s.io/api/authorization/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestParseSelectorArgs(t *testing.T) {
+	args := strings.Split("app=web,tier!=cache,env in (prod) field:status.phase=Running  node:node-role.kubernetes.io/worker", " ")
+	sel, err := ParseSelectorArgs(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sel.String(); got != "app=web,env in (prod),tier!=cache field:status.phase=Running node:node-role.kubernetes.io/worker" {
+		t.Errorf("unexpected selectors %q", got)
+	}
+	options := &metav1.ListOptions{}
+	sel.tweakListOptions()(options)
+	if options.LabelSelector != "app=web,env in (prod),tier!=cache" || options.FieldSelector != "status.phase=Running" {
+		t.Errorf("unexpected pod list options %+v", options)
+	}
+
+	for _, test := range []struct {
+		line     string
+		selected string
+	}{
+		{line: "app=web tier!=cache", selected: "app=web,tier!=cache"},
+		{line: "app=web env notin (dev, test) tier", selected: "app=web,env notin (dev,test),tier"},
+		{line: "node:a=b node:c=d", selected: "node:a=b,c=d"},
+		{line: "field:status.phase=Running field:spec.nodeName=n1", selected: "field:spec.nodeName=n1,status.phase=Running"},
+	} {
+		sel, err := ParseSelectorArgs(strings.Fields(test.line))
+		if err != nil {
+			t.Errorf("%q: %s", test.line, err)
+		} else if got := sel.String(); got != test.selected {
+			t.Errorf("%q: expecting %q, got %q", test.line, test.selected, got)
+		}
+	}
+
+	if sel, err := ParseSelectorArgs(nil); err != nil || !sel.Empty() || sel.serverSide() {
+		t.Errorf("expecting empty selectors, got %v %v", sel, err)
+	}
+	if sel, err := ParseSelectorArgs([]string{"node:kubernetes.io/os=linux"}); err != nil || sel.serverSide() {
+		t.Errorf("expecting a node selector applied client side, got %v %v", sel, err)
+	}
+	if _, err := ParseSelectorArgs([]string{"app in (web"}); err == nil {
+		t.Error("expecting an invalid label selector error")
+	}
+	if _, err := ParseSelectorArgs([]string{"field:status.phase~Running"}); err == nil {
+		t.Error("expecting an invalid field selector error")
+	}
+}
+
+func TestGetPodsOnNodeSelected(t *testing.T) {
+	factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	podInformer := factory.Core().V1().Pods()
+	if err := podInformer.Informer().AddIndexers(cache.Indexers{podNodeIndex: podNodeIndexFunc}); err != nil {
+		t.Fatal(err)
+	}
+	pods := []*coreV1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web", Labels: map[string]string{"app": "web"}}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "cache", Labels: map[string]string{"app": "cache"}}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "tools", Name: "web", Labels: map[string]string{"app": "web"}}},
+	}
+	for _, pod := range pods {
+		pod.Spec.NodeName = "worker-1"
+		if err := podInformer.Informer().GetIndexer().Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := &Client{}
+	ctrl := &Controller{client: client, podInformer: podInformer}
+	client.SetNamespaceScope([]string{"shop", "payments"}, nil)
+	sel, err := ParseSelectors("app=web", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetSelectors(sel)
+
+	got := ctrl.getPodsOnNode("worker-1")
+	if len(got) != 1 || got[0].Namespace != "shop" || got[0].Name != "web" {
+		t.Errorf("expecting the node pods counted as by GetPodList, got %v", got)
+	}
+}
+
+func TestGetNodeListSelected(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authzV1.SelfSubjectAccessReview)
+		review.Status.Allowed = true
+		return true, review, nil
+	})
+	factory := informers.NewSharedInformerFactory(kubeClient, 0)
+	nodeInformer := factory.Core().V1().Nodes()
+	for name, role := range map[string]string{"cp-1": "node-role.kubernetes.io/control-plane", "worker-1": "node-role.kubernetes.io/worker"} {
+		node := &coreV1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{role: ""}}}
+		if err := nodeInformer.Informer().GetIndexer().Add(node); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := &Client{kubeClient: kubeClient}
+	ctrl := &Controller{client: client, nodeInformer: nodeInformer}
+	sel, err := ParseSelectors("", "", "node-role.kubernetes.io/worker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetSelectors(sel)
+
+	nodes, err := ctrl.GetNodeList(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0].Name != "worker-1" {
+		t.Errorf("expecting worker-1, got %v", nodes)
+	}
+	if all, err := ctrl.listNodes(context.Background(), labels.Everything()); err != nil || len(all) != 2 {
+		t.Errorf("expecting both nodes for etcd discovery, got %v %v", all, err)
+	}
+}
+
+func TestAssertSelectors(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		restrictions := action.(k8stesting.ListAction).GetListRestrictions()
+		if restrictions.Fields.String() == "metadata.labels=x" {
+			return true, nil, errors.New(`field label not supported: metadata.labels`)
+		}
+		return false, nil, nil
+	})
+	client := &Client{kubeClient: kubeClient}
+
+	for args, valid := range map[string]bool{"": true, "app=web field:status.phase=Running": true, "field:metadata.labels=x": false} {
+		sel, err := ParseSelectorArgs(strings.Fields(args))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.AssertSelectors(context.Background(), sel); (err == nil) != valid {
+			t.Errorf("%q: expecting valid %t, got %v", args, valid, err)
+		}
+	}
+}
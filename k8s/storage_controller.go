@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"context"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+)
+
+// storageRefreshInterval is how often the storage refresh func is called
+const storageRefreshInterval = 5 * time.Second
+
+// GetStorageModel returns the claims of the watched namespaces with the pods
+// mounting them, the persistent volumes and the storage classes
+func (c *Controller) GetStorageModel(ctx context.Context) (*model.StorageModel, error) {
+	claims, err := c.GetPVCList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := c.GetPVList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	classes, err := c.GetStorageClassList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := c.GetPodList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewStorageModel(claims, volumes, classes, pods), nil
+}
+
+func (c *Controller) setupStorageHandler(ctx context.Context) {
+	c.loops.Add(1)
+	go func() {
+		defer c.loops.Done()
+		ticker := time.NewTicker(storageRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// the storage is only described while a panel displays it
+				handlerFunc := c.storageRefreshFunc
+				if handlerFunc == nil {
+					continue
+				}
+				storage, err := c.GetStorageModel(ctx)
+				if err != nil {
+					continue
+				}
+				handlerFunc(ctx, *storage)
+			}
+		}
+	}()
+}
@@ -1,49 +1,20 @@
 package k8s
 
 import (
-	"sync"
-	"strings"
-	"regexp"
 	"context"
 	"time"
-	"os/exec"
-	"os"
 
 	"github.com/pjy0381/ktop/views/model"
 	coreV1 "k8s.io/api/core/v1"
-	"k8s.io/client-go/kubernetes"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metricsV1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 )
 
-var (
-    clientset *kubernetes.Clientset
-    lastUpdateTime time.Time
-    updateInterval = 5 * time.Second
-)
-
-func (c *Controller) setupSummaryHandler(ctx context.Context, handlerFunc RefreshSummaryFunc) {
-	go func() {
-		c.refreshSummary(ctx, handlerFunc)
-		ticker := time.NewTicker(5 * time.Second)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				if err := c.refreshSummary(ctx, handlerFunc); err != nil {
-					continue
-				}
-			}
-		}
-	}()
-}
-
+// refreshSummary computes the cluster summary from the listers and the pod
+// models. It runs with the model refresh, whenever the dirty set reports a
+// change of the nodes, pods, workloads, volumes, events or control plane.
 func (c *Controller) refreshSummary(ctx context.Context, handlerFunc RefreshSummaryFunc) error {
-	var mu sync.Mutex
-	var wg sync.WaitGroup
 	var summary model.ClusterSummary
 
 	// extract namespace summary
@@ -52,6 +23,7 @@ func (c *Controller) refreshSummary(ctx context.Context, handlerFunc RefreshSumm
 		return err
 	}
 	summary.Namespaces = len(namespaces)
+	summary.ActiveNamespaces = c.ActiveNamespaces()
 
 	nodes, err := c.GetNodeList(ctx)
 	if err != nil {
@@ -87,70 +59,43 @@ func (c *Controller) refreshSummary(ctx context.Context, handlerFunc RefreshSumm
 
 	}
 
-	// extract pods summary
-	pods, err := c.GetPodList(ctx)
-	if err != nil {
-		return err
-	}
+	// extract pods summary from the pod models, kept current by the model refresh
 	summary.PodsAvailable = 0
 	summary.RequestedPodMemTotal = resource.NewQuantity(0, resource.DecimalSI)
 	summary.RequestedPodCpuTotal = resource.NewQuantity(0, resource.DecimalSI)
-
-	nodeMetricsCache := make(map[string]*metricsV1beta1.NodeMetrics)
-	for _, pod := range pods {
-		// retrieve metrics per pod
-                podMetrics, err := c.GetPodMetricsByName(ctx, pod)
-                if err != nil {
-                        podMetrics = new(metricsV1beta1.PodMetrics)
-                }
-                // retrieve and cache node metrics for related pod-node
-                if metrics, ok := nodeMetricsCache[pod.Spec.NodeName]; !ok {
-                        metrics, err = c.GetNodeMetrics(ctx, pod.Spec.NodeName)
-                        if err != nil {
-                                metrics = new(metricsV1beta1.NodeMetrics)
-                        }
-                        nodeMetricsCache[pod.Spec.NodeName] = metrics
-                }
-                nodeMetrics := nodeMetricsCache[pod.Spec.NodeName]
-                podModel := model.NewPodModel(pod, podMetrics, nodeMetrics)
-
+	for _, podModel := range c.podModels {
 		if podModel.Status == "Completed" {
 			continue
 		}
 
 		summary.PodsAvailable++
-		if pod.Status.Phase == coreV1.PodRunning && podModel.ReadyContainers == podModel.TotalContainers {
+		if podModel.Status == string(coreV1.PodRunning) && podModel.ReadyContainers == podModel.TotalContainers {
 			summary.PodsRunning++
 		}
-		containerSummary := model.GetPodContainerSummary(pod)
-		summary.RequestedPodMemTotal.Add(*containerSummary.RequestedMemQty)
-		summary.RequestedPodCpuTotal.Add(*containerSummary.RequestedCpuQty)
+		if podModel.PodRequestedMemQty != nil {
+			summary.RequestedPodMemTotal.Add(*podModel.PodRequestedMemQty)
+		}
+		if podModel.PodRequestedCpuQty != nil {
+			summary.RequestedPodCpuTotal.Add(*podModel.PodRequestedCpuQty)
+		}
+	}
 
+	// usage history
+	if c.client.AssertMetricsAvailable() == nil {
+		now := time.Now()
+		summary.History = c.history.record(clusterHistoryKey, now, summary.UsageNodeCpuTotal, summary.UsageNodeMemTotal)
+		c.history.prune(now)
 	}
 
 	// etcd count
-	content, _ := os.ReadFile("/etc/hosts")
-	lines := strings.FieldsFunc(string(content), func(r rune) bool {
-		return r == '\n' || r == '\r'
-	})
-	for _, line := range lines {
-		words := strings.Fields(line)
-		if len(words) >= 3 {
-			if len(words[2]) == 4 && strings.Contains(words[2], "et"){
-				wg.Add(1)
-				go func(word string) {
-					defer wg.Done()
-					status := getKubeletStatus(word, "etcd")
-					mu.Lock()
-					defer mu.Unlock()
-					if status == "active" {
-						summary.EtcdReady++
-					}
-				}(words[0])
-				summary.EtcdCount++
-			}
-		}
+	summary.EtcdReady, summary.EtcdCount = c.etcdMonitor.Status(ctx)
+
+	// control plane components, as of the last control plane refresh
+	c.RLock()
+	if c.controlPlane != nil {
+		summary.ControlPlaneHealthy, summary.ControlPlaneUnhealthy = c.controlPlane.Healthy()
 	}
+	c.RUnlock()
 
 	// deployments count
 	deps, err := c.GetDeploymentList(ctx)
@@ -188,6 +133,7 @@ func (c *Controller) refreshSummary(ctx context.Context, handlerFunc RefreshSumm
 		return err
 	}
 	for _, stateful := range statefulsets {
+		summary.StatefulSetsDesired += int(stateful.Status.Replicas)
 		summary.StatefulSetsReady += int(stateful.Status.ReadyReplicas)
 	}
 
@@ -223,87 +169,58 @@ func (c *Controller) refreshSummary(ctx context.Context, handlerFunc RefreshSumm
 	summary.PVCsTotal = resource.NewQuantity(0, resource.DecimalSI)
 	for _, pvc := range pvcs {
 		if pvc.Status.Phase == coreV1.ClaimBound {
+			summary.PVCsBound++
 			summary.PVCsTotal.Add(*pvc.Spec.Resources.Requests.Storage())
 		}
 	}
 
-	// count service
-	for _, node := range nodes {
-		summary.KubeletCount++
-		summary.ContainerdCount++
-		summary.SciniCount++
-		nodeInfo := node.Status.NodeInfo
-
-		// kubelet
-                if isKubeletHealthy(node) {
-                        summary.KubeletReady++
-                }
-
-		// containerd
-		if len(removeNumbersAndDotRegex(nodeInfo.ContainerRuntimeVersion)) != 0 {
-			summary.ContainerdReady++
-                }
+	// warning events count
+	events, err := c.GetEventList(ctx)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if event.Type == coreV1.EventTypeWarning {
+			summary.WarningEvents++
+		}
+	}
 
-		// scini
-		wg.Add(1)
-		go func(node *coreV1.Node) {
-			defer wg.Done()
-			status := getKubeletStatus(node.Status.Addresses[0].Address, "scini")
-			mu.Lock()
-			defer mu.Unlock()
-			if status == "active" {
-				summary.SciniReady++
+	// count node services
+	for _, name := range c.hostProber.Services() {
+		summary.Services = append(summary.Services, model.ServiceSummary{Name: name})
+	}
+	for _, node := range nodes {
+		statuses := c.hostProber.NodeStatuses(ctx, node)
+		for i := range summary.Services {
+			svc := &summary.Services[i]
+			switch statuses[svc.Name] {
+			case model.ServiceDisabled:
+				continue
+			case model.ServiceActive:
+				svc.Ready++
 			}
-		}(node)
-
-        }
-
-	wg.Wait()
-	handlerFunc(ctx, summary)
-	return nil
-}
-
-func isKubeletHealthy(node *coreV1.Node) bool {
-        for _, condition := range node.Status.Conditions {
-		if condition.Status == coreV1.ConditionTrue {
-			return true
+			svc.Count++
 		}
-        }
-        return false
-}
-
-func removeNumbersAndDotRegex(input string) string {
-	re := regexp.MustCompile(`[^0-9]`)
-	return re.ReplaceAllString(input, "")
-}
-
-func getKubeletStatus(IP string, service string) string {
-    cmd := exec.Command("ssh", "-o StrictHostKeyChecking=no",  IP, "sudo", "systemctl", "status", service)
-    // 결과에서 상태 부분 추출
-    output, err := cmd.Output()
-    if err != nil {
-        return ""
-    }
-    status := extractStatus(string(output))
-    if status == "" {
-        return ""
-    }
+	}
 
-    defer cmd.Process.Kill()
-    return status
-}
+	c.Lock()
+	c.summary = &summary
+	c.summaryTime = time.Now()
+	c.Unlock()
 
-func extractStatus(output string) string {
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
-		if strings.Contains(line, "Active:") {
-			fields := strings.Fields(line)
-			if len(fields) >= 2 {
-				// "Active:" 다음에 상태가 오므로, 그 다음에 있는 단어가 상태
-				return fields[1]
-			}
-		}
+	if recorder := c.recorder; recorder != nil {
+		recorder.RecordSummary(summary)
+	}
+	if handlerFunc != nil {
+		handlerFunc(ctx, summary)
 	}
-	return ""
+	return nil
 }
 
+// GetClusterSummary returns the last computed cluster summary and when it
+// was computed, or nil before the first summary refresh
+func (c *Controller) GetClusterSummary() (*model.ClusterSummary, time.Time) {
+	c.RLock()
+	defer c.RUnlock()
+	return c.summary, c.summaryTime
+}
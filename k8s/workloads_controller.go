@@ -0,0 +1,146 @@
+package k8s
+
+import (
+	"context"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// workloadsRefreshInterval is how often the workloads refresh func is called
+const workloadsRefreshInterval = 5 * time.Second
+
+// GetWorkloadModels returns the deployments, stateful sets, daemon sets, jobs
+// and cron jobs of the watched namespaces, each with its pods rolled up
+func (c *Controller) GetWorkloadModels(ctx context.Context) ([]model.WorkloadModel, error) {
+	workloads := make(map[string]*model.WorkloadModel)
+	add := func(workload *model.WorkloadModel) {
+		workloads[workload.Key()] = workload
+	}
+
+	deps, err := c.GetDeploymentList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range deps {
+		add(model.NewDeploymentModel(dep))
+	}
+	statefulSets, err := c.GetStatefulSetList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, set := range statefulSets {
+		add(model.NewStatefulSetModel(set))
+	}
+	daemonSets, err := c.GetDaemonSetList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, set := range daemonSets {
+		add(model.NewDaemonSetModel(set))
+	}
+	jobs, err := c.GetJobList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		add(model.NewJobModel(job))
+	}
+	cronJobs, err := c.GetCronJobList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, cronJob := range cronJobs {
+		add(model.NewCronJobModel(cronJob))
+	}
+
+	pods, err := c.GetPodList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodeInfo := newNodeInfoCache()
+	for _, pod := range pods {
+		kind, name, ok := c.podWorkload(pod)
+		if !ok {
+			continue
+		}
+		workload, ok := workloads[model.WorkloadKey(kind, pod.Namespace, name)]
+		if !ok {
+			continue
+		}
+		workload.AddPod(*c.buildPodModel(ctx, pod, nodeInfo))
+	}
+
+	models := make([]model.WorkloadModel, 0, len(workloads))
+	for _, workload := range workloads {
+		models = append(models, *workload)
+	}
+	model.SortWorkloadModels(models)
+	return models, nil
+}
+
+// podWorkload returns the kind and name of the workload controlling pod: the
+// deployment of its replica set, the cron job of its job, or its controller.
+// Pods without a controller are not part of a workload.
+func (c *Controller) podWorkload(pod *coreV1.Pod) (kind, name string, ok bool) {
+	controllers := c.podControllers(pod)
+	if len(controllers) == 0 {
+		return "", "", false
+	}
+	top := controllers[len(controllers)-1]
+	return top.Kind, top.Name, true
+}
+
+// podControllers returns the controllers of pod from its direct controller up to
+// the top-level one, as far as the informers know them
+func (c *Controller) podControllers(pod *coreV1.Pod) []model.OwnerModel {
+	return model.ResolveOwnerChain(pod.Namespace, metav1.GetControllerOf(pod), c.controllerOf)
+}
+
+// controllerOf returns the controller of the replica set or job namespace/name.
+// Other kinds are not followed, their controllers being top-level workloads.
+func (c *Controller) controllerOf(kind, namespace, name string) *metav1.OwnerReference {
+	switch kind {
+	case model.KindReplicaSet:
+		rs, err := c.replicaSetInformer.Lister().ReplicaSets(namespace).Get(name)
+		if err != nil {
+			return nil
+		}
+		return metav1.GetControllerOf(rs)
+	case model.KindJob:
+		job, err := c.jobInformer.Lister().Jobs(namespace).Get(name)
+		if err != nil {
+			return nil
+		}
+		return metav1.GetControllerOf(job)
+	}
+	return nil
+}
+
+func (c *Controller) setupWorkloadsHandler(ctx context.Context) {
+	c.loops.Add(1)
+	go func() {
+		defer c.loops.Done()
+		ticker := time.NewTicker(workloadsRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// the workloads are only built while a panel displays them
+				handlerFunc := c.workloadRefreshFunc
+				if handlerFunc == nil {
+					continue
+				}
+				workloads, err := c.GetWorkloadModels(ctx)
+				if err != nil {
+					continue
+				}
+				handlerFunc(ctx, workloads)
+			}
+		}
+	}()
+}
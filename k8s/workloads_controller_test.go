@@ -0,0 +1,52 @@
+package k8s
+
+import (
+	"testing"
+
+	appsV1 "k8s.io/api/apps/v1"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func controlledBy(kind, name string) []metav1.OwnerReference {
+	controller := true
+	return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+}
+
+func TestPodWorkload(t *testing.T) {
+	factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	rsInformer := factory.Apps().V1().ReplicaSets()
+	jobInformer := factory.Batch().V1().Jobs()
+	rs := &appsV1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "api-5d8f", OwnerReferences: controlledBy("Deployment", "api")}}
+	if err := rsInformer.Informer().GetIndexer().Add(rs); err != nil {
+		t.Fatal(err)
+	}
+	job := &batchV1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "backup-2760", OwnerReferences: controlledBy("CronJob", "backup")}}
+	if err := jobInformer.Informer().GetIndexer().Add(job); err != nil {
+		t.Fatal(err)
+	}
+	ctrl := &Controller{replicaSetInformer: rsInformer, jobInformer: jobInformer}
+
+	tests := []struct {
+		owners []metav1.OwnerReference
+		kind   string
+		name   string
+		ok     bool
+	}{
+		{owners: controlledBy("ReplicaSet", "api-5d8f"), kind: "Deployment", name: "api", ok: true},
+		{owners: controlledBy("Job", "backup-2760"), kind: "CronJob", name: "backup", ok: true},
+		{owners: controlledBy("StatefulSet", "db"), kind: "StatefulSet", name: "db", ok: true},
+		{owners: controlledBy("ReplicaSet", "orphan-rs"), kind: "ReplicaSet", name: "orphan-rs", ok: true},
+		{owners: nil, ok: false},
+	}
+	for _, test := range tests {
+		pod := &coreV1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "pod", OwnerReferences: test.owners}}
+		kind, name, ok := ctrl.podWorkload(pod)
+		if kind != test.kind || name != test.name || ok != test.ok {
+			t.Errorf("owners %+v: expecting %s/%s %t, got %s/%s %t", test.owners, test.kind, test.name, test.ok, kind, name, ok)
+		}
+	}
+}
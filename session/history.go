@@ -0,0 +1,119 @@
+package session
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// replayHistory rebuilds the usage history of the nodes and pods of a
+// session from the usage recorded in its frames, as the controller kept it
+// while recording. Moving forward only adds the frames played since the
+// last move, moving backward replays the frames of the history window.
+type replayHistory struct {
+	window  time.Duration
+	samples int
+	pos     int // last frame added, -1 when none
+	series  map[string]*model.MetricsHistory
+}
+
+// newReplayHistory returns the history of the sessions recorded with header,
+// or nil when their frames carry the history or the cluster had no metrics
+func newReplayHistory(header Header) *replayHistory {
+	if !header.Metrics || header.HistoryWindow <= 0 || header.HistorySamples < 1 {
+		return nil
+	}
+	h := &replayHistory{window: header.HistoryWindow, samples: header.HistorySamples}
+	h.reset()
+	return h
+}
+
+func (h *replayHistory) reset() {
+	h.pos = -1
+	h.series = make(map[string]*model.MetricsHistory)
+}
+
+// moveTo adds the frames up to pos to the history
+func (h *replayHistory) moveTo(frames []Frame, pos int) {
+	if pos < h.pos || (h.pos >= 0 && frames[pos].Time.Sub(frames[h.pos].Time) > h.window) {
+		h.reset()
+	}
+	start := h.pos + 1
+	if h.pos < 0 {
+		from := frames[pos].Time.Add(-h.window)
+		start = sort.Search(pos, func(i int) bool {
+			return !frames[i].Time.Before(from)
+		})
+	}
+	for i := start; i <= pos; i++ {
+		h.add(frames[i])
+	}
+	h.pos = pos
+	h.prune(frames[pos].Time)
+}
+
+func (h *replayHistory) add(frame Frame) {
+	for _, node := range frame.Nodes {
+		h.record("node/"+node.Name, frame.Time, node.UsageCpuQty, node.UsageMemQty)
+	}
+	for _, pod := range frame.Pods {
+		h.record("pod/"+pod.Namespace+"/"+pod.Name, frame.Time, pod.PodUsageCpuQty, pod.PodUsageMemQty)
+	}
+}
+
+func (h *replayHistory) record(key string, at time.Time, cpu, mem *resource.Quantity) {
+	series, ok := h.series[key]
+	if !ok {
+		series = model.NewMetricsHistory(h.samples, h.window/time.Duration(h.samples))
+		h.series[key] = series
+	}
+	sample := model.MetricsSample{Time: at}
+	if cpu != nil {
+		sample.Cpu = cpu.MilliValue()
+	}
+	if mem != nil {
+		sample.Mem = mem.Value()
+	}
+	series.Add(sample)
+}
+
+// prune forgets the objects without samples within the window before now
+func (h *replayHistory) prune(now time.Time) {
+	for key, series := range h.series {
+		if last, ok := series.Last(); !ok || now.Sub(last.Time) > h.window {
+			delete(h.series, key)
+		}
+	}
+}
+
+// nodes returns copies of nodes with their history
+func (h *replayHistory) nodes(nodes []model.NodeModel) []model.NodeModel {
+	if nodes == nil {
+		return nil
+	}
+	withHistory := make([]model.NodeModel, len(nodes))
+	for i, node := range nodes {
+		if series, ok := h.series["node/"+node.Name]; ok {
+			node.History = series.Samples()
+		}
+		withHistory[i] = node
+	}
+	return withHistory
+}
+
+// pods returns copies of pods with their history
+func (h *replayHistory) pods(pods []model.PodModel) []model.PodModel {
+	if pods == nil {
+		return nil
+	}
+	withHistory := make([]model.PodModel, len(pods))
+	for i, pod := range pods {
+		if series, ok := h.series["pod/"+pod.Namespace+"/"+pod.Name]; ok {
+			pod.History = series.Samples()
+		}
+		withHistory[i] = pod
+	}
+	return withHistory
+}
@@ -0,0 +1,217 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+)
+
+const (
+	MinSpeed = 0.1
+	MaxSpeed = 100.0
+)
+
+// State is what a replay shows at a frame: the latest summary, nodes and
+// pods recorded up to it, any of which may be missing early in a session
+type State struct {
+	Time    time.Time
+	Frame   int // index of the frame, from 0
+	Frames  int
+	Playing bool
+	Speed   float64
+
+	Summary *model.ClusterSummary
+	Nodes   []model.NodeModel
+	Pods    []model.PodModel
+}
+
+// Player plays a session back at its recorded pace times a speed. It can
+// be paused, stepped frame by frame and moved to any time of the session.
+type Player struct {
+	sync.Mutex
+	session *Session
+	pos     int
+	playing bool
+	speed   float64
+	moved   chan struct{}
+	history *replayHistory
+
+	// index of the latest frame of each kind up to a frame, -1 when none
+	lastSummary []int
+	lastNodes   []int
+	lastPods    []int
+}
+
+// NewPlayer returns a player paused at the first frame of session
+func NewPlayer(session *Session) *Player {
+	p := &Player{session: session, speed: 1, moved: make(chan struct{}, 1), history: newReplayHistory(session.Header)}
+	summary, nodes, pods := -1, -1, -1
+	for i, frame := range session.Frames {
+		switch frame.Kind {
+		case FrameSummary:
+			summary = i
+		case FrameNodes:
+			nodes = i
+		case FramePods:
+			pods = i
+		}
+		p.lastSummary = append(p.lastSummary, summary)
+		p.lastNodes = append(p.lastNodes, nodes)
+		p.lastPods = append(p.lastPods, pods)
+	}
+	return p
+}
+
+// Header returns the header of the played session
+func (p *Player) Header() Header {
+	return p.session.Header
+}
+
+// Run calls show with the state of the player each time it moves, until ctx is done
+func (p *Player) Run(ctx context.Context, show func(State)) {
+	for {
+		p.Lock()
+		state := p.state()
+		var timer *time.Timer
+		var next <-chan time.Time
+		if p.playing && p.pos < len(p.session.Frames)-1 {
+			frames := p.session.Frames
+			delay := time.Duration(float64(frames[p.pos+1].Time.Sub(frames[p.pos].Time)) / p.speed)
+			timer = time.NewTimer(delay)
+			next = timer.C
+		}
+		pos := p.pos
+		p.Unlock()
+
+		show(state)
+
+		select {
+		case <-ctx.Done():
+		case <-p.moved:
+		case <-next:
+			p.Lock()
+			// a frame due while the player was moved is ignored
+			if p.playing && p.pos == pos {
+				p.pos++
+				// the playback stops at the end of the session
+				p.playing = p.pos < len(p.session.Frames)-1
+			}
+			p.Unlock()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// State returns the state of the player
+func (p *Player) State() State {
+	p.Lock()
+	defer p.Unlock()
+	return p.state()
+}
+
+func (p *Player) state() State {
+	frames := p.session.Frames
+	state := State{
+		Time:    frames[p.pos].Time,
+		Frame:   p.pos,
+		Frames:  len(frames),
+		Playing: p.playing,
+		Speed:   p.speed,
+	}
+	if i := p.lastSummary[p.pos]; i >= 0 {
+		state.Summary = frames[i].Summary
+	}
+	if i := p.lastNodes[p.pos]; i >= 0 {
+		state.Nodes = frames[i].Nodes
+	}
+	if i := p.lastPods[p.pos]; i >= 0 {
+		state.Pods = frames[i].Pods
+	}
+	if p.history != nil {
+		p.history.moveTo(frames, p.pos)
+		state.Nodes = p.history.nodes(state.Nodes)
+		state.Pods = p.history.pods(state.Pods)
+	}
+	return state
+}
+
+// notify wakes Run up, which is called with p locked
+func (p *Player) notify() {
+	select {
+	case p.moved <- struct{}{}:
+	default:
+	}
+}
+
+// TogglePause resumes or pauses the playback. Resuming at the last frame restarts from the first.
+func (p *Player) TogglePause() {
+	p.Lock()
+	defer p.Unlock()
+	p.playing = !p.playing
+	if p.playing && p.pos == len(p.session.Frames)-1 {
+		p.pos = 0
+	}
+	p.notify()
+}
+
+// Step pauses the playback and moves n frames, backward when n is negative
+func (p *Player) Step(n int) {
+	p.Lock()
+	defer p.Unlock()
+	p.playing = false
+	p.moveTo(p.pos + n)
+}
+
+// Seek moves by d from the time of the current frame, backward when d is negative
+func (p *Player) Seek(d time.Duration) {
+	p.Lock()
+	defer p.Unlock()
+	p.seekTo(p.session.Frames[p.pos].Time.Add(d))
+}
+
+// SeekTo moves to the last frame recorded at or before t, or the first frame
+func (p *Player) SeekTo(t time.Time) {
+	p.Lock()
+	defer p.Unlock()
+	p.seekTo(t)
+}
+
+func (p *Player) seekTo(t time.Time) {
+	frames := p.session.Frames
+	after := sort.Search(len(frames), func(i int) bool {
+		return frames[i].Time.After(t)
+	})
+	p.moveTo(after - 1)
+}
+
+func (p *Player) moveTo(pos int) {
+	switch {
+	case pos < 0:
+		pos = 0
+	case pos >= len(p.session.Frames):
+		pos = len(p.session.Frames) - 1
+	}
+	p.pos = pos
+	p.notify()
+}
+
+// SetSpeed sets the playback speed, 2 playing twice as fast as recorded
+func (p *Player) SetSpeed(speed float64) error {
+	if speed < MinSpeed || speed > MaxSpeed {
+		return fmt.Errorf("speed must be between %g and %g", MinSpeed, MaxSpeed)
+	}
+	p.Lock()
+	defer p.Unlock()
+	p.speed = speed
+	p.notify()
+	return nil
+}
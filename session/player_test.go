@@ -0,0 +1,115 @@
+package session
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func testSession() *Session {
+	start := time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+	return &Session{Frames: []Frame{
+		{Time: at(0), Kind: FrameNodes, Nodes: []model.NodeModel{{Name: "node-1"}}},
+		{Time: at(5), Kind: FramePods, Pods: []model.PodModel{{Name: "api-1"}}},
+		{Time: at(10), Kind: FrameSummary, Summary: &model.ClusterSummary{NodesCount: 1}},
+		{Time: at(20), Kind: FramePods, Pods: []model.PodModel{{Name: "api-1"}, {Name: "api-2"}}},
+	}}
+}
+
+func TestPlayerMoves(t *testing.T) {
+	player := NewPlayer(testSession())
+	if state := player.State(); state.Frame != 0 || state.Playing || len(state.Nodes) != 1 || state.Pods != nil || state.Summary != nil {
+		t.Errorf("unexpected initial state %+v", state)
+	}
+
+	player.Step(2)
+	if state := player.State(); state.Frame != 2 || len(state.Pods) != 1 || state.Summary == nil || len(state.Nodes) != 1 {
+		t.Errorf("expecting the latest models at frame 2, got %+v", state)
+	}
+	player.Step(-5)
+	if state := player.State(); state.Frame != 0 {
+		t.Errorf("expecting a step clamped to the first frame, got %d", state.Frame)
+	}
+
+	player.Seek(12 * time.Second)
+	if state := player.State(); state.Frame != 2 {
+		t.Errorf("expecting seek to the frame at 10s, got %d", state.Frame)
+	}
+	player.SeekTo(time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC))
+	if state := player.State(); state.Frame != 3 || len(state.Pods) != 2 {
+		t.Errorf("expecting seek past the end to the last frame, got %+v", state)
+	}
+
+	if err := player.SetSpeed(0); err == nil {
+		t.Error("expecting an error for a null speed")
+	}
+}
+
+func TestPlayerRun(t *testing.T) {
+	player := NewPlayer(testSession())
+	if err := player.SetSpeed(MaxSpeed); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	states := make(chan State, 16)
+	go player.Run(ctx, func(state State) {
+		states <- state
+	})
+	<-states
+	player.TogglePause()
+
+	// played at 100x, the 20s session lasts 200ms
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case state := <-states:
+			if state.Frame == 3 {
+				if state.Playing {
+					t.Error("expecting the playback stopped at the end")
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatal("the playback did not reach the last frame")
+		}
+	}
+}
+
+func TestPlayerHistory(t *testing.T) {
+	start := time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)
+	pods := func(seconds int, cpu string) Frame {
+		usage := resource.MustParse(cpu)
+		return Frame{Time: start.Add(time.Duration(seconds) * time.Second), Kind: FramePods,
+			Pods: []model.PodModel{{Namespace: "web", Name: "api-1", PodUsageCpuQty: &usage}}}
+	}
+	session := &Session{
+		Header: Header{Metrics: true, HistoryWindow: time.Minute, HistorySamples: 4},
+		Frames: []Frame{pods(0, "100m"), pods(15, "200m"), pods(30, "300m"), pods(120, "400m")},
+	}
+	player := NewPlayer(session)
+
+	player.Step(2)
+	if history := player.State().Pods[0].History.Cpu(); !reflect.DeepEqual(history, []float64{100, 200, 300}) {
+		t.Errorf("expecting the usage of the frames played as history, got %v", history)
+	}
+	player.Step(1)
+	if history := player.State().Pods[0].History.Cpu(); !reflect.DeepEqual(history, []float64{400}) {
+		t.Errorf("expecting the usage past the window dropped, got %v", history)
+	}
+	player.Step(-2)
+	if history := player.State().Pods[0].History.Cpu(); !reflect.DeepEqual(history, []float64{100, 200}) {
+		t.Errorf("expecting the history rebuilt when moving backward, got %v", history)
+	}
+	if session.Frames[1].Pods[0].History != nil {
+		t.Error("expecting the frames left without history")
+	}
+}
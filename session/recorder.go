@@ -0,0 +1,101 @@
+package session
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+)
+
+// Recorder writes the refreshes of a controller to a session file. Each frame
+// is flushed once written so that the file remains readable if ktop dies.
+// Writing stops at the first error, which Err returns.
+type Recorder struct {
+	sync.Mutex
+	file    *os.File
+	gz      *gzip.Writer
+	encoder *json.Encoder
+	err     error
+}
+
+// Create creates the session file at path, replacing any file there, and writes header
+func Create(path string, header Header) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(file)
+	r := &Recorder{file: file, gz: gz, encoder: json.NewEncoder(gz)}
+
+	header.Version = Version
+	if header.Started.IsZero() {
+		header.Started = time.Now()
+	}
+	r.write(header)
+	if r.err != nil {
+		file.Close()
+		return nil, r.err
+	}
+	return r, nil
+}
+
+func (r *Recorder) RecordSummary(summary model.ClusterSummary) {
+	r.write(Frame{Time: time.Now(), Kind: FrameSummary, Summary: &summary})
+}
+
+// RecordNodes records nodes without their history, which the player rebuilds
+// from the usage of the frames
+func (r *Recorder) RecordNodes(nodes []model.NodeModel) {
+	recorded := make([]model.NodeModel, len(nodes))
+	for i, node := range nodes {
+		node.History = nil
+		recorded[i] = node
+	}
+	r.write(Frame{Time: time.Now(), Kind: FrameNodes, Nodes: recorded})
+}
+
+// RecordPods records pods without their history, like RecordNodes
+func (r *Recorder) RecordPods(pods []model.PodModel) {
+	recorded := make([]model.PodModel, len(pods))
+	for i, pod := range pods {
+		pod.History = nil
+		recorded[i] = pod
+	}
+	r.write(Frame{Time: time.Now(), Kind: FramePods, Pods: recorded})
+}
+
+func (r *Recorder) write(value interface{}) {
+	r.Lock()
+	defer r.Unlock()
+	if r.err != nil {
+		return
+	}
+	if err := r.encoder.Encode(value); err != nil {
+		r.err = err
+		return
+	}
+	r.err = r.gz.Flush()
+}
+
+// Err returns the error that stopped the recording, if any
+func (r *Recorder) Err() error {
+	r.Lock()
+	defer r.Unlock()
+	return r.err
+}
+
+// Close ends the recording
+func (r *Recorder) Close() error {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.gz.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+	if err := r.file.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+	return r.err
+}
@@ -0,0 +1,110 @@
+// Package session records the models refreshed by a controller to a file and
+// replays them later, offline.
+//
+// A session file is a gzip stream of JSON values: a Header followed by one
+// Frame per refresh, in the order they were recorded.
+package session
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+)
+
+// Version is the version of the session files written by Recorder
+const Version = 1
+
+// Header describes the cluster a session was recorded from
+type Header struct {
+	Version       int       `json:"version"`
+	Started       time.Time `json:"started"`
+	Context       string    `json:"context"`
+	Host          string    `json:"host"`
+	ServerVersion string    `json:"serverVersion"`
+	User          string    `json:"user"`
+	Namespaces    []string  `json:"namespaces,omitempty"`
+	Metrics       bool      `json:"metrics"`
+	Services      []string  `json:"services,omitempty"`
+	// HistoryWindow and HistorySamples size the usage history rebuilt by
+	// Player, the frames carrying none. Sessions recorded without them
+	// carry the history of each model in their frames.
+	HistoryWindow  time.Duration `json:"historyWindow,omitempty"`
+	HistorySamples int           `json:"historySamples,omitempty"`
+}
+
+const (
+	FrameSummary = "summary"
+	FrameNodes   = "nodes"
+	FramePods    = "pods"
+)
+
+// Frame is a single refresh of Kind: the cluster summary, the nodes or the pods
+type Frame struct {
+	Time    time.Time             `json:"time"`
+	Kind    string                `json:"kind"`
+	Summary *model.ClusterSummary `json:"summary,omitempty"`
+	Nodes   []model.NodeModel     `json:"nodes,omitempty"`
+	Pods    []model.PodModel      `json:"pods,omitempty"`
+}
+
+// Session is a recorded session
+type Session struct {
+	Header Header
+	Frames []Frame
+}
+
+// Load reads the session file at path. A file cut short, such as by a
+// crash while recording, loads up to its last complete frame.
+func Load(path string) (*Session, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	session, err := Read(file)
+	if err != nil {
+		return nil, fmt.Errorf("session %s: %w", path, err)
+	}
+	return session, nil
+}
+
+// Read reads a session from r
+func Read(r io.Reader) (*Session, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	decoder := json.NewDecoder(gz)
+	var session Session
+	if err := decoder.Decode(&session.Header); err != nil {
+		return nil, fmt.Errorf("header: %w", err)
+	}
+	if session.Header.Version != Version {
+		return nil, fmt.Errorf("unsupported session version %d", session.Header.Version)
+	}
+
+	for {
+		var frame Frame
+		err := decoder.Decode(&frame)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("frame %d: %w", len(session.Frames), err)
+		}
+		session.Frames = append(session.Frames, frame)
+	}
+	if len(session.Frames) == 0 {
+		return nil, errors.New("no frames recorded")
+	}
+	return &session, nil
+}
@@ -0,0 +1,66 @@
+package session
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestRecordAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.ktop")
+	recorder, err := Create(path, Header{Context: "prod", Metrics: true, Services: []string{"kubelet"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpu := resource.MustParse("250m")
+	recorder.RecordSummary(model.ClusterSummary{NodesCount: 3, UsageNodeCpuTotal: &cpu})
+	recorder.RecordNodes(nil)
+	recorder.RecordPods([]model.PodModel{{Namespace: "web", Name: "api-1", PodUsageCpuQty: &cpu, History: model.MetricsSamples{{Cpu: 250}}}})
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Header.Version != Version || session.Header.Context != "prod" || session.Header.Started.IsZero() {
+		t.Errorf("unexpected header %+v", session.Header)
+	}
+	if len(session.Frames) != 3 {
+		t.Fatalf("expecting 3 frames, got %d", len(session.Frames))
+	}
+	if summary := session.Frames[0].Summary; summary == nil || summary.NodesCount != 3 || summary.UsageNodeCpuTotal.MilliValue() != 250 {
+		t.Errorf("unexpected summary frame %+v", session.Frames[0])
+	}
+	if session.Frames[1].Kind != FrameNodes {
+		t.Errorf("expecting an empty nodes frame, got %+v", session.Frames[1])
+	}
+	if pods := session.Frames[2].Pods; len(pods) != 1 || pods[0].PodUsageCpuQty.MilliValue() != 250 || pods[0].History != nil {
+		t.Errorf("unexpected pods frame %+v", session.Frames[2])
+	}
+}
+
+func TestReadTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(gz)
+	encoder.Encode(Header{Version: Version})
+	encoder.Encode(Frame{Time: time.Now(), Kind: FrameNodes, Nodes: []model.NodeModel{{Name: "node-1"}}})
+	gz.Write([]byte(`{"time":"2024-01-01T00:00:00Z","kind":"po`))
+	gz.Flush()
+
+	session, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(session.Frames) != 1 || session.Frames[0].Nodes[0].Name != "node-1" {
+		t.Errorf("expecting the complete frame only, got %+v", session.Frames)
+	}
+}
@@ -0,0 +1,147 @@
+// Package snapshot saves snapshots of the pods, nodes and workloads of a
+// cluster to files and loads them back, so that snapshots taken before and
+// after a change can be compared across ktop sessions.
+//
+// A snapshot file is a versioned JSON or YAML document, depending on the
+// extension of its path.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	"sigs.k8s.io/yaml"
+)
+
+// Version is the version of the snapshot files written by Save
+const Version = 1
+
+// File is the content of a snapshot file
+type File struct {
+	Version    int                   `json:"version"`
+	Name       string                `json:"name"`
+	Time       time.Time             `json:"time"`
+	Context    string                `json:"context"`
+	Namespaces []string              `json:"namespaces,omitempty"`
+	Pods       []model.PodModel      `json:"pods,omitempty"`
+	Nodes      []model.NodeModel     `json:"nodes,omitempty"`
+	Workloads  []model.WorkloadModel `json:"workloads,omitempty"`
+}
+
+// Save writes snapshot to path, as YAML when path ends with .yaml or .yml
+// and as JSON otherwise, without the usage history of its models
+func Save(path string, snapshot *model.Snapshot) error {
+	file := File{
+		Version:    Version,
+		Name:       snapshot.Name,
+		Time:       snapshot.Time,
+		Context:    snapshot.Context,
+		Namespaces: snapshot.Namespaces,
+		Pods:       make([]model.PodModel, len(snapshot.Pods)),
+		Nodes:      make([]model.NodeModel, len(snapshot.Nodes)),
+		Workloads:  snapshot.Workloads,
+	}
+	// the usage history is for the sparklines of a live cluster only
+	for i, pod := range snapshot.Pods {
+		pod.History = nil
+		file.Pods[i] = pod
+	}
+	for i, node := range snapshot.Nodes {
+		node.History = nil
+		file.Nodes[i] = node
+	}
+
+	var data []byte
+	var err error
+	if isYAML(path) {
+		data, err = yaml.Marshal(file)
+	} else {
+		data, err = json.MarshalIndent(file, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("snapshot %s: %w", snapshot.Name, err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Load reads the snapshot file at path, in JSON or YAML
+func Load(path string) (*model.Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is YAML too
+	data, err = yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s: %w", path, err)
+	}
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("snapshot %s: %w", path, err)
+	}
+	if file.Version != Version {
+		return nil, fmt.Errorf("snapshot %s: unsupported version %d, expecting %d", path, file.Version, Version)
+	}
+
+	snapshot := model.NewSnapshot(file.Name, file.Time, file.Pods, file.Nodes, file.Workloads)
+	snapshot.Context = file.Context
+	snapshot.Namespaces = file.Namespaces
+	return snapshot, nil
+}
+
+func isYAML(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// FileName returns the default file name of snapshot, made of its context,
+// name and time, such as ktop-prod-before-20240501-100000.json
+func FileName(snapshot *model.Snapshot) string {
+	name := fmt.Sprintf("ktop-%s-%s-%s", snapshot.Context, snapshot.Name, snapshot.Time.Local().Format("20060102-150405"))
+	return unsafeFileChars.ReplaceAllString(name, "_") + ".json"
+}
+
+// WriteDiff writes the changes from snapshot from to snapshot to as a table,
+// after a line naming both snapshots and counting the changes
+func WriteDiff(w io.Writer, from, to *model.Snapshot) error {
+	diff := model.DiffSnapshots(from, to)
+	fmt.Fprintf(w, "%s -> %s: %d added, %d removed, %d changed\n",
+		describe(from), describe(to), diff.Added, diff.Removed, diff.Changed)
+	if len(diff.Changes) == 0 {
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHANGE\tKIND\tNAMESPACE\tNAME\tFIELD\tFROM\tTO\tDELTA")
+	for _, change := range diff.Changes {
+		object := fmt.Sprintf("%s\t%s\t%s\t%s", change.Change, change.Kind, model.OrDash(change.Namespace), change.Name)
+		if len(change.Fields) == 0 {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t-\n", object)
+			continue
+		}
+		for _, field := range change.Fields {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", object, field.Field, model.OrDash(field.From), model.OrDash(field.To), model.OrDash(field.Delta))
+		}
+	}
+	return tw.Flush()
+}
+
+// describe names snapshot with its context and time
+func describe(snapshot *model.Snapshot) string {
+	return fmt.Sprintf("%s (%s, %s)", snapshot.Name, model.OrDash(snapshot.Context), snapshot.Time.Local().Format("2006-01-02 15:04:05"))
+}
+
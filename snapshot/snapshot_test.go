@@ -0,0 +1,84 @@
+package snapshot
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pjy0381/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func testSnapshot(name string, restarts int) *model.Snapshot {
+	cpu := resource.MustParse("250m")
+	snapshot := model.NewSnapshot(name, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		[]model.PodModel{{Namespace: "web", Name: "api-1", UID: "a1", Node: "node-1", Restarts: restarts, PodUsageCpuQty: &cpu, History: model.MetricsSamples{{Cpu: 250}}}},
+		[]model.NodeModel{{Name: "node-1", Status: "Ready"}},
+		[]model.WorkloadModel{{Kind: model.KindDeployment, Namespace: "web", Name: "api", Desired: 2, Ready: 2}},
+	)
+	snapshot.Context = "prod"
+	snapshot.Namespaces = []string{"web"}
+	return snapshot
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	for _, ext := range []string{".json", ".yaml"} {
+		path := filepath.Join(t.TempDir(), "before"+ext)
+		if err := Save(path, testSnapshot("before", 1)); err != nil {
+			t.Fatal(err)
+		}
+
+		snapshot, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if snapshot.Name != "before" || snapshot.Context != "prod" || len(snapshot.Namespaces) != 1 || !snapshot.Time.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+			t.Errorf("%s: unexpected snapshot %+v", ext, snapshot)
+		}
+		if len(snapshot.Pods) != 1 || snapshot.Pods[0].UID != "a1" || snapshot.Pods[0].PodUsageCpuQty.MilliValue() != 250 || snapshot.Pods[0].History != nil {
+			t.Errorf("%s: unexpected pods %+v", ext, snapshot.Pods)
+		}
+		if len(snapshot.Nodes) != 1 || len(snapshot.Workloads) != 1 || snapshot.Workloads[0].Ready != 2 {
+			t.Errorf("%s: unexpected nodes or workloads %+v %+v", ext, snapshot.Nodes, snapshot.Workloads)
+		}
+	}
+}
+
+func TestLoadVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "future.json")
+	if err := os.WriteFile(path, []byte(`{"version": 2, "name": "x"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "unsupported version 2") {
+		t.Errorf("expecting an unsupported version error, got %v", err)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	snapshot := testSnapshot("before change", 0)
+	snapshot.Context = "arn:aws:eks:eu-west-1:123:cluster/prod"
+	name := FileName(snapshot)
+	if strings.ContainsAny(name, " :/") || !strings.HasPrefix(name, "ktop-arn_aws_eks_eu-west-1_123_cluster_prod-before_change-") || filepath.Ext(name) != ".json" {
+		t.Errorf("unexpected file name %s", name)
+	}
+}
+
+func TestWriteDiff(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteDiff(&out, testSnapshot("before", 1), testSnapshot("after", 4)); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expecting a summary, a header and a change, got\n%s", out.String())
+	}
+	if !strings.Contains(lines[0], "0 added, 0 removed, 1 changed") {
+		t.Errorf("unexpected summary %q", lines[0])
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "changed Pod web api-1 restarts 1 4 +3" {
+		t.Errorf("unexpected change %q", lines[2])
+	}
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Command is a command typed in a command input or bound to a key
+type Command struct {
+	Name    string
+	Aliases []string
+	// Args describes the arguments, such as "[key|tree]" or "<namespace>"
+	Args    string
+	MinArgs int
+	Help    string
+	// Keys are the keys running the command without arguments unless
+	// bound otherwise, see Keymap
+	Keys []string
+	Run  func(args []string)
+}
+
+// Usage returns the name, aliases and arguments of the command
+func (c *Command) Usage() string {
+	usage := strings.Join(append([]string{c.Name}, c.Aliases...), "|")
+	if c.Args != "" {
+		usage += " " + c.Args
+	}
+	return usage
+}
+
+// Commands is a registry of commands, which panels register theirs with
+type Commands struct {
+	commands []*Command
+	byName   map[string]*Command
+}
+
+func NewCommands() *Commands {
+	return &Commands{byName: make(map[string]*Command)}
+}
+
+// Register adds cmds, failing when a name or alias is already registered
+func (c *Commands) Register(cmds ...Command) error {
+	for i := range cmds {
+		cmd := &cmds[i]
+		if cmd.Name == "" || cmd.Run == nil {
+			return fmt.Errorf("command %q: missing name or handler", cmd.Usage())
+		}
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			if registered, ok := c.byName[name]; ok {
+				return fmt.Errorf("command %s: %q already names command %s", cmd.Name, name, registered.Name)
+			}
+		}
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			c.byName[name] = cmd
+		}
+		c.commands = append(c.commands, cmd)
+	}
+	return nil
+}
+
+// Lookup returns the command of a name or alias
+func (c *Commands) Lookup(name string) (*Command, bool) {
+	cmd, ok := c.byName[name]
+	return cmd, ok
+}
+
+// List returns the commands in registration order
+func (c *Commands) List() []*Command {
+	return c.commands
+}
+
+// Parse returns the command of a command line and its arguments, separated
+// by spaces. An empty line returns a nil command.
+func (c *Commands) Parse(line string) (*Command, []string, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil, nil, nil
+	}
+	cmd, ok := c.Lookup(fields[0])
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown command %q, ? lists the commands", fields[0])
+	}
+	args := fields[1:]
+	if len(args) < cmd.MinArgs {
+		return nil, nil, fmt.Errorf("%s needs %s", fields[0], cmd.Args)
+	}
+	return cmd, args, nil
+}
+
+// Run runs the command of a command line, see Parse
+func (c *Commands) Run(line string) error {
+	cmd, args, err := c.Parse(line)
+	if err != nil || cmd == nil {
+		return err
+	}
+	cmd.Run(args)
+	return nil
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommands(t *testing.T) {
+	var ran []string
+	commands := NewCommands()
+	err := commands.Register(
+		Command{Name: "nodes", Aliases: []string{"n"}, Args: "[key]", Run: func(args []string) { ran = append([]string{"nodes"}, args...) }},
+		Command{Name: "namespace", Aliases: []string{"-n"}, Args: "<namespace>", MinArgs: 1, Run: func(args []string) { ran = append([]string{"namespace"}, args...) }},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		line string
+		ran  []string
+		err  bool
+	}{
+		{line: "n  -cpu", ran: []string{"nodes", "-cpu"}},
+		{line: "nodes", ran: []string{"nodes"}},
+		{line: "-n kube-system", ran: []string{"namespace", "kube-system"}},
+		{line: "-n", err: true},
+		{line: "nope", err: true},
+		{line: "  ", ran: nil},
+	}
+	for _, test := range tests {
+		ran = nil
+		err := commands.Run(test.line)
+		if (err != nil) != test.err {
+			t.Errorf("%q: unexpected error %v", test.line, err)
+		}
+		if !reflect.DeepEqual(ran, test.ran) {
+			t.Errorf("%q: expecting %v to run, got %v", test.line, test.ran, ran)
+		}
+	}
+
+	if err := commands.Register(Command{Name: "nope", Aliases: []string{"n"}, Run: func([]string) {}}); err == nil {
+		t.Error("expecting a duplicate alias error")
+	}
+	if _, ok := commands.Lookup("nope"); ok {
+		t.Error("expecting a failed registration not to register the command")
+	}
+	if usage := commands.List()[0].Usage(); usage != "nodes|n [key]" {
+		t.Errorf("unexpected usage %q", usage)
+	}
+}
@@ -19,6 +19,8 @@ var (
 		Controller      rune
 		Clock rune
 		TrafficLight rune
+		SortAsc      rune
+		SortDesc     rune
 	}{
 		BargraphChar:    '|',
 		BargraphLBorder: '[',
@@ -37,5 +39,7 @@ var (
 		Controller:      '🛂',
 		Clock: '⏰',
 		TrafficLight: '🚦',
+		SortAsc:      '▲',
+		SortDesc:     '▼',
 	}
 )
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	altPrefix = "Alt+"
+	spaceKey  = "Space"
+)
+
+// Keymap binds keys to command lines, see Commands. Keys are named as by
+// tcell, such as Esc, Tab, F1 or Ctrl-R, by their character, such as j or /,
+// or Space, and prefixed with Alt+ when pressed with Alt.
+type Keymap struct {
+	bindings map[string]string
+}
+
+func NewKeymap() *Keymap {
+	return &Keymap{bindings: make(map[string]string)}
+}
+
+// Bind binds the keys of bindings to their command lines, an empty line
+// removing the binding of its key
+func (k *Keymap) Bind(bindings map[string]string) error {
+	for name, line := range bindings {
+		key, err := ParseKey(name)
+		if err != nil {
+			return err
+		}
+		if line = strings.TrimSpace(line); line == "" {
+			delete(k.bindings, key)
+			continue
+		}
+		k.bindings[key] = line
+	}
+	return nil
+}
+
+// Command returns the command line bound to the key of event
+func (k *Keymap) Command(event *tcell.EventKey) (string, bool) {
+	line, ok := k.bindings[KeyName(event)]
+	return line, ok
+}
+
+// Keys returns the keys bound to lines running the command named name of
+// commands, single characters first
+func (k *Keymap) Keys(commands *Commands, name string) []string {
+	var keys []string
+	for key, line := range k.bindings {
+		if cmd, _, err := commands.Parse(line); err == nil && cmd != nil && cmd.Name == name {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) < len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// Validate checks that the bound lines run commands of commands
+func (k *Keymap) Validate(commands *Commands) error {
+	for key, line := range k.bindings {
+		if _, _, err := commands.Parse(line); err != nil {
+			return fmt.Errorf("key %s: %s", key, err)
+		}
+	}
+	return nil
+}
+
+// KeyName returns the name of the key of event, or "" for a key without name
+func KeyName(event *tcell.EventKey) string {
+	name, ok := tcell.KeyNames[event.Key()]
+	switch {
+	case event.Key() == tcell.KeyRune && event.Rune() == ' ':
+		name = spaceKey
+	case event.Key() == tcell.KeyRune:
+		name = string(event.Rune())
+	case !ok:
+		return ""
+	}
+	if event.Modifiers()&tcell.ModAlt != 0 {
+		name = altPrefix + name
+	}
+	return name
+}
+
+// ParseKey returns the name of a key in the form of KeyName. Names of tcell
+// keys are case insensitive and accept + after Ctrl, such as ctrl+r.
+func ParseKey(name string) (string, error) {
+	key, prefix := name, ""
+	if len([]rune(name)) > 1 && strings.HasPrefix(strings.ToLower(name), strings.ToLower(altPrefix)) {
+		key, prefix = name[len(altPrefix):], altPrefix
+	}
+	if key == " " || strings.EqualFold(key, spaceKey) {
+		return prefix + spaceKey, nil
+	}
+	if len([]rune(key)) == 1 {
+		return prefix + key, nil
+	}
+	if strings.HasPrefix(strings.ToLower(key), "ctrl+") {
+		key = "Ctrl-" + key[len("ctrl+"):]
+	}
+	for _, keyName := range tcell.KeyNames {
+		if strings.EqualFold(keyName, key) {
+			return prefix + keyName, nil
+		}
+	}
+	return "", fmt.Errorf("unknown key %q", name)
+}
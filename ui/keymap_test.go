@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestKeymap(t *testing.T) {
+	commands := NewCommands()
+	for _, name := range []string{"quit", "down", "page"} {
+		if err := commands.Register(Command{Name: name, Run: func([]string) {}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keymap := NewKeymap()
+	if err := keymap.Bind(map[string]string{"Esc": "quit", "j": "down", "ctrl+d": "down", "alt+J": "down", "f2": "page 2", "space": "page 1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := keymap.Validate(commands); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		event *tcell.EventKey
+		line  string
+	}{
+		{event: tcell.NewEventKey(tcell.KeyEsc, 0, tcell.ModNone), line: "quit"},
+		{event: tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModNone), line: "down"},
+		{event: tcell.NewEventKey(tcell.KeyRune, 'J', tcell.ModAlt), line: "down"},
+		{event: tcell.NewEventKey(tcell.KeyCtrlD, 0, tcell.ModCtrl), line: "down"},
+		{event: tcell.NewEventKey(tcell.KeyF2, 0, tcell.ModNone), line: "page 2"},
+		{event: tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone), line: "page 1"},
+		{event: tcell.NewEventKey(tcell.KeyRune, 'k', tcell.ModNone)},
+	}
+	for _, test := range tests {
+		if line, _ := keymap.Command(test.event); line != test.line {
+			t.Errorf("%s: expecting %q, got %q", test.event.Name(), test.line, line)
+		}
+	}
+	if keys := keymap.Keys(commands, "down"); !reflect.DeepEqual(keys, []string{"j", "Alt+J", "Ctrl-D"}) {
+		t.Errorf("unexpected keys of down %v", keys)
+	}
+
+	if err := keymap.Bind(map[string]string{"j": ""}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := keymap.Command(tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModNone)); ok {
+		t.Error("expecting j to be unbound")
+	}
+	if err := keymap.Bind(map[string]string{"Hyper-X": "quit"}); err == nil {
+		t.Error("expecting an unknown key error")
+	}
+	if err := keymap.Bind(map[string]string{"x": "nope"}); err != nil || keymap.Validate(commands) == nil {
+		t.Error("expecting a validation error of an unknown command")
+	}
+}
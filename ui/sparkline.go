@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"strings"
+)
+
+// sparkBlocks are the block characters of a sparkline, from lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline returns values drawn as a line of width block characters, like
+// ▁▂▅█▃, oldest first. When there are more values than width, consecutive
+// values are merged keeping their maximum so that spikes remain visible.
+// Values are scaled to max, or to the largest value when max is not positive.
+// Fewer values than width are right aligned, padded with spaces.
+func Sparkline(values []float64, width int, max float64) string {
+	if width <= 0 {
+		return ""
+	}
+	values = downsample(values, width)
+
+	if max <= 0 {
+		for _, val := range values {
+			if val > max {
+				max = val
+			}
+		}
+	}
+
+	var line strings.Builder
+	line.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, val := range values {
+		level := 0
+		if max > 0 && val > 0 {
+			level = int(val / max * float64(len(sparkBlocks)-1))
+		}
+		switch {
+		case level < 0:
+			level = 0
+		case level >= len(sparkBlocks):
+			level = len(sparkBlocks) - 1
+		}
+		line.WriteRune(sparkBlocks[level])
+	}
+	return line.String()
+}
+
+// downsample merges values into at most width buckets holding their maximum
+func downsample(values []float64, width int) []float64 {
+	if len(values) <= width {
+		return values
+	}
+	buckets := make([]float64, width)
+	for i, val := range values {
+		bucket := i * width / len(values)
+		if val > buckets[bucket] {
+			buckets[bucket] = val
+		}
+	}
+	return buckets
+}
@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		max    float64
+		line   string
+	}{
+		{name: "no width", values: []float64{1, 2}, width: 0, line: ""},
+		{name: "no values", width: 3, line: "   "},
+		{name: "auto scale", values: []float64{0, 7, 14}, width: 3, line: "▁▄█"},
+		{name: "fixed max", values: []float64{7, 14}, width: 4, max: 28, line: "  ▂▄"},
+		{name: "above max", values: []float64{40}, width: 1, max: 28, line: "█"},
+		{name: "spike kept", values: []float64{1, 1, 1, 14, 1, 1}, width: 3, line: "▁█▁"},
+	}
+
+	for _, test := range tests {
+		if line := Sparkline(test.values, test.width, test.max); line != test.line {
+			t.Errorf("%s: expecting sparkline %q, got %q", test.name, test.line, line)
+		}
+	}
+}
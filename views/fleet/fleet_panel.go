@@ -0,0 +1,234 @@
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/ui"
+	"github.com/pjy0381/ktop/views/model"
+	"github.com/rivo/tview"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const refreshInterval = 5 * time.Second
+
+// FleetPanel is the page listing a summary row per cluster in multi-cluster
+// mode. Selecting a cluster displays it on the detail page.
+type FleetPanel struct {
+	app        *application.Application
+	title      string
+	detailPage string
+	refresh    func()
+	root       *tview.Flex
+	children   []tview.Primitive
+	listCols   []string
+	list       *tview.Table
+	laidout    bool
+	models     []model.FleetClusterModel
+}
+
+// New returns the fleet page, drilling down into detailPage
+func New(app *application.Application, title, detailPage string) *FleetPanel {
+	p := &FleetPanel{app: app, title: title, detailPage: detailPage, refresh: app.Refresh}
+	p.Layout(nil)
+	p.DrawHeader([]string{"CONTEXT", "STATUS", "VERSION", "NODES", "PODS", "CPU", "MEMORY", "CONTROL PLANE", "ETCD"})
+	return p
+}
+
+func (p *FleetPanel) GetTitle() string {
+	return p.title
+}
+
+func (p *FleetPanel) Layout(_ interface{}) {
+	if !p.laidout {
+		p.list = tview.NewTable()
+		p.list.SetFixed(1, 0)
+		p.list.SetBorder(false)
+		p.list.SetBorders(false)
+		p.list.SetSelectable(true, false)
+		p.list.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorYellow).Foreground(tcell.ColorBlue))
+		p.list.SetSelectedFunc(p.drillDown)
+
+		p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(p.list, 0, 1, true)
+		p.root.SetBorder(true)
+		p.root.SetTitle(fmt.Sprintf(" %c %s ", ui.Icons.Factory, p.GetTitle()))
+		p.root.SetTitleAlign(tview.AlignLeft)
+		p.children = append(p.children, p.list)
+		p.laidout = true
+	}
+}
+
+func (p *FleetPanel) DrawHeader(data interface{}) {
+	cols, ok := data.([]string)
+	if !ok {
+		panic(fmt.Sprintf("FleetPanel.DrawHeader got unexpected data type %T", data))
+	}
+
+	p.listCols = cols
+	for i, col := range p.listCols {
+		p.list.SetCell(0, i,
+			tview.NewTableCell(col).
+				SetTextColor(tcell.ColorBlack).
+				SetBackgroundColor(tcell.ColorDarkGray).
+				SetAlign(tview.AlignLeft).
+				SetExpansion(100).
+				SetSelectable(false),
+		)
+	}
+	p.list.SetFixed(1, 0)
+}
+
+// DrawBody draws a row per cluster, the displayed cluster marked with *
+func (p *FleetPanel) DrawBody(data interface{}) {
+	models, ok := data.([]model.FleetClusterModel)
+	if !ok {
+		panic(fmt.Sprintf("FleetPanel.DrawBody got unexpected type %T", data))
+	}
+	p.models = models
+
+	up := 0
+	for i, cluster := range models {
+		if cluster.State == model.ClusterUp {
+			up++
+		}
+
+		name := cluster.Context
+		if cluster.Current {
+			name = "* " + name
+		}
+		status := string(cluster.State)
+		if cluster.Error != "" {
+			status = fmt.Sprintf("%s: %s", cluster.State, cluster.Error)
+		}
+
+		cells := []string{name, status, cluster.Version, "", "", "", "", "", ""}
+		if summary := cluster.Summary; summary != nil {
+			cells[3] = fmt.Sprintf("%s%d[white]/%d", countColor(summary.NodesReady, summary.NodesCount), summary.NodesReady, summary.NodesCount)
+			cells[4] = fmt.Sprintf("%s%d[white]/%d", countColor(summary.PodsRunning, summary.PodsAvailable), summary.PodsRunning, summary.PodsAvailable)
+			cells[5] = usageText(summary.UsageNodeCpuTotal, summary.RequestedPodCpuTotal, summary.AllocatableNodeCpuTotal)
+			cells[6] = usageText(summary.UsageNodeMemTotal, summary.RequestedPodMemTotal, summary.AllocatableNodeMemTotal)
+			cpTotal := summary.ControlPlaneHealthy + summary.ControlPlaneUnhealthy
+			cells[7] = fmt.Sprintf("%s%d[white]/%d", countColor(summary.ControlPlaneHealthy, cpTotal), summary.ControlPlaneHealthy, cpTotal)
+			cells[8] = fmt.Sprintf("%s%d[white]/%d", countColor(summary.EtcdReady, summary.EtcdCount), summary.EtcdReady, summary.EtcdCount)
+		}
+
+		for col, text := range cells {
+			color := tcell.ColorWhite
+			if col == 1 {
+				color = stateColor(cluster.State)
+			}
+			p.list.SetCell(
+				i+1, col,
+				&tview.TableCell{
+					Text:  text,
+					Color: color,
+					Align: tview.AlignLeft,
+				},
+			)
+		}
+	}
+
+	p.root.SetTitle(fmt.Sprintf(" %c %s (%d/%d up) ", ui.Icons.Factory, p.GetTitle(), up, len(models)))
+}
+
+func (p *FleetPanel) DrawFooter(_ interface{}) {}
+
+func (p *FleetPanel) Clear() {
+	p.list.Clear()
+	p.Layout(nil)
+	p.DrawHeader(p.listCols)
+}
+
+func (p *FleetPanel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+func (p *FleetPanel) GetChildrenViews() []tview.Primitive {
+	return p.children
+}
+
+// Run starts the controllers of the fleet clusters and redraws their
+// summary rows periodically
+func (p *FleetPanel) Run(ctx context.Context) error {
+	fleet := p.app.GetFleet()
+	if fleet == nil {
+		return fmt.Errorf("fleet panel: multi-cluster mode not enabled")
+	}
+	fleet.Start(ctx, time.Second*1)
+
+	go func() {
+		p.refreshFleet()
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.refreshFleet()
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (p *FleetPanel) refreshFleet() {
+	models := p.app.GetFleet().GetFleetModels(p.app.GetK8sClient())
+	p.Clear()
+	p.DrawBody(models)
+	if p.refresh != nil {
+		p.refresh()
+	}
+}
+
+// drillDown displays the selected cluster on the detail page
+func (p *FleetPanel) drillDown(row, _ int) {
+	if row < 1 || row > len(p.models) {
+		return
+	}
+	member := p.app.GetFleet().Member(p.models[row-1].Context)
+	if member == nil || member.Client() == nil {
+		return
+	}
+	p.app.SetK8sClient(member.Client())
+	p.app.ShowPage(p.detailPage)
+	p.refreshFleet()
+}
+
+func countColor(ready, total int) string {
+	if ready < total {
+		return "[red]"
+	}
+	return "[green]"
+}
+
+func stateColor(state model.ClusterState) tcell.Color {
+	switch state {
+	case model.ClusterUp:
+		return tcell.ColorGreen
+	case model.ClusterDown, model.ClusterStale:
+		return tcell.ColorRed
+	default:
+		return tcell.ColorYellow
+	}
+}
+
+// usageText returns the usage percentage of allocatable, or the requested
+// percentage when no usage metrics are available
+func usageText(usage, requested, allocatable *resource.Quantity) string {
+	if allocatable == nil || allocatable.IsZero() {
+		return "n/a"
+	}
+	if usage != nil && !usage.IsZero() {
+		return fmt.Sprintf("%.0f%%", ui.GetRatio(float64(usage.MilliValue()), float64(allocatable.MilliValue()))*100)
+	}
+	if requested != nil {
+		return fmt.Sprintf("%.0f%% req", ui.GetRatio(float64(requested.MilliValue()), float64(allocatable.MilliValue()))*100)
+	}
+	return "n/a"
+}
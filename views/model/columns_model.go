@@ -0,0 +1,76 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ApplyColumns returns the column ids of a table after args, applied in
+// order to current: a list of ids, comma or space separated, replaces the
+// columns, +id adds a column at the end, -id hides one and "default"
+// restores defaults. Ids are checked against known.
+func ApplyColumns(current, defaults, known []string, args []string) ([]string, error) {
+	columns := append([]string{}, current...)
+	listing := false
+	for _, arg := range args {
+		switch {
+		case arg == "":
+		case arg == "default":
+			columns = append([]string{}, defaults...)
+			listing = false
+		case strings.HasPrefix(arg, "+"):
+			id := arg[1:]
+			if err := checkColumn(id, known); err != nil {
+				return current, err
+			}
+			if !containsKey(columns, id) {
+				columns = append(columns, id)
+			}
+			listing = false
+		case strings.HasPrefix(arg, "-"):
+			id := arg[1:]
+			if err := checkColumn(id, known); err != nil {
+				return current, err
+			}
+			columns = removeColumn(columns, id)
+			listing = false
+		default:
+			if !listing {
+				columns = nil
+				listing = true
+			}
+			for _, id := range strings.Split(arg, ",") {
+				if id == "" {
+					continue
+				}
+				if err := checkColumn(id, known); err != nil {
+					return current, err
+				}
+				if !containsKey(columns, id) {
+					columns = append(columns, id)
+				}
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return current, fmt.Errorf("no column left to draw")
+	}
+	return columns, nil
+}
+
+func checkColumn(id string, known []string) error {
+	if !containsKey(known, id) {
+		return fmt.Errorf("unknown column %q, expecting one of %s", id, strings.Join(known, ", "))
+	}
+	return nil
+}
+
+func removeColumn(columns []string, id string) []string {
+	kept := columns[:0]
+	for _, column := range columns {
+		if column != id {
+			kept = append(kept, column)
+		}
+	}
+	return kept
+}
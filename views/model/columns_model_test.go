@@ -0,0 +1,37 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyColumns(t *testing.T) {
+	known := []string{"ns", "name", "status", "cpu", "mem", "qos", "image"}
+	defaults := []string{"ns", "name", "status", "cpu", "mem"}
+	tests := []struct {
+		args    []string
+		columns []string
+	}{
+		{args: []string{"name,cpu,ns"}, columns: []string{"name", "cpu", "ns"}},
+		{args: []string{"name", "cpu", "cpu"}, columns: []string{"name", "cpu"}},
+		{args: []string{"+qos", "-ns"}, columns: []string{"name", "status", "cpu", "mem", "qos"}},
+		{args: []string{"+name"}, columns: defaults},
+		{args: []string{"image", "+qos", "name"}, columns: []string{"name"}},
+		{args: []string{"default", "+image"}, columns: []string{"ns", "name", "status", "cpu", "mem", "image"}},
+	}
+	for _, test := range tests {
+		columns, err := ApplyColumns(defaults, defaults, known, test.args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(columns, test.columns) {
+			t.Errorf("%v: expecting %v, got %v", test.args, test.columns, columns)
+		}
+	}
+
+	for _, args := range [][]string{{"+bogus"}, {"name,bogus"}, {"-ns", "-name", "-status", "-cpu", "-mem"}} {
+		if columns, err := ApplyColumns(defaults, defaults, known, args); err == nil || !reflect.DeepEqual(columns, defaults) {
+			t.Errorf("%v: expecting an error keeping the columns, got %v %v", args, columns, err)
+		}
+	}
+}
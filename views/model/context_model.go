@@ -0,0 +1,10 @@
+package model
+
+// ContextModel is a context of the kubeconfig
+type ContextModel struct {
+	Name      string
+	Cluster   string
+	User      string
+	Namespace string
+	Current   bool
+}
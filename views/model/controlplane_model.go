@@ -0,0 +1,36 @@
+package model
+
+// ControlPlaneComponent is a control plane component instance, such as a
+// kube-scheduler static pod running on a control-plane node
+type ControlPlaneComponent struct {
+	Name    string
+	Node    string
+	Healthy bool
+	Status  string
+}
+
+// ControlPlaneCheck is a named check reported by the API server
+// /readyz or /livez endpoints
+type ControlPlaneCheck struct {
+	Endpoint string
+	Name     string
+	Passed   bool
+	Message  string
+}
+
+type ControlPlaneModel struct {
+	Components []ControlPlaneComponent
+	Checks     []ControlPlaneCheck
+}
+
+// Healthy returns the number of healthy and unhealthy components
+func (m ControlPlaneModel) Healthy() (healthy, unhealthy int) {
+	for _, comp := range m.Components {
+		if comp.Healthy {
+			healthy++
+		} else {
+			unhealthy++
+		}
+	}
+	return
+}
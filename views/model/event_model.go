@@ -0,0 +1,117 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const EventTypeWarning = v1.EventTypeWarning
+
+type EventModel struct {
+	Type      string
+	Reason    string
+	Namespace string
+	Kind      string
+	Name      string
+	Object    string
+	Message   string
+	Count     int
+	LastSeen  metav1.Time
+	Age       string
+}
+
+// EventFilter selects events by type and involved object. Empty fields match all events.
+type EventFilter struct {
+	WarningsOnly bool
+	Namespace    string
+	Kind         string
+	Name         string
+}
+
+func (f EventFilter) Match(event EventModel) bool {
+	if f.WarningsOnly && event.Type != EventTypeWarning {
+		return false
+	}
+	if f.Namespace != "" && event.Namespace != f.Namespace {
+		return false
+	}
+	if f.Kind != "" && !strings.EqualFold(event.Kind, f.Kind) {
+		return false
+	}
+	return f.Name == "" || event.Name == f.Name
+}
+
+// IsEmpty reports whether the filter matches all events
+func (f EventFilter) IsEmpty() bool {
+	return f == EventFilter{}
+}
+
+func (f EventFilter) String() string {
+	var terms []string
+	if f.WarningsOnly {
+		terms = append(terms, "warnings")
+	}
+	if f.Namespace != "" {
+		terms = append(terms, "ns:"+f.Namespace)
+	}
+	if f.Kind != "" || f.Name != "" {
+		terms = append(terms, f.Kind+"/"+f.Name)
+	}
+	return strings.Join(terms, " ")
+}
+
+// FilterEventModels returns the events matched by filter, in order
+func FilterEventModels(events []EventModel, filter EventFilter) []EventModel {
+	if filter.IsEmpty() {
+		return events
+	}
+	var matched []EventModel
+	for _, event := range events {
+		if filter.Match(event) {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+// NewEventModels returns the events most recent first
+func NewEventModels(events []*v1.Event) []EventModel {
+	models := make([]EventModel, 0, len(events))
+	for _, event := range events {
+		models = append(models, NewEventModel(event))
+	}
+	sort.SliceStable(models, func(i, j int) bool {
+		return models[i].LastSeen.After(models[j].LastSeen.Time)
+	})
+	return models
+}
+
+func NewEventModel(event *v1.Event) EventModel {
+	lastSeen := event.LastTimestamp
+	if lastSeen.IsZero() {
+		lastSeen = metav1.NewTime(event.EventTime.Time)
+	}
+	if lastSeen.IsZero() {
+		lastSeen = event.FirstTimestamp
+	}
+	count := int(event.Count)
+	if event.Series != nil {
+		count = int(event.Series.Count)
+	}
+	return EventModel{
+		Type:      event.Type,
+		Reason:    event.Reason,
+		Namespace: event.Namespace,
+		Kind:      event.InvolvedObject.Kind,
+		Name:      event.InvolvedObject.Name,
+		Object:    fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
+		Message:   event.Message,
+		Count:     count,
+		LastSeen:  lastSeen,
+		Age:       timeSince(lastSeen),
+	}
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFilterEventModels(t *testing.T) {
+	events := NewEventModels([]*v1.Event{
+		{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "web"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "api-1"},
+			Type:           v1.EventTypeWarning, Reason: "BackOff",
+		},
+		{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "web"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "api-2"},
+			Type:           v1.EventTypeNormal, Reason: "Pulled",
+		},
+		{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "default"},
+			InvolvedObject: v1.ObjectReference{Kind: "Node", Name: "worker-1"},
+			Type:           v1.EventTypeWarning, Reason: "NodeNotReady",
+		},
+	})
+
+	tests := []struct {
+		name     string
+		filter   EventFilter
+		expected int
+	}{
+		{name: "all", filter: EventFilter{}, expected: 3},
+		{name: "warnings", filter: EventFilter{WarningsOnly: true}, expected: 2},
+		{name: "namespace", filter: EventFilter{Namespace: "web"}, expected: 2},
+		{name: "warnings in namespace", filter: EventFilter{WarningsOnly: true, Namespace: "web"}, expected: 1},
+		{name: "pod", filter: EventFilter{Namespace: "web", Kind: "Pod", Name: "api-2"}, expected: 1},
+		{name: "node", filter: EventFilter{Kind: "node", Name: "worker-1"}, expected: 1},
+		{name: "no match", filter: EventFilter{Kind: "Pod", Name: "worker-1"}, expected: 0},
+	}
+	for _, test := range tests {
+		if matched := FilterEventModels(events, test.filter); len(matched) != test.expected {
+			t.Errorf("%s: expecting %d events, got %d", test.name, test.expected, len(matched))
+		}
+	}
+
+	if text := (EventFilter{WarningsOnly: true, Kind: "Pod", Name: "api-1"}).String(); text != "warnings Pod/api-1" {
+		t.Errorf("unexpected filter text %q", text)
+	}
+}
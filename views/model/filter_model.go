@@ -0,0 +1,182 @@
+package model
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Prefixes of the filter terms matching a single field
+const (
+	FilterNamespace = "ns"
+	FilterNode      = "node"
+	FilterStatus    = "status"
+)
+
+// Filter narrows pods and nodes to those matching all of its terms. A term
+// is a case-insensitive regular expression, or a substring when it is not a
+// valid one. A term prefixed with ns:, node: or status: matches that field,
+// other terms match the name, namespace, node, status or IPs.
+type Filter struct {
+	text  string
+	terms []filterTerm
+}
+
+type filterTerm struct {
+	field string // "" for terms matching any field
+	re    *regexp.Regexp
+}
+
+// NewFilter returns the filter of the whitespace separated terms of text, or
+// nil when text has no terms
+func NewFilter(text string) *Filter {
+	filter := &Filter{text: strings.TrimSpace(text)}
+	for _, term := range strings.Fields(text) {
+		field := ""
+		for _, prefix := range []string{FilterNamespace, FilterNode, FilterStatus} {
+			if value := strings.TrimPrefix(term, prefix+":"); value != term {
+				field, term = prefix, value
+				break
+			}
+		}
+		if term == "" {
+			continue
+		}
+		re, err := regexp.Compile("(?i)" + term)
+		if err != nil {
+			re = regexp.MustCompile("(?i)" + regexp.QuoteMeta(term))
+		}
+		filter.terms = append(filter.terms, filterTerm{field: field, re: re})
+	}
+	if len(filter.terms) == 0 {
+		return nil
+	}
+	return filter
+}
+
+// String returns the text of the filter
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.text
+}
+
+// MatchPod reports whether pod matches all the terms of the filter
+func (f *Filter) MatchPod(pod PodModel) bool {
+	if f == nil {
+		return true
+	}
+	for _, term := range f.terms {
+		var ok bool
+		switch term.field {
+		case FilterNamespace:
+			ok = term.re.MatchString(pod.Namespace)
+		case FilterNode:
+			ok = term.re.MatchString(pod.Node)
+		case FilterStatus:
+			ok = term.re.MatchString(pod.Status)
+		default:
+			ok = matchAny(term.re, pod.Name, pod.Namespace, pod.Node, pod.Status, pod.IP)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchNode reports whether node matches all the terms of the filter. Nodes
+// have no namespace, ns: terms are ignored.
+func (f *Filter) MatchNode(node NodeModel) bool {
+	if f == nil {
+		return true
+	}
+	for _, term := range f.terms {
+		var ok bool
+		switch term.field {
+		case FilterNamespace:
+			ok = true
+		case FilterNode:
+			ok = term.re.MatchString(node.Name)
+		case FilterStatus:
+			ok = term.re.MatchString(node.Status)
+		default:
+			ok = matchAny(term.re, node.Name, node.Status, node.InternalIP, node.ExternalIP)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAny(re *regexp.Regexp, values ...string) bool {
+	for _, value := range values {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches returns the ranges of text matched by the terms of field, such as
+// FilterNamespace, and the terms matching any field, merged and in order.
+// Field "" only takes the terms matching any field.
+func (f *Filter) Matches(field, text string) [][]int {
+	if f == nil || text == "" {
+		return nil
+	}
+	var matches [][]int
+	for _, term := range f.terms {
+		if term.field == "" || term.field == field {
+			matches = append(matches, term.re.FindAllStringIndex(text, -1)...)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i][0] < matches[j][0]
+	})
+
+	var merged [][]int
+	for _, match := range matches {
+		if match[0] == match[1] {
+			continue
+		}
+		if last := len(merged) - 1; last >= 0 && match[0] <= merged[last][1] {
+			if match[1] > merged[last][1] {
+				merged[last][1] = match[1]
+			}
+			continue
+		}
+		merged = append(merged, []int{match[0], match[1]})
+	}
+	return merged
+}
+
+// FilterPods returns the pods matching filter
+func FilterPods(pods []PodModel, filter *Filter) []PodModel {
+	if filter == nil {
+		return pods
+	}
+	var matching []PodModel
+	for _, pod := range pods {
+		if filter.MatchPod(pod) {
+			matching = append(matching, pod)
+		}
+	}
+	return matching
+}
+
+// FilterNodes returns the nodes matching filter
+func FilterNodes(nodes []NodeModel, filter *Filter) []NodeModel {
+	if filter == nil {
+		return nodes
+	}
+	var matching []NodeModel
+	for _, node := range nodes {
+		if filter.MatchNode(node) {
+			matching = append(matching, node)
+		}
+	}
+	return matching
+}
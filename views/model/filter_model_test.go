@@ -0,0 +1,64 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterPods(t *testing.T) {
+	pods := []PodModel{
+		{Namespace: "web", Name: "api-7d9f", Node: "node-1", Status: "Running", IP: "10.0.0.4"},
+		{Namespace: "web", Name: "frontend-1", Node: "node-2", Status: "CrashLoopBackOff"},
+		{Namespace: "kube-system", Name: "coredns-1", Node: "node-1", Status: "Running"},
+	}
+	tests := []struct {
+		filter string
+		names  []string
+	}{
+		{filter: "api", names: []string{"api-7d9f"}},
+		{filter: "API", names: []string{"api-7d9f"}},
+		{filter: "^(api|front)", names: []string{"api-7d9f", "frontend-1"}},
+		{filter: "ns:web", names: []string{"api-7d9f", "frontend-1"}},
+		{filter: "node:node-1 ns:kube", names: []string{"coredns-1"}},
+		{filter: "status:crash", names: []string{"frontend-1"}},
+		{filter: "10.0.0", names: []string{"api-7d9f"}},
+		{filter: "web end-1$", names: []string{"frontend-1"}},
+		{filter: "[", names: nil},
+		{filter: "ns:", names: []string{"api-7d9f", "frontend-1", "coredns-1"}},
+	}
+	for _, test := range tests {
+		var names []string
+		for _, pod := range FilterPods(pods, NewFilter(test.filter)) {
+			names = append(names, pod.Name)
+		}
+		if !reflect.DeepEqual(names, test.names) {
+			t.Errorf("filter %q: expecting %v, got %v", test.filter, test.names, names)
+		}
+	}
+}
+
+func TestFilterNodes(t *testing.T) {
+	nodes := []NodeModel{
+		{Name: "node-1", Status: "Ready"},
+		{Name: "node-2", Status: "NotReady"},
+	}
+	if matching := FilterNodes(nodes, NewFilter("status:^not ns:web")); len(matching) != 1 || matching[0].Name != "node-2" {
+		t.Errorf("expecting node-2, ignoring the namespace term, got %+v", matching)
+	}
+	if matching := FilterNodes(nodes, NewFilter("  ")); len(matching) != 2 {
+		t.Errorf("expecting a blank filter to keep all nodes, got %+v", matching)
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	filter := NewFilter("ap pi ns:web")
+	if matches := filter.Matches("", "api-api"); !reflect.DeepEqual(matches, [][]int{{0, 3}, {4, 7}}) {
+		t.Errorf("expecting merged matches, got %v", matches)
+	}
+	if matches := filter.Matches(FilterNamespace, "web"); !reflect.DeepEqual(matches, [][]int{{0, 3}}) {
+		t.Errorf("expecting the namespace term to match, got %v", matches)
+	}
+	if matches := filter.Matches("", "web"); matches != nil {
+		t.Errorf("expecting the namespace term to only match namespaces, got %v", matches)
+	}
+}
@@ -0,0 +1,23 @@
+package model
+
+// ClusterState is the reachability of a cluster in multi-cluster mode
+type ClusterState string
+
+const (
+	ClusterUp         ClusterState = "up"
+	ClusterDown       ClusterState = "down"
+	ClusterConnecting ClusterState = "connecting"
+	ClusterSyncing    ClusterState = "syncing"
+	// ClusterStale is a cluster whose summary stopped refreshing
+	ClusterStale ClusterState = "stale"
+)
+
+// FleetClusterModel is the fleet summary row of a cluster
+type FleetClusterModel struct {
+	Context string
+	Current bool // the cluster displayed by the overview
+	State   ClusterState
+	Error   string
+	Version string
+	Summary *ClusterSummary
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"time"
+)
+
+// MetricsSample is the cpu, in millicores, and the memory, in bytes, used at a time
+type MetricsSample struct {
+	Time time.Time
+	Cpu  int64
+	Mem  int64
+}
+
+// MetricsSamples are samples oldest first
+type MetricsSamples []MetricsSample
+
+// Cpu returns the cpu of the samples in millicores
+func (s MetricsSamples) Cpu() []float64 {
+	values := make([]float64, len(s))
+	for i, sample := range s {
+		values[i] = float64(sample.Cpu)
+	}
+	return values
+}
+
+// Mem returns the memory of the samples in bytes
+func (s MetricsSamples) Mem() []float64 {
+	values := make([]float64, len(s))
+	for i, sample := range s {
+		values[i] = float64(sample.Mem)
+	}
+	return values
+}
+
+// MetricsHistory keeps the most recent samples of an object in a ring
+// buffer. Samples added within interval of the last one are merged into it,
+// keeping the maximum, so that short spikes are not lost.
+type MetricsHistory struct {
+	interval time.Duration
+	samples  []MetricsSample
+	start    int
+	count    int
+}
+
+// NewMetricsHistory returns a history of size samples, interval apart
+func NewMetricsHistory(size int, interval time.Duration) *MetricsHistory {
+	if size < 1 {
+		size = 1
+	}
+	return &MetricsHistory{interval: interval, samples: make([]MetricsSample, size)}
+}
+
+// Add records sample, overwriting the oldest sample once the history is full
+func (h *MetricsHistory) Add(sample MetricsSample) {
+	if h.count > 0 {
+		last := &h.samples[(h.start+h.count-1)%len(h.samples)]
+		if sample.Time.Sub(last.Time) < h.interval {
+			if sample.Cpu > last.Cpu {
+				last.Cpu = sample.Cpu
+			}
+			if sample.Mem > last.Mem {
+				last.Mem = sample.Mem
+			}
+			return
+		}
+	}
+
+	if h.count < len(h.samples) {
+		h.samples[(h.start+h.count)%len(h.samples)] = sample
+		h.count++
+		return
+	}
+	h.samples[h.start] = sample
+	h.start = (h.start + 1) % len(h.samples)
+}
+
+// Samples returns a copy of the samples, oldest first
+func (h *MetricsHistory) Samples() MetricsSamples {
+	samples := make(MetricsSamples, h.count)
+	for i := range samples {
+		samples[i] = h.samples[(h.start+i)%len(h.samples)]
+	}
+	return samples
+}
+
+// Last returns the most recent sample, if any
+func (h *MetricsHistory) Last() (MetricsSample, bool) {
+	if h.count == 0 {
+		return MetricsSample{}, false
+	}
+	return h.samples[(h.start+h.count-1)%len(h.samples)], true
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetricsHistory(t *testing.T) {
+	start := time.Now()
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+
+	history := NewMetricsHistory(3, 10*time.Second)
+	if _, ok := history.Last(); ok || len(history.Samples()) != 0 {
+		t.Fatal("expecting an empty history")
+	}
+
+	history.Add(MetricsSample{Time: at(0), Cpu: 100, Mem: 10})
+	// a spike within the interval is merged into the last sample
+	history.Add(MetricsSample{Time: at(5), Cpu: 900, Mem: 5})
+	samples := history.Samples()
+	if len(samples) != 1 || samples[0].Cpu != 900 || samples[0].Mem != 10 {
+		t.Fatalf("expecting a single merged sample, got %+v", samples)
+	}
+
+	history.Add(MetricsSample{Time: at(10), Cpu: 200})
+	history.Add(MetricsSample{Time: at(20), Cpu: 300})
+	history.Add(MetricsSample{Time: at(30), Cpu: 400})
+	cpu := history.Samples().Cpu()
+	if len(cpu) != 3 || cpu[0] != 200 || cpu[1] != 300 || cpu[2] != 400 {
+		t.Errorf("expecting the oldest sample overwritten, got %v", cpu)
+	}
+	if last, ok := history.Last(); !ok || last.Cpu != 400 {
+		t.Errorf("expecting last sample of 400m, got %+v", last)
+	}
+}
@@ -0,0 +1,158 @@
+package model
+
+import (
+	"sort"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+type TaintModel struct {
+	Key    string
+	Value  string
+	Effect string
+}
+
+// ResourceModel compares the capacity of a node resource with what is
+// allocatable to pods, requested by them and used. Requested and Used are
+// nil when not tracked for the resource.
+type ResourceModel struct {
+	Name        string
+	Capacity    *resource.Quantity
+	Allocatable *resource.Quantity
+	Requested   *resource.Quantity
+	Used        *resource.Quantity
+}
+
+type ImageModel struct {
+	Name      string
+	SizeBytes int64
+}
+
+type AttachedVolumeModel struct {
+	Name       string
+	DevicePath string
+	InUse      bool
+}
+
+// NodeDetailModel is a node described with the pods scheduled on it
+type NodeDetailModel struct {
+	NodeModel
+	SystemInfo  coreV1.NodeSystemInfo
+	Conditions  []ConditionModel
+	Taints      []TaintModel
+	Labels      map[string]string
+	Annotations map[string]string
+	Resources   []ResourceModel
+	Volumes     []AttachedVolumeModel
+	Images      []ImageModel
+	Pods        []PodModel
+}
+
+// standard node resources listed before extended resources
+var nodeResourceOrder = []coreV1.ResourceName{
+	coreV1.ResourceCPU, coreV1.ResourceMemory, coreV1.ResourceEphemeralStorage, coreV1.ResourcePods,
+}
+
+// NewNodeDetailModel describes node, where nodeModel carries the pod requests
+// and pods are the models of the pods scheduled on the node
+func NewNodeDetailModel(node *coreV1.Node, nodeModel NodeModel, metrics *v1beta1.NodeMetrics, pods []PodModel) *NodeDetailModel {
+	detail := &NodeDetailModel{
+		NodeModel:   nodeModel,
+		SystemInfo:  node.Status.NodeInfo,
+		Labels:      node.Labels,
+		Annotations: node.Annotations,
+		Pods:        pods,
+	}
+
+	for _, cond := range node.Status.Conditions {
+		detail.Conditions = append(detail.Conditions, ConditionModel{
+			Type:               string(cond.Type),
+			Status:             string(cond.Status),
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+			LastTransitionTime: cond.LastTransitionTime,
+		})
+	}
+
+	for _, taint := range node.Spec.Taints {
+		detail.Taints = append(detail.Taints, TaintModel{Key: taint.Key, Value: taint.Value, Effect: string(taint.Effect)})
+	}
+
+	detail.Resources = nodeResources(node, nodeModel, metrics, len(pods))
+
+	inUse := make(map[coreV1.UniqueVolumeName]bool)
+	for _, name := range node.Status.VolumesInUse {
+		inUse[name] = true
+	}
+	for _, vol := range node.Status.VolumesAttached {
+		detail.Volumes = append(detail.Volumes, AttachedVolumeModel{
+			Name:       string(vol.Name),
+			DevicePath: vol.DevicePath,
+			InUse:      inUse[vol.Name],
+		})
+	}
+
+	for _, image := range node.Status.Images {
+		name := "<none>"
+		if len(image.Names) > 0 {
+			// the last name is usually the tag rather than the digest
+			name = image.Names[len(image.Names)-1]
+		}
+		detail.Images = append(detail.Images, ImageModel{Name: name, SizeBytes: image.SizeBytes})
+	}
+	sort.Slice(detail.Images, func(i, j int) bool {
+		return detail.Images[i].SizeBytes > detail.Images[j].SizeBytes
+	})
+
+	return detail
+}
+
+func nodeResources(node *coreV1.Node, nodeModel NodeModel, metrics *v1beta1.NodeMetrics, podCount int) []ResourceModel {
+	names := make(map[coreV1.ResourceName]bool)
+	for name := range node.Status.Capacity {
+		names[name] = true
+	}
+	for name := range node.Status.Allocatable {
+		names[name] = true
+	}
+
+	var extended []coreV1.ResourceName
+	for name := range names {
+		standard := false
+		for _, std := range nodeResourceOrder {
+			standard = standard || name == std
+		}
+		if !standard {
+			extended = append(extended, name)
+		}
+	}
+	sort.Slice(extended, func(i, j int) bool { return extended[i] < extended[j] })
+
+	var resources []ResourceModel
+	for _, name := range append(append([]coreV1.ResourceName{}, nodeResourceOrder...), extended...) {
+		if !names[name] {
+			continue
+		}
+		capacity := node.Status.Capacity[name]
+		allocatable := node.Status.Allocatable[name]
+		res := ResourceModel{Name: string(name), Capacity: &capacity, Allocatable: &allocatable}
+		switch name {
+		case coreV1.ResourceCPU:
+			res.Requested = nodeModel.RequestedPodCpuQty
+			if metrics != nil {
+				res.Used = metrics.Usage.Cpu()
+			}
+		case coreV1.ResourceMemory:
+			res.Requested = nodeModel.RequestedPodMemQty
+			if metrics != nil {
+				res.Used = metrics.Usage.Memory()
+			}
+		case coreV1.ResourcePods:
+			res.Used = resource.NewQuantity(int64(podCount), resource.DecimalSI)
+		}
+		resources = append(resources, res)
+	}
+	return resources
+}
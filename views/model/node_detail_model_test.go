@@ -0,0 +1,99 @@
+package model
+
+import (
+	"testing"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+func TestNewNodeDetailModel(t *testing.T) {
+	node := &coreV1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: map[string]string{"zone": "a"}},
+		Spec: coreV1.NodeSpec{Taints: []coreV1.Taint{
+			{Key: "gpu", Value: "true", Effect: coreV1.TaintEffectNoSchedule},
+		}},
+		Status: coreV1.NodeStatus{
+			Capacity: coreV1.ResourceList{
+				"nvidia.com/gpu":      resource.MustParse("2"),
+				coreV1.ResourcePods:   resource.MustParse("110"),
+				coreV1.ResourceCPU:    resource.MustParse("4"),
+				coreV1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+			Allocatable: coreV1.ResourceList{
+				coreV1.ResourcePods:   resource.MustParse("110"),
+				coreV1.ResourceCPU:    resource.MustParse("3800m"),
+				coreV1.ResourceMemory: resource.MustParse("15Gi"),
+			},
+			Conditions: []coreV1.NodeCondition{
+				{Type: coreV1.NodeReady, Status: coreV1.ConditionTrue},
+				{Type: coreV1.NodeDiskPressure, Status: coreV1.ConditionFalse},
+			},
+			Images: []coreV1.ContainerImage{
+				{Names: []string{"nginx@sha256:abc", "nginx:1.21"}, SizeBytes: 100},
+				{Names: []string{"pause:3.6"}, SizeBytes: 300},
+			},
+			VolumesAttached: []coreV1.AttachedVolume{{Name: "csi/vol-1", DevicePath: "/dev/xvdb"}},
+			VolumesInUse:    []coreV1.UniqueVolumeName{"csi/vol-1"},
+		},
+	}
+	nodeModel := NodeModel{
+		Name:               node.Name,
+		RequestedPodCpuQty: resource.NewMilliQuantity(500, resource.DecimalSI),
+		RequestedPodMemQty: resource.NewQuantity(0, resource.BinarySI),
+	}
+	metrics := &v1beta1.NodeMetrics{Usage: coreV1.ResourceList{coreV1.ResourceCPU: resource.MustParse("1200m")}}
+	pods := []PodModel{{Name: "a"}, {Name: "b"}}
+
+	detail := NewNodeDetailModel(node, nodeModel, metrics, pods)
+
+	var names []string
+	for _, res := range detail.Resources {
+		names = append(names, res.Name)
+	}
+	expected := []string{"cpu", "memory", "pods", "nvidia.com/gpu"}
+	if len(names) != len(expected) {
+		t.Fatalf("expecting resources %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("expecting resources %v, got %v", expected, names)
+		}
+	}
+
+	cpu := detail.Resources[0]
+	if cpu.Capacity.MilliValue() != 4000 || cpu.Allocatable.MilliValue() != 3800 {
+		t.Errorf("unexpected cpu capacity %s and allocatable %s", cpu.Capacity, cpu.Allocatable)
+	}
+	if cpu.Requested.MilliValue() != 500 || cpu.Used.MilliValue() != 1200 {
+		t.Errorf("unexpected cpu requested %s and used %s", cpu.Requested, cpu.Used)
+	}
+	if podsRes := detail.Resources[2]; podsRes.Used.Value() != 2 {
+		t.Errorf("expecting 2 pods used, got %s", podsRes.Used)
+	}
+	if gpu := detail.Resources[3]; !gpu.Allocatable.IsZero() || gpu.Requested != nil {
+		t.Errorf("expecting gpu without allocatable or requests, got %+v", gpu)
+	}
+
+	if len(detail.Conditions) != 2 || len(detail.Taints) != 1 || detail.Taints[0].Effect != "NoSchedule" {
+		t.Errorf("unexpected conditions %+v or taints %+v", detail.Conditions, detail.Taints)
+	}
+	if len(detail.Images) != 2 || detail.Images[0].Name != "pause:3.6" || detail.Images[1].Name != "nginx:1.21" {
+		t.Errorf("expecting images largest first by tag, got %+v", detail.Images)
+	}
+	if len(detail.Volumes) != 1 || !detail.Volumes[0].InUse {
+		t.Errorf("expecting attached volume in use, got %+v", detail.Volumes)
+	}
+}
+
+func TestNewNodeDetailModelWithoutMetrics(t *testing.T) {
+	node := &coreV1.Node{Status: coreV1.NodeStatus{
+		Capacity: coreV1.ResourceList{coreV1.ResourceMemory: resource.MustParse("1Gi")},
+	}}
+	detail := NewNodeDetailModel(node, NodeModel{}, nil, nil)
+	if len(detail.Resources) != 1 || detail.Resources[0].Used != nil {
+		t.Errorf("expecting memory without usage, got %+v", detail.Resources)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	coreV1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
 )
 
@@ -16,10 +17,12 @@ const (
 
 type NodeModel struct {
 	Name                 string
+	UID                  types.UID
 	Roles                []string
 	Controller           bool
 	Hostname             string
 	Role                 string
+	Pool                 string
 	Status               string
 	Pressures            []string
 	CreationTime         metav1.Time
@@ -48,18 +51,25 @@ type NodeModel struct {
 	UsageCpuQty *resource.Quantity
 	UsageMemQty *resource.Quantity
 
-	Kubelet		bool
-	Containerd	bool
-	Scini		bool
+	Services map[string]ServiceState
+
+	History MetricsSamples // usage over the history window
+}
+
+// AgeChanged reports whether TimeSinceStart no longer reads the age of the node
+func (n NodeModel) AgeChanged() bool {
+	return n.TimeSinceStart != timeSince(n.CreationTime)
 }
 
 func NewNodeModel(node *coreV1.Node, metrics *v1beta1.NodeMetrics) *NodeModel {
 	roles := GetNodeControlRoles(node)
 	return &NodeModel{
 		Name:           node.Name,
+		UID:            node.UID,
 		Roles:          roles,
 		Controller:     IsNodeController(roles),
 		Hostname:       GetNodeHostName(node),
+		Pool:           GetNodePool(node),
 		Status:         GetNodeReadyStatus(node),
 		Pressures:      GetNodePressures(node),
 		TimeSinceStart: timeSince(node.CreationTimestamp),
@@ -87,6 +97,30 @@ func NewNodeModel(node *coreV1.Node, metrics *v1beta1.NodeMetrics) *NodeModel {
 	}
 }
 
+// NodePoolLabels are the labels naming the node pool or group of a node, by
+// cloud provider or provisioner
+var NodePoolLabels = []string{
+	"cloud.google.com/gke-nodepool",
+	"eks.amazonaws.com/nodegroup",
+	"alpha.eksctl.io/nodegroup-name",
+	"kubernetes.azure.com/agentpool",
+	"agentpool",
+	"karpenter.sh/nodepool",
+	"karpenter.sh/provisioner-name",
+	"node.kubernetes.io/pool",
+	"node-pool",
+}
+
+// GetNodePool returns the node pool of node after NodePoolLabels, or ""
+func GetNodePool(node *coreV1.Node) string {
+	for _, label := range NodePoolLabels {
+		if pool, ok := node.Labels[label]; ok {
+			return pool
+		}
+	}
+	return ""
+}
+
 func GetNodeControlRoles(node *coreV1.Node) []string {
 	roles := []string{}
 	for key, _ := range node.Labels {
@@ -156,24 +190,3 @@ func SortNodeModels(nodes []NodeModel) {
 		return nodes[i].Name < nodes[j].Name
 	})
 }
-
-func SortNodeModelsByField(nodes []NodeModel, sortBy int) {
-    sort.Slice(nodes, func(i, j int) bool {
-        switch sortBy {
-        case 0:
-            return nodes[i].Name < nodes[j].Name
-        case 1:
-            if nodes[i].Status == nodes[j].Status {
-                return nodes[i].Name < nodes[j].Name
-            }
-            return nodes[i].Status < nodes[j].Status
-        case 2:
-            if nodes[i].CreationTime == nodes[j].CreationTime {
-                return nodes[i].Name < nodes[j].Name
-            }
-            return nodes[i].CreationTime.After(nodes[j].CreationTime.Time)
-        default:
-            return nodes[i].Name < nodes[j].Name
-        }
-    })
-}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxOwnerDepth bounds owner chains against reference cycles
+const maxOwnerDepth = 8
+
+// OwnerLookup returns the controller of the object kind namespace/name, or
+// nil when the object has no controller or is unknown
+type OwnerLookup func(kind, namespace, name string) *metav1.OwnerReference
+
+// ResolveOwnerChain follows the controllers of an object in namespace from its
+// controller owner up to the top-level controller, which is last
+func ResolveOwnerChain(namespace string, owner *metav1.OwnerReference, lookup OwnerLookup) []OwnerModel {
+	var chain []OwnerModel
+	for owner != nil && len(chain) < maxOwnerDepth {
+		chain = append(chain, OwnerModel{Kind: owner.Kind, Name: owner.Name, Controller: true})
+		owner = lookup(owner.Kind, namespace, owner.Name)
+	}
+	return chain
+}
+
+// PodGroupModel is the pods of a top-level controller with their counts and
+// resources rolled up. Owner is empty for the pods without a controller.
+type PodGroupModel struct {
+	Namespace string
+	Owner     OwnerModel
+	Pods      []PodModel
+
+	ReadyPods       int
+	Restarts        int
+	RequestedCpuQty *resource.Quantity
+	RequestedMemQty *resource.Quantity
+	UsageCpuQty     *resource.Quantity
+	UsageMemQty     *resource.Quantity
+}
+
+func (g *PodGroupModel) Key() string {
+	return g.Namespace + "/" + g.Owner.Kind + "/" + g.Owner.Name
+}
+
+// GroupPodModelsByOwner groups pods by top-level controller, keeping the pod
+// order within groups. Groups are sorted by namespace and owner, the pods
+// without a controller last in their namespace.
+func GroupPodModelsByOwner(pods []PodModel) []PodGroupModel {
+	var groups []PodGroupModel
+	index := make(map[string]int)
+	for _, pod := range pods {
+		group := PodGroupModel{Namespace: pod.Namespace}
+		if owner, ok := pod.TopController(); ok {
+			group.Owner = owner
+		}
+		i, ok := index[group.Key()]
+		if !ok {
+			group.RequestedCpuQty = resource.NewQuantity(0, resource.DecimalSI)
+			group.RequestedMemQty = resource.NewQuantity(0, resource.DecimalSI)
+			group.UsageCpuQty = resource.NewQuantity(0, resource.DecimalSI)
+			group.UsageMemQty = resource.NewQuantity(0, resource.DecimalSI)
+			groups = append(groups, group)
+			i = len(groups) - 1
+			index[group.Key()] = i
+		}
+		groups[i].addPod(pod)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if (a.Owner.Kind == "") != (b.Owner.Kind == "") {
+			return b.Owner.Kind == ""
+		}
+		if a.Owner.Kind != b.Owner.Kind {
+			return a.Owner.Kind < b.Owner.Kind
+		}
+		return a.Owner.Name < b.Owner.Name
+	})
+	return groups
+}
+
+func (g *PodGroupModel) addPod(pod PodModel) {
+	g.Pods = append(g.Pods, pod)
+	if pod.TotalContainers > 0 && pod.ReadyContainers == pod.TotalContainers {
+		g.ReadyPods++
+	}
+	g.Restarts += pod.Restarts
+	addQty(g.RequestedCpuQty, pod.PodRequestedCpuQty)
+	addQty(g.RequestedMemQty, pod.PodRequestedMemQty)
+	addQty(g.UsageCpuQty, pod.PodUsageCpuQty)
+	addQty(g.UsageMemQty, pod.PodUsageMemQty)
+}
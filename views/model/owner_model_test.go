@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResolveOwnerChain(t *testing.T) {
+	controllers := map[string]*metav1.OwnerReference{
+		"ReplicaSet/api-5d8f": {Kind: KindDeployment, Name: "api"},
+		// a reference cycle must not loop forever
+		"ReplicaSet/loop": {Kind: KindReplicaSet, Name: "loop"},
+	}
+	lookup := func(kind, namespace, name string) *metav1.OwnerReference {
+		return controllers[kind+"/"+name]
+	}
+
+	chain := ResolveOwnerChain("web", &metav1.OwnerReference{Kind: KindReplicaSet, Name: "api-5d8f"}, lookup)
+	if len(chain) != 2 || chain[0].Name != "api-5d8f" || chain[1].Kind != KindDeployment || chain[1].Name != "api" {
+		t.Errorf("unexpected chain %+v", chain)
+	}
+	if chain := ResolveOwnerChain("web", nil, lookup); len(chain) != 0 {
+		t.Errorf("expecting no chain without controller, got %+v", chain)
+	}
+	if chain := ResolveOwnerChain("web", &metav1.OwnerReference{Kind: KindReplicaSet, Name: "loop"}, lookup); len(chain) != maxOwnerDepth {
+		t.Errorf("expecting a chain cut at %d, got %d", maxOwnerDepth, len(chain))
+	}
+}
+
+func TestGroupPodModelsByOwner(t *testing.T) {
+	api := []OwnerModel{{Kind: KindReplicaSet, Name: "api-5d8f"}, {Kind: KindDeployment, Name: "api"}}
+	pods := []PodModel{
+		{Namespace: "web", Name: "debug", ReadyContainers: 1, TotalContainers: 1},
+		{Namespace: "web", Name: "api-1", Controllers: api, ReadyContainers: 2, TotalContainers: 2, Restarts: 1,
+			PodUsageCpuQty: resource.NewMilliQuantity(100, resource.DecimalSI)},
+		{Namespace: "web", Name: "api-2", Controllers: api, ReadyContainers: 1, TotalContainers: 2, Restarts: 2,
+			PodUsageCpuQty: resource.NewMilliQuantity(50, resource.DecimalSI)},
+		{Namespace: "db", Name: "pg-0", Controllers: []OwnerModel{{Kind: KindStatefulSet, Name: "pg"}}},
+	}
+
+	groups := GroupPodModelsByOwner(pods)
+	if len(groups) != 3 {
+		t.Fatalf("expecting 3 groups, got %d", len(groups))
+	}
+	if groups[0].Namespace != "db" || groups[0].Owner.Name != "pg" {
+		t.Errorf("expecting the db group first, got %s", groups[0].Key())
+	}
+	dep := groups[1]
+	if dep.Owner.Kind != KindDeployment || len(dep.Pods) != 2 || dep.Pods[0].Name != "api-1" {
+		t.Errorf("unexpected deployment group %+v", dep)
+	}
+	if dep.ReadyPods != 1 || dep.Restarts != 3 || dep.UsageCpuQty.MilliValue() != 150 {
+		t.Errorf("unexpected roll-up: ready %d, restarts %d, cpu %s", dep.ReadyPods, dep.Restarts, dep.UsageCpuQty)
+	}
+	if groups[2].Owner.Kind != "" || groups[2].Pods[0].Name != "debug" {
+		t.Errorf("expecting the pods without controller last, got %s", groups[2].Key())
+	}
+}
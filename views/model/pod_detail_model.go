@@ -0,0 +1,197 @@
+package model
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsV1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// ContainerModel is a container of a pod with its resources and usage
+type ContainerModel struct {
+	Name            string
+	Init            bool
+	Image           string
+	State           string
+	Ready           bool
+	Restarts        int
+	LastTermination string
+
+	RequestedCpuQty *resource.Quantity
+	LimitCpuQty     *resource.Quantity
+	UsageCpuQty     *resource.Quantity
+	RequestedMemQty *resource.Quantity
+	LimitMemQty     *resource.Quantity
+	UsageMemQty     *resource.Quantity
+}
+
+type ConditionModel struct {
+	Type               string
+	Status             string
+	Reason             string
+	Message            string
+	LastTransitionTime metav1.Time
+}
+
+type OwnerModel struct {
+	Kind       string
+	Name       string
+	Controller bool
+}
+
+// VolumeModel is a pod volume, Source naming what backs it such as a claim or config map
+type VolumeModel struct {
+	Name   string
+	Kind   string
+	Source string
+}
+
+// PodDetailModel is a pod described container by container
+type PodDetailModel struct {
+	PodModel
+	QOSClass       string
+	ServiceAccount string
+	Labels         map[string]string
+	Containers     []ContainerModel
+	Conditions     []ConditionModel
+	Owners         []OwnerModel
+	Volumes        []VolumeModel
+	Events         []EventModel
+}
+
+func NewPodDetailModel(pod *v1.Pod, podMetrics *metricsV1beta1.PodMetrics, events []*v1.Event) *PodDetailModel {
+	detail := &PodDetailModel{
+		PodModel:       *NewPodModel(pod, podMetrics, new(metricsV1beta1.NodeMetrics)),
+		QOSClass:       string(pod.Status.QOSClass),
+		ServiceAccount: pod.Spec.ServiceAccountName,
+		Labels:         pod.Labels,
+	}
+
+	usage := podMetricsByContainer(podMetrics)
+	statuses := make(map[string]v1.ContainerStatus)
+	for _, stat := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		statuses[stat.Name] = stat
+	}
+	for _, container := range pod.Spec.InitContainers {
+		detail.Containers = append(detail.Containers, newContainerModel(container, true, statuses[container.Name], usage[container.Name]))
+	}
+	for _, container := range pod.Spec.Containers {
+		detail.Containers = append(detail.Containers, newContainerModel(container, false, statuses[container.Name], usage[container.Name]))
+	}
+
+	for _, cond := range pod.Status.Conditions {
+		detail.Conditions = append(detail.Conditions, ConditionModel{
+			Type:               string(cond.Type),
+			Status:             string(cond.Status),
+			Reason:             cond.Reason,
+			Message:            cond.Message,
+			LastTransitionTime: cond.LastTransitionTime,
+		})
+	}
+
+	for _, owner := range pod.OwnerReferences {
+		detail.Owners = append(detail.Owners, OwnerModel{
+			Kind:       owner.Kind,
+			Name:       owner.Name,
+			Controller: owner.Controller != nil && *owner.Controller,
+		})
+	}
+
+	for _, vol := range pod.Spec.Volumes {
+		detail.Volumes = append(detail.Volumes, newVolumeModel(vol))
+	}
+
+	detail.Events = NewEventModels(events)
+	return detail
+}
+
+// podMetricsByContainer returns the usage of each container, which
+// podMetricsTotals sums up for the pod
+func podMetricsByContainer(metrics *metricsV1beta1.PodMetrics) map[string]v1.ResourceList {
+	usage := make(map[string]v1.ResourceList)
+	if metrics == nil {
+		return usage
+	}
+	for _, c := range metrics.Containers {
+		usage[c.Name] = c.Usage
+	}
+	return usage
+}
+
+func newContainerModel(container v1.Container, init bool, stat v1.ContainerStatus, usage v1.ResourceList) ContainerModel {
+	return ContainerModel{
+		Name:            container.Name,
+		Init:            init,
+		Image:           container.Image,
+		State:           containerState(stat.State),
+		Ready:           stat.Ready,
+		Restarts:        int(stat.RestartCount),
+		LastTermination: lastTermination(stat.LastTerminationState),
+		RequestedCpuQty: container.Resources.Requests.Cpu(),
+		LimitCpuQty:     container.Resources.Limits.Cpu(),
+		UsageCpuQty:     usage.Cpu(),
+		RequestedMemQty: container.Resources.Requests.Memory(),
+		LimitMemQty:     container.Resources.Limits.Memory(),
+		UsageMemQty:     usage.Memory(),
+	}
+}
+
+func containerState(state v1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running"
+	case state.Waiting != nil:
+		return "Waiting: " + state.Waiting.Reason
+	case state.Terminated != nil:
+		return "Terminated: " + terminationReason(state.Terminated)
+	}
+	return "Unknown"
+}
+
+func lastTermination(state v1.ContainerState) string {
+	if state.Terminated == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s %s ago", terminationReason(state.Terminated), timeSince(state.Terminated.FinishedAt))
+}
+
+func terminationReason(term *v1.ContainerStateTerminated) string {
+	reason := term.Reason
+	if reason == "" && term.Signal != 0 {
+		reason = fmt.Sprintf("Sig:%d", term.Signal)
+	}
+	if reason == "" {
+		reason = "Exit"
+	}
+	return fmt.Sprintf("%s (%d)", reason, term.ExitCode)
+}
+
+func newVolumeModel(vol v1.Volume) VolumeModel {
+	model := VolumeModel{Name: vol.Name, Kind: "Other"}
+	src := vol.VolumeSource
+	switch {
+	case src.PersistentVolumeClaim != nil:
+		model.Kind, model.Source = "PVC", src.PersistentVolumeClaim.ClaimName
+	case src.ConfigMap != nil:
+		model.Kind, model.Source = "ConfigMap", src.ConfigMap.Name
+	case src.Secret != nil:
+		model.Kind, model.Source = "Secret", src.Secret.SecretName
+	case src.EmptyDir != nil:
+		model.Kind, model.Source = "EmptyDir", string(src.EmptyDir.Medium)
+	case src.HostPath != nil:
+		model.Kind, model.Source = "HostPath", src.HostPath.Path
+	case src.Projected != nil:
+		model.Kind, model.Source = "Projected", fmt.Sprintf("%d sources", len(src.Projected.Sources))
+	case src.DownwardAPI != nil:
+		model.Kind = "DownwardAPI"
+	case src.CSI != nil:
+		model.Kind, model.Source = "CSI", src.CSI.Driver
+	case src.NFS != nil:
+		model.Kind, model.Source = "NFS", src.NFS.Server+":"+src.NFS.Path
+	case src.Ephemeral != nil:
+		model.Kind = "Ephemeral"
+	}
+	return model
+}
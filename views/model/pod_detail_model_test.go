@@ -0,0 +1,61 @@
+package model
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsV1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+func TestNewPodDetailModel(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "api-1"},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "api", Image: "api:1.2", Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m")},
+					Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+				}},
+				{Name: "proxy", Image: "envoy:1.20"},
+			},
+			Volumes: []v1.Volume{
+				{Name: "data", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "api-data"}}},
+			},
+		},
+		Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{
+			{
+				Name: "api", Ready: true, RestartCount: 2,
+				State:                v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+			},
+			{Name: "proxy", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+		}},
+	}
+	metrics := &metricsV1beta1.PodMetrics{Containers: []metricsV1beta1.ContainerMetrics{
+		{Name: "api", Usage: v1.ResourceList{v1.ResourceCPU: resource.MustParse("120m")}},
+		{Name: "proxy", Usage: v1.ResourceList{v1.ResourceCPU: resource.MustParse("5m")}},
+	}}
+
+	detail := NewPodDetailModel(pod, metrics, nil)
+	if len(detail.Containers) != 2 {
+		t.Fatalf("expecting 2 containers, got %d", len(detail.Containers))
+	}
+	api, proxy := detail.Containers[0], detail.Containers[1]
+	if api.UsageCpuQty.MilliValue() != 120 || proxy.UsageCpuQty.MilliValue() != 5 {
+		t.Errorf("expecting per container cpu usage 120m and 5m, got %s and %s", api.UsageCpuQty, proxy.UsageCpuQty)
+	}
+	if api.RequestedCpuQty.MilliValue() != 250 || api.LimitCpuQty.MilliValue() != 1000 {
+		t.Errorf("unexpected api cpu request %s and limit %s", api.RequestedCpuQty, api.LimitCpuQty)
+	}
+	if api.Restarts != 2 || api.LastTermination == "" {
+		t.Errorf("expecting api restarts and last termination, got %+v", api)
+	}
+	if proxy.State != "Waiting: CrashLoopBackOff" {
+		t.Errorf("unexpected proxy state %q", proxy.State)
+	}
+	if len(detail.Volumes) != 1 || detail.Volumes[0].Kind != "PVC" || detail.Volumes[0].Source != "api-data" {
+		t.Errorf("unexpected volumes %+v", detail.Volumes)
+	}
+}
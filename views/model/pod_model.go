@@ -2,12 +2,14 @@ package model
 
 import (
 	"fmt"
+	"net"
 	"sort"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/duration"
 	metricsV1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 )
@@ -15,15 +17,22 @@ import (
 type PodModel struct {
 	Namespace string
 	Name      string
+	UID       types.UID
 	Status    string
 	Node      string
 	IP        string
+	IPs       []string // addresses of each IP family, IP first
 	TimeSince string
+	QOSClass  string
+	Images    []string // images of the containers, in order
 
 	PodRequestedCpuQty *resource.Quantity
 	PodRequestedMemQty *resource.Quantity
 	PodUsageCpuQty     *resource.Quantity
 	PodUsageMemQty     *resource.Quantity
+	// limits of the containers, nil when a container has no limit
+	PodLimitCpuQty *resource.Quantity
+	PodLimitMemQty *resource.Quantity
 
 	NodeAllocatableCpuQty *resource.Quantity
 	NodeAllocatableMemQty *resource.Quantity
@@ -37,11 +46,27 @@ type PodModel struct {
 	VolMounts       int
 
 	CreationTimestamp	metav1.Time
+
+	// controllers of the pod, from its direct controller up to the top-level one
+	Controllers []OwnerModel
+
+	History MetricsSamples // usage over the history window
+}
+
+// TopController returns the top-level controller of the pod, if any
+func (p PodModel) TopController() (OwnerModel, bool) {
+	if len(p.Controllers) == 0 {
+		return OwnerModel{}, false
+	}
+	return p.Controllers[len(p.Controllers)-1], true
 }
 
 type PodContainerSummary struct {
 	RequestedMemQty *resource.Quantity
 	RequestedCpuQty *resource.Quantity
+	LimitMemQty     *resource.Quantity // nil when a container has no memory limit
+	LimitCpuQty     *resource.Quantity // nil when a container has no cpu limit
+	Images          []string
 	VolMounts       int
 	Ports           int
 }
@@ -54,43 +79,6 @@ type ContainerStatusSummary struct {
 	SomeRunning bool
 }
 
-func SortPodModelsByField(pods []PodModel, sortBy int) {
-    sort.Slice(pods, func(i, j int) bool {
-        switch sortBy {
-        case 1:
-            return pods[i].Name < pods[j].Name
-        case 2:
-            if pods[i].ReadyContainers != pods[j].ReadyContainers {
-                return pods[i].ReadyContainers < pods[j].ReadyContainers
-            }
-        case 3:
-            if pods[i].Status != pods[j].Status {
-
-                return pods[i].Status < pods[j].Status
-            }
-        case 4:
-            if pods[i].Restarts != pods[j].Restarts {
-                return pods[i].Restarts < pods[j].Restarts
-            }
-        case 5:
-            if pods[i].CreationTimestamp != pods[j].CreationTimestamp {
-                return pods[i].CreationTimestamp.After(pods[j].CreationTimestamp.Time)
-            }
-        case 6:
-            if pods[i].Node != pods[j].Node {
-                return pods[i].Node < pods[j].Node
-            }
-        }
-        if pods[i].Namespace != pods[j].Namespace {
-            return pods[i].Namespace < pods[j].Namespace
-        }
-        if pods[i].Node != pods[j].Node {
-            return pods[i].Node < pods[j].Node
-        }
-        return pods[i].Name < pods[j].Name
-    })
-}
-
 func SortPodModels(pods []PodModel) {
         sort.Slice(pods, func(i, j int) bool {
                 if pods[i].Namespace != pods[j].Namespace {
@@ -114,15 +102,21 @@ func NewPodModel(pod *v1.Pod, podMetrics *metricsV1beta1.PodMetrics, nodeMetrics
 	return &PodModel{
 		Namespace:          pod.GetNamespace(),
 		Name:               pod.Name,
+		UID:                pod.UID,
 		Status:             statusSummary.Status,
 		TimeSince:          timeSince(pod.CreationTimestamp),
 		CreationTimestamp:  pod.CreationTimestamp,
 		IP:                 pod.Status.PodIP,
+		IPs:                podIPs(pod.Status),
+		QOSClass:           string(pod.Status.QOSClass),
+		Images:             containerSummary.Images,
 		Node:               pod.Spec.NodeName,
 		Volumes:            len(pod.Spec.Volumes),
 		VolMounts:          containerSummary.VolMounts,
 		PodRequestedMemQty: containerSummary.RequestedMemQty,
 		PodRequestedCpuQty: containerSummary.RequestedCpuQty,
+		PodLimitMemQty:     containerSummary.LimitMemQty,
+		PodLimitCpuQty:     containerSummary.LimitCpuQty,
 		NodeUsageCpuQty:    nodeMetrics.Usage.Cpu(),
 		NodeUsageMemQty:    nodeMetrics.Usage.Memory(),
 		PodUsageCpuQty:     totalCpu,
@@ -133,6 +127,42 @@ func NewPodModel(pod *v1.Pod, podMetrics *metricsV1beta1.PodMetrics, nodeMetrics
 	}
 }
 
+func podIPs(status v1.PodStatus) []string {
+	var ips []string
+	for _, ip := range status.PodIPs {
+		ips = append(ips, ip.IP)
+	}
+	if len(ips) == 0 && status.PodIP != "" {
+		ips = append(ips, status.PodIP)
+	}
+	return ips
+}
+
+// IPFamily returns IPv4, IPv6 or dual-stack after the addresses of the pod,
+// or "" before it has one
+func (p PodModel) IPFamily() string {
+	var v4, v6 bool
+	for _, ip := range p.IPs {
+		parsed := net.ParseIP(ip)
+		switch {
+		case parsed == nil:
+		case parsed.To4() != nil:
+			v4 = true
+		default:
+			v6 = true
+		}
+	}
+	switch {
+	case v4 && v6:
+		return "dual-stack"
+	case v6:
+		return "IPv6"
+	case v4:
+		return "IPv4"
+	}
+	return ""
+}
+
 func podMetricsTotals(metrics *metricsV1beta1.PodMetrics) (totalCpu, totalMem *resource.Quantity) {
 	containers := metrics.Containers
 	totalCpu = resource.NewQuantity(0, resource.DecimalSI)
@@ -179,6 +209,11 @@ func podIsReady(conds []v1.PodCondition) bool {
 	return false
 }
 
+// AgeChanged reports whether TimeSince no longer reads the age of the pod
+func (p PodModel) AgeChanged() bool {
+	return p.TimeSince != timeSince(p.CreationTimestamp)
+}
+
 func timeSince(ts metav1.Time) string {
 	if ts.IsZero() {
 		return "..."
@@ -189,14 +224,35 @@ func timeSince(ts metav1.Time) string {
 func GetPodContainerSummary(pod *v1.Pod) PodContainerSummary {
 	mems := resource.NewQuantity(0, resource.DecimalSI)
 	cpus := resource.NewQuantity(0, resource.DecimalSI)
+	limitMems := resource.NewQuantity(0, resource.BinarySI)
+	limitCpus := resource.NewQuantity(0, resource.DecimalSI)
+	memLimited, cpuLimited := true, true
+	var images []string
 	var ports int
 	var mounts int
 	for _, container := range pod.Spec.Containers {
 		mems.Add(*container.Resources.Requests.Memory())
 		cpus.Add(*container.Resources.Requests.Cpu())
+		if limit, ok := container.Resources.Limits[v1.ResourceMemory]; ok {
+			limitMems.Add(limit)
+		} else {
+			memLimited = false
+		}
+		if limit, ok := container.Resources.Limits[v1.ResourceCPU]; ok {
+			limitCpus.Add(limit)
+		} else {
+			cpuLimited = false
+		}
+		images = append(images, container.Image)
 		ports += len(container.Ports)
 		mounts += len(container.VolumeMounts)
 	}
+	if !memLimited {
+		limitMems = nil
+	}
+	if !cpuLimited {
+		limitCpus = nil
+	}
 
 	for _, container := range pod.Spec.InitContainers {
 		mems.Add(*container.Resources.Requests.Memory())
@@ -213,6 +269,9 @@ func GetPodContainerSummary(pod *v1.Pod) PodContainerSummary {
 	return PodContainerSummary{
 		RequestedMemQty: mems,
 		RequestedCpuQty: cpus,
+		LimitMemQty:     limitMems,
+		LimitCpuQty:     limitCpus,
+		Images:          images,
 		VolMounts:       mounts,
 		Ports:           ports,
 	}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPodModelIPFamily(t *testing.T) {
+	tests := []struct {
+		ips    []string
+		family string
+	}{
+		{ips: nil, family: ""},
+		{ips: []string{"10.1.2.3"}, family: "IPv4"},
+		{ips: []string{"fd00::12"}, family: "IPv6"},
+		{ips: []string{"10.1.2.3", "fd00::12"}, family: "dual-stack"},
+	}
+	for _, test := range tests {
+		if family := (PodModel{IPs: test.ips}).IPFamily(); family != test.family {
+			t.Errorf("%v: expecting %q, got %q", test.ips, test.family, family)
+		}
+	}
+}
+
+func TestGetPodContainerSummaryLimits(t *testing.T) {
+	limited := func(cpu, mem string) v1.Container {
+		return v1.Container{Image: "web:1", Resources: v1.ResourceRequirements{Limits: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse(mem),
+		}}}
+	}
+	pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{limited("500m", "256Mi"), limited("1", "1Gi")}}}
+	summary := GetPodContainerSummary(pod)
+	if summary.LimitCpuQty == nil || summary.LimitCpuQty.MilliValue() != 1500 {
+		t.Errorf("expecting a cpu limit of 1500m, got %v", summary.LimitCpuQty)
+	}
+	if summary.LimitMemQty == nil || summary.LimitMemQty.Value() != 1280*1024*1024 {
+		t.Errorf("expecting a memory limit of 1280Mi, got %v", summary.LimitMemQty)
+	}
+	if len(summary.Images) != 2 {
+		t.Errorf("expecting the images of both containers, got %v", summary.Images)
+	}
+
+	pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Image: "sidecar:1"})
+	if summary := GetPodContainerSummary(pod); summary.LimitCpuQty != nil || summary.LimitMemQty != nil {
+		t.Errorf("expecting no limits with an unlimited container, got %v %v", summary.LimitCpuQty, summary.LimitMemQty)
+	}
+}
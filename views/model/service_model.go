@@ -0,0 +1,18 @@
+package model
+
+// ServiceState is the health of a host service as last reported by its probe
+type ServiceState string
+
+const (
+	ServiceActive   ServiceState = "active"
+	ServiceInactive ServiceState = "inactive"
+	ServiceUnknown  ServiceState = "unknown"
+	ServiceDisabled ServiceState = "n/a"
+)
+
+// ServiceSummary counts the nodes where a service is monitored and healthy
+type ServiceSummary struct {
+	Name  string
+	Ready int
+	Count int
+}
@@ -0,0 +1,330 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// usageChangeRatio is how much the usage of an object must move, relative
+// to the larger of both values, to be reported as a change. Usage moves at
+// every metrics scrape.
+const usageChangeRatio = 0.1
+
+// Snapshot holds the pods, nodes and workloads of a cluster at a point in
+// time. Context and Namespaces tell the cluster and namespaces they come from.
+type Snapshot struct {
+	Name       string
+	Time       time.Time
+	Context    string
+	Namespaces []string
+	Pods       []PodModel
+	Nodes      []NodeModel
+	Workloads  []WorkloadModel
+}
+
+// NewSnapshot returns a snapshot of copies of pods, nodes and workloads, which
+// can be sorted by their panels
+func NewSnapshot(name string, t time.Time, pods []PodModel, nodes []NodeModel, workloads []WorkloadModel) *Snapshot {
+	return &Snapshot{
+		Name:      name,
+		Time:      t,
+		Pods:      append([]PodModel(nil), pods...),
+		Nodes:     append([]NodeModel(nil), nodes...),
+		Workloads: append([]WorkloadModel(nil), workloads...),
+	}
+}
+
+// ObjectKey identifies an object across snapshots. An object recreated with
+// the same name has another UID, hence another key.
+func ObjectKey(kind, namespace, name string, uid types.UID) string {
+	return kind + "/" + namespace + "/" + name + "/" + string(uid)
+}
+
+// OrDash returns text, or - when it is empty, for the cells and fields
+// without a value
+func OrDash(text string) string {
+	if text == "" {
+		return "-"
+	}
+	return text
+}
+
+// FieldChange is the change of a field of an object between two snapshots.
+// Delta is the signed difference of numeric fields.
+type FieldChange struct {
+	Field string
+	From  string
+	To    string
+	Delta string
+}
+
+// ObjectChange is an object added, removed or changed between two snapshots,
+// with the fields that changed
+type ObjectChange struct {
+	Change    string
+	Kind      string
+	Namespace string
+	Name      string
+	Fields    []FieldChange
+}
+
+// SnapshotDiff holds the changes from a snapshot to another one, sorted by
+// kind, namespace and name
+type SnapshotDiff struct {
+	From     string
+	FromTime time.Time
+	To       string
+	ToTime   time.Time
+	Changes  []ObjectChange
+	Added    int
+	Removed  int
+	Changed  int
+}
+
+// DiffSnapshots returns the pods, nodes and workloads added, removed or
+// changed from snapshot from to snapshot to
+func DiffSnapshots(from, to *Snapshot) SnapshotDiff {
+	diff := SnapshotDiff{From: from.Name, FromTime: from.Time, To: to.Name, ToTime: to.Time}
+
+	previousObjects := snapshotObjects(from)
+	fromObjects := make(map[string]diffObject)
+	for _, obj := range previousObjects {
+		fromObjects[obj.key] = obj
+	}
+	seen := make(map[string]bool)
+	for _, obj := range snapshotObjects(to) {
+		seen[obj.key] = true
+		previous, ok := fromObjects[obj.key]
+		if !ok {
+			diff.add(ChangeAdded, obj, nil)
+			continue
+		}
+		if fields := diffFields(previous.fields, obj.fields); len(fields) > 0 {
+			diff.add(ChangeChanged, obj, fields)
+		}
+	}
+	for _, obj := range previousObjects {
+		if !seen[obj.key] {
+			diff.add(ChangeRemoved, obj, nil)
+		}
+	}
+
+	sort.SliceStable(diff.Changes, func(i, j int) bool {
+		a, b := diff.Changes[i], diff.Changes[j]
+		if a.Kind != b.Kind {
+			return kindOrder(a.Kind) < kindOrder(b.Kind)
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return diff
+}
+
+func (d *SnapshotDiff) add(change string, obj diffObject, fields []FieldChange) {
+	d.Changes = append(d.Changes, ObjectChange{
+		Change:    change,
+		Kind:      obj.kind,
+		Namespace: obj.namespace,
+		Name:      obj.name,
+		Fields:    fields,
+	})
+	switch change {
+	case ChangeAdded:
+		d.Added++
+	case ChangeRemoved:
+		d.Removed++
+	default:
+		d.Changed++
+	}
+}
+
+// kindOrder orders nodes first, then workloads by model.WorkloadKinds, then pods
+func kindOrder(kind string) int {
+	switch kind {
+	case "Node":
+		return 0
+	case "Pod":
+		return len(WorkloadKinds) + 1
+	}
+	for i, workloadKind := range WorkloadKinds {
+		if workloadKind == kind {
+			return i + 1
+		}
+	}
+	return len(WorkloadKinds) + 2
+}
+
+// diffObject is an object of a snapshot with the fields compared across snapshots
+type diffObject struct {
+	key       string
+	kind      string
+	namespace string
+	name      string
+	fields    []diffField
+}
+
+// diffField is a field of an object. Numeric fields have a value to compute
+// deltas, usage fields only change past usageChangeRatio.
+type diffField struct {
+	name   string
+	text   string
+	value  float64
+	format func(float64) string
+	usage  bool
+}
+
+func textField(name, text string) diffField {
+	return diffField{name: name, text: text}
+}
+
+func countField(name string, count int) diffField {
+	return diffField{name: name, text: fmt.Sprintf("%d", count), value: float64(count), format: countText}
+}
+
+func cpuField(name string, qty *resource.Quantity, usage bool) diffField {
+	milli := float64(quantityMilli(qty))
+	return diffField{name: name, text: cpuQtyText(milli), value: milli, format: cpuQtyText, usage: usage}
+}
+
+func memField(name string, qty *resource.Quantity, usage bool) diffField {
+	mebi := float64(0)
+	if qty != nil {
+		mebi = float64(qty.Value() / (1 << 20))
+	}
+	return diffField{name: name, text: memQtyText(mebi), value: mebi, format: memQtyText, usage: usage}
+}
+
+func snapshotObjects(s *Snapshot) []diffObject {
+	var objects []diffObject
+	for _, node := range s.Nodes {
+		objects = append(objects, diffObject{
+			key:  ObjectKey("Node", "", node.Name, node.UID),
+			kind: "Node",
+			name: node.Name,
+			fields: []diffField{
+				textField("status", node.Status),
+				textField("pressures", strings.Join(node.Pressures, ",")),
+				textField("kubelet", node.KubeletVersion),
+				countField("pods", node.PodsCount),
+				cpuField("cpu requested", node.RequestedPodCpuQty, false),
+				memField("mem requested", node.RequestedPodMemQty, false),
+				cpuField("cpu usage", node.UsageCpuQty, true),
+				memField("mem usage", node.UsageMemQty, true),
+			},
+		})
+	}
+	for _, w := range s.Workloads {
+		objects = append(objects, diffObject{
+			key:       ObjectKey(w.Kind, w.Namespace, w.Name, w.UID),
+			kind:      w.Kind,
+			namespace: w.Namespace,
+			name:      w.Name,
+			fields: []diffField{
+				textField("status", w.Status),
+				textField("images", strings.Join(w.Images, ",")),
+				countField("desired", w.Desired),
+				countField("ready", w.Ready),
+				countField("updated", w.Updated),
+				countField("available", w.Available),
+				countField("succeeded", w.Succeeded),
+				countField("failed", w.Failed),
+				countField("pods", w.Pods),
+				countField("restarts", w.Restarts),
+				cpuField("cpu usage", w.UsageCpuQty, true),
+				memField("mem usage", w.UsageMemQty, true),
+			},
+		})
+	}
+	for _, pod := range s.Pods {
+		objects = append(objects, diffObject{
+			key:       ObjectKey("Pod", pod.Namespace, pod.Name, pod.UID),
+			kind:      "Pod",
+			namespace: pod.Namespace,
+			name:      pod.Name,
+			fields: []diffField{
+				textField("status", pod.Status),
+				textField("ready", fmt.Sprintf("%d/%d", pod.ReadyContainers, pod.TotalContainers)),
+				countField("restarts", pod.Restarts),
+				textField("node", pod.Node),
+				textField("ip", pod.IP),
+				cpuField("cpu requested", pod.PodRequestedCpuQty, false),
+				memField("mem requested", pod.PodRequestedMemQty, false),
+				cpuField("cpu usage", pod.PodUsageCpuQty, true),
+				memField("mem usage", pod.PodUsageMemQty, true),
+			},
+		})
+	}
+	return objects
+}
+
+// diffFields returns the changes from fields from to fields to, which list
+// the same fields in the same order
+func diffFields(from, to []diffField) []FieldChange {
+	var changes []FieldChange
+	for i := range to {
+		a, b := from[i], to[i]
+		if a.text == b.text {
+			continue
+		}
+		if b.usage && !usageChanged(a.value, b.value) {
+			continue
+		}
+		change := FieldChange{Field: b.name, From: a.text, To: b.text}
+		if b.format != nil {
+			change.Delta = signed(b.value-a.value, b.format)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func usageChanged(from, to float64) bool {
+	max := from
+	if to > max {
+		max = to
+	}
+	diff := to - from
+	if diff < 0 {
+		diff = -diff
+	}
+	return max > 0 && diff >= max*usageChangeRatio
+}
+
+func signed(delta float64, format func(float64) string) string {
+	if delta < 0 {
+		return "-" + format(-delta)
+	}
+	return "+" + format(delta)
+}
+
+func quantityMilli(qty *resource.Quantity) int64 {
+	if qty == nil {
+		return 0
+	}
+	return qty.MilliValue()
+}
+
+func countText(value float64) string {
+	return fmt.Sprintf("%d", int64(value))
+}
+
+func cpuQtyText(milli float64) string {
+	return fmt.Sprintf("%dm", int64(milli))
+}
+
+func memQtyText(mebi float64) string {
+	return fmt.Sprintf("%dMi", int64(mebi))
+}
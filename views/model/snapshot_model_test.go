@@ -0,0 +1,106 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func snapshotPod(name, uid, node string, restarts int, cpu string) PodModel {
+	usage := resource.MustParse(cpu)
+	return PodModel{
+		Namespace: "web", Name: name, UID: types.UID("uid-" + uid), Status: "Running", Node: node,
+		ReadyContainers: 1, TotalContainers: 1, Restarts: restarts, PodUsageCpuQty: &usage,
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	from := NewSnapshot("before", start, []PodModel{
+		snapshotPod("api-0", "a", "node-1", 0, "100m"),
+		snapshotPod("api-1", "b", "node-1", 2, "100m"),
+		snapshotPod("db-0", "c", "node-2", 0, "200m"),
+		snapshotPod("old", "d", "node-2", 0, "10m"),
+	}, []NodeModel{{Name: "node-1", UID: "n1", Status: "Ready"}}, nil)
+	to := NewSnapshot("after", start.Add(time.Minute), []PodModel{
+		snapshotPod("api-0", "a", "node-1", 3, "105m"), // restarted, usage within the threshold
+		snapshotPod("api-1", "b", "node-2", 2, "100m"), // moved
+		snapshotPod("db-0", "e", "node-2", 0, "200m"),  // recreated
+		snapshotPod("new", "f", "node-1", 0, "10m"),
+	}, []NodeModel{{Name: "node-1", UID: "n1", Status: "NotReady"}}, nil)
+
+	diff := DiffSnapshots(from, to)
+	if diff.From != "before" || diff.To != "after" || !diff.ToTime.Equal(start.Add(time.Minute)) {
+		t.Errorf("unexpected diff snapshots %+v", diff)
+	}
+	if diff.Added != 2 || diff.Removed != 2 || diff.Changed != 3 {
+		t.Fatalf("expecting 2 added, 2 removed and 3 changed, got %+v", diff.Changes)
+	}
+
+	node := diff.Changes[0]
+	if node.Kind != "Node" || len(node.Fields) != 1 || node.Fields[0] != (FieldChange{Field: "status", From: "Ready", To: "NotReady"}) {
+		t.Errorf("expecting the node status change first, got %+v", node)
+	}
+
+	changes := make(map[string][]ObjectChange)
+	for _, change := range diff.Changes[1:] {
+		changes[change.Name] = append(changes[change.Name], change)
+	}
+	restarted := changes["api-0"]
+	if len(restarted) != 1 || restarted[0].Change != ChangeChanged || len(restarted[0].Fields) != 1 ||
+		restarted[0].Fields[0] != (FieldChange{Field: "restarts", From: "0", To: "3", Delta: "+3"}) {
+		t.Errorf("expecting api-0 restarts only, got %+v", restarted)
+	}
+	moved := changes["api-1"]
+	if len(moved) != 1 || len(moved[0].Fields) != 1 || moved[0].Fields[0].Field != "node" || moved[0].Fields[0].To != "node-2" {
+		t.Errorf("expecting api-1 to move to node-2, got %+v", moved)
+	}
+	recreated := changes["db-0"]
+	if len(recreated) != 2 || recreated[0].Change == recreated[1].Change {
+		t.Errorf("expecting db-0 removed and added, got %+v", recreated)
+	}
+	if old := changes["old"]; len(old) != 1 || old[0].Change != ChangeRemoved {
+		t.Errorf("expecting old removed, got %+v", old)
+	}
+	if added := changes["new"]; len(added) != 1 || added[0].Change != ChangeAdded {
+		t.Errorf("expecting new added, got %+v", added)
+	}
+}
+
+func TestDiffSnapshotsUsage(t *testing.T) {
+	from := NewSnapshot("a", time.Time{}, []PodModel{snapshotPod("api", "a", "n", 0, "100m")}, nil, nil)
+	to := NewSnapshot("b", time.Time{}, []PodModel{snapshotPod("api", "a", "n", 0, "250m")}, nil, nil)
+
+	diff := DiffSnapshots(from, to)
+	if len(diff.Changes) != 1 || len(diff.Changes[0].Fields) != 1 {
+		t.Fatalf("expecting a usage change, got %+v", diff.Changes)
+	}
+	if field := diff.Changes[0].Fields[0]; field != (FieldChange{Field: "cpu usage", From: "100m", To: "250m", Delta: "+150m"}) {
+		t.Errorf("unexpected usage change %+v", field)
+	}
+
+	if diff := DiffSnapshots(to, from); diff.Changes[0].Fields[0].Delta != "-150m" {
+		t.Errorf("expecting a negative delta, got %+v", diff.Changes[0].Fields[0])
+	}
+}
+
+func TestDiffSnapshotsMemUsage(t *testing.T) {
+	withMem := func(mem string) PodModel {
+		pod := snapshotPod("api", "a", "n", 0, "100m")
+		usage := resource.MustParse(mem)
+		pod.PodUsageMemQty = &usage
+		return pod
+	}
+	from := NewSnapshot("a", time.Time{}, []PodModel{withMem("256Mi")}, nil, nil)
+	to := NewSnapshot("b", time.Time{}, []PodModel{withMem("1Gi")}, nil, nil)
+
+	diff := DiffSnapshots(from, to)
+	if len(diff.Changes) != 1 || len(diff.Changes[0].Fields) != 1 {
+		t.Fatalf("expecting a usage change, got %+v", diff.Changes)
+	}
+	if field := diff.Changes[0].Fields[0]; field != (FieldChange{Field: "mem usage", From: "256Mi", To: "1024Mi", Delta: "+768Mi"}) {
+		t.Errorf("unexpected usage change %+v", field)
+	}
+}
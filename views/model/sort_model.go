@@ -0,0 +1,214 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Sort keys of pods and nodes
+const (
+	SortName       = "name"
+	SortNamespace  = "ns"
+	SortNode       = "node"
+	SortReady      = "ready"
+	SortStatus     = "status"
+	SortRestarts   = "restarts"
+	SortAge        = "age"
+	SortPodCount   = "pods"
+	SortCpu        = "cpu"
+	SortMem        = "mem"
+	SortCpuRequest = "cpureq"
+	SortMemRequest = "memreq"
+)
+
+// PodSortKeys are the keys pods can be sorted by
+var PodSortKeys = []string{SortName, SortNamespace, SortNode, SortReady, SortStatus, SortRestarts, SortAge, SortCpu, SortMem, SortCpuRequest, SortMemRequest}
+
+// NodeSortKeys are the keys nodes can be sorted by
+var NodeSortKeys = []string{SortName, SortStatus, SortAge, SortPodCount, SortCpu, SortMem, SortCpuRequest, SortMemRequest}
+
+// podSortNumbers and nodeSortNumbers are the keys of the numbers pods and
+// nodes were sorted by before the sort keys, "" for the default order
+var (
+	podSortNumbers  = []string{"", SortName, SortReady, SortStatus, SortRestarts, SortAge, SortNode}
+	nodeSortNumbers = []string{SortName, SortStatus, SortAge}
+)
+
+// SortOrder is a sort key and direction. The zero value is the default order
+// of a list: pods by namespace, node and name, nodes by name.
+type SortOrder struct {
+	Key  string
+	Desc bool
+}
+
+// ParseSortOrder parses a sort argument, one of keys, prefixed with - to sort
+// in descending order. A key without - that current already sorts by toggles
+// the direction of current.
+func ParseSortOrder(arg string, current SortOrder, keys []string) (SortOrder, error) {
+	key := strings.TrimPrefix(arg, "-")
+	desc := key != arg
+	if !containsKey(keys, key) {
+		return current, fmt.Errorf("unknown sort key %q, expecting one of %s", key, strings.Join(keys, ", "))
+	}
+	if !desc && key == current.Key {
+		return SortOrder{Key: key, Desc: !current.Desc}, nil
+	}
+	return SortOrder{Key: key, Desc: desc}, nil
+}
+
+// ParsePodSortOrder parses a pod sort argument, see ParseSortOrder. The
+// numbers pods were sorted by before, such as 4 for restarts, are accepted.
+func ParsePodSortOrder(arg string, current SortOrder) (SortOrder, error) {
+	return parseNumberedSortOrder(arg, current, PodSortKeys, podSortNumbers)
+}
+
+// ParseNodeSortOrder parses a node sort argument, like ParsePodSortOrder
+func ParseNodeSortOrder(arg string, current SortOrder) (SortOrder, error) {
+	return parseNumberedSortOrder(arg, current, NodeSortKeys, nodeSortNumbers)
+}
+
+func parseNumberedSortOrder(arg string, current SortOrder, keys, numbers []string) (SortOrder, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return ParseSortOrder(arg, current, keys)
+	}
+	if n < 0 || n >= len(numbers) {
+		return current, fmt.Errorf("unknown sort key %q, expecting one of %s", arg, strings.Join(keys, ", "))
+	}
+	if numbers[n] == "" {
+		return SortOrder{}, nil
+	}
+	return SortOrder{Key: numbers[n]}, nil
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the order as a sort argument, or "" for the default order
+func (o SortOrder) String() string {
+	if o.Desc {
+		return "-" + o.Key
+	}
+	return o.Key
+}
+
+// SortPods sorts pods by order, then by namespace, node and name
+func SortPods(pods []PodModel, order SortOrder) {
+	sort.Slice(pods, func(i, j int) bool {
+		if c := comparePods(pods[i], pods[j], order.Key); c != 0 {
+			return (c < 0) != order.Desc
+		}
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		if pods[i].Node != pods[j].Node {
+			return pods[i].Node < pods[j].Node
+		}
+		return pods[i].Name < pods[j].Name
+	})
+}
+
+func comparePods(a, b PodModel, key string) int {
+	switch key {
+	case SortName:
+		return strings.Compare(a.Name, b.Name)
+	case SortNamespace:
+		return strings.Compare(a.Namespace, b.Namespace)
+	case SortNode:
+		return strings.Compare(a.Node, b.Node)
+	case SortReady:
+		return compareInts(a.ReadyContainers, b.ReadyContainers)
+	case SortStatus:
+		return strings.Compare(a.Status, b.Status)
+	case SortRestarts:
+		return compareInts(a.Restarts, b.Restarts)
+	case SortAge:
+		return compareAges(a.CreationTimestamp.Time, b.CreationTimestamp.Time)
+	case SortCpu:
+		return compareQuantities(a.PodUsageCpuQty, b.PodUsageCpuQty)
+	case SortMem:
+		return compareQuantities(a.PodUsageMemQty, b.PodUsageMemQty)
+	case SortCpuRequest:
+		return compareQuantities(a.PodRequestedCpuQty, b.PodRequestedCpuQty)
+	case SortMemRequest:
+		return compareQuantities(a.PodRequestedMemQty, b.PodRequestedMemQty)
+	}
+	return 0
+}
+
+// SortNodes sorts nodes by order, then by name
+func SortNodes(nodes []NodeModel, order SortOrder) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if c := compareNodes(nodes[i], nodes[j], order.Key); c != 0 {
+			return (c < 0) != order.Desc
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+func compareNodes(a, b NodeModel, key string) int {
+	switch key {
+	case SortName:
+		return strings.Compare(a.Name, b.Name)
+	case SortStatus:
+		return strings.Compare(a.Status, b.Status)
+	case SortAge:
+		return compareAges(a.CreationTime.Time, b.CreationTime.Time)
+	case SortPodCount:
+		return compareInts(a.PodsCount, b.PodsCount)
+	case SortCpu:
+		return compareQuantities(a.UsageCpuQty, b.UsageCpuQty)
+	case SortMem:
+		return compareQuantities(a.UsageMemQty, b.UsageMemQty)
+	case SortCpuRequest:
+		return compareQuantities(a.RequestedPodCpuQty, b.RequestedPodCpuQty)
+	case SortMemRequest:
+		return compareQuantities(a.RequestedPodMemQty, b.RequestedPodMemQty)
+	}
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareAges compares the ages of objects created at a and b, the most
+// recently created being the youngest
+func compareAges(a, b time.Time) int {
+	switch {
+	case a.After(b):
+		return -1
+	case b.After(a):
+		return 1
+	}
+	return 0
+}
+
+// compareQuantities compares quantities, nil being zero
+func compareQuantities(a, b *resource.Quantity) int {
+	var zero resource.Quantity
+	if a == nil {
+		a = &zero
+	}
+	if b == nil {
+		b = &zero
+	}
+	return a.Cmp(*b)
+}
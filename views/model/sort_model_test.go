@@ -0,0 +1,103 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseSortOrder(t *testing.T) {
+	tests := []struct {
+		arg     string
+		current SortOrder
+		order   SortOrder
+	}{
+		{arg: "cpu", order: SortOrder{Key: SortCpu}},
+		{arg: "-restarts", order: SortOrder{Key: SortRestarts, Desc: true}},
+		{arg: "cpu", current: SortOrder{Key: SortCpu}, order: SortOrder{Key: SortCpu, Desc: true}},
+		{arg: "cpu", current: SortOrder{Key: SortCpu, Desc: true}, order: SortOrder{Key: SortCpu}},
+		{arg: "-cpu", current: SortOrder{Key: SortCpu, Desc: true}, order: SortOrder{Key: SortCpu, Desc: true}},
+		{arg: "mem", current: SortOrder{Key: SortCpu, Desc: true}, order: SortOrder{Key: SortMem}},
+	}
+	for _, test := range tests {
+		order, err := ParseSortOrder(test.arg, test.current, PodSortKeys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if order != test.order {
+			t.Errorf("%q from %+v: expecting %+v, got %+v", test.arg, test.current, test.order, order)
+		}
+		if parsed, _ := ParseSortOrder(order.String(), SortOrder{}, PodSortKeys); parsed != order {
+			t.Errorf("expecting %q to parse back to %+v, got %+v", order, order, parsed)
+		}
+	}
+	if _, err := ParseSortOrder("ready", SortOrder{}, NodeSortKeys); err == nil {
+		t.Error("expecting nodes not to sort by ready")
+	}
+}
+
+func TestParseNumberedSortOrder(t *testing.T) {
+	current := SortOrder{Key: SortCpu, Desc: true}
+	for arg, order := range map[string]SortOrder{"0": {}, "1": {Key: SortName}, "4": {Key: SortRestarts}, "6": {Key: SortNode}, "-mem": {Key: SortMem, Desc: true}} {
+		if got, err := ParsePodSortOrder(arg, current); err != nil || got != order {
+			t.Errorf("pods %q: expecting %+v, got %+v %v", arg, order, got, err)
+		}
+	}
+	for arg, order := range map[string]SortOrder{"0": {Key: SortName}, "2": {Key: SortAge}, "pods": {Key: SortPodCount}} {
+		if got, err := ParseNodeSortOrder(arg, current); err != nil || got != order {
+			t.Errorf("nodes %q: expecting %+v, got %+v %v", arg, order, got, err)
+		}
+	}
+	if got, err := ParseNodeSortOrder("3", current); err == nil || got != current {
+		t.Errorf("expecting nodes not to sort by 3, got %+v", got)
+	}
+}
+
+func TestSortPods(t *testing.T) {
+	qty := func(value string) *resource.Quantity {
+		q := resource.MustParse(value)
+		return &q
+	}
+	now := time.Now()
+	pods := []PodModel{
+		{Namespace: "web", Name: "a", PodUsageCpuQty: qty("200m"), PodRequestedMemQty: qty("1Gi"), Restarts: 1, CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+		{Namespace: "db", Name: "b", PodUsageCpuQty: qty("1"), Restarts: 3, CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+		{Namespace: "web", Name: "c", Restarts: 1, PodRequestedMemQty: qty("64Mi"), CreationTimestamp: metav1.NewTime(now.Add(-24 * time.Hour))},
+	}
+	tests := []struct {
+		order SortOrder
+		names []string
+	}{
+		{order: SortOrder{}, names: []string{"b", "a", "c"}},
+		{order: SortOrder{Key: SortCpu}, names: []string{"c", "a", "b"}},
+		{order: SortOrder{Key: SortCpu, Desc: true}, names: []string{"b", "a", "c"}},
+		{order: SortOrder{Key: SortMemRequest, Desc: true}, names: []string{"a", "c", "b"}},
+		{order: SortOrder{Key: SortRestarts, Desc: true}, names: []string{"b", "a", "c"}},
+		{order: SortOrder{Key: SortAge}, names: []string{"b", "a", "c"}},
+	}
+	for _, test := range tests {
+		SortPods(pods, test.order)
+		var names []string
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		if !reflect.DeepEqual(names, test.names) {
+			t.Errorf("order %q: expecting %v, got %v", test.order, test.names, names)
+		}
+	}
+}
+
+func TestSortNodes(t *testing.T) {
+	nodes := []NodeModel{
+		{Name: "node-1", PodsCount: 10},
+		{Name: "node-2", PodsCount: 30},
+		{Name: "node-3", PodsCount: 10},
+	}
+	SortNodes(nodes, SortOrder{Key: SortPodCount, Desc: true})
+	if names := []string{nodes[0].Name, nodes[1].Name, nodes[2].Name}; !reflect.DeepEqual(names, []string{"node-2", "node-1", "node-3"}) {
+		t.Errorf("expecting the busiest node first then names, got %v", names)
+	}
+}
@@ -0,0 +1,197 @@
+package model
+
+import (
+	"sort"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultClassAnnotation marks the storage class given by admission to the
+// claims created without one. Claims left without a class show none.
+const defaultClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// PVCModel is a persistent volume claim with the volume it is bound to and
+// the pods mounting it. Capacity is the capacity of the bound volume.
+type PVCModel struct {
+	Namespace    string
+	Name         string
+	Status       string
+	Volume       string
+	StorageClass string
+	Provisioner  string
+	RequestedQty *resource.Quantity
+	CapacityQty  *resource.Quantity
+	AccessModes  string
+	Pods         []string
+
+	CreationTimestamp metav1.Time
+	TimeSince         string
+}
+
+// Unhealthy reports whether the claim waits for a volume or lost it
+func (m PVCModel) Unhealthy() bool {
+	return m.Status == string(coreV1.ClaimPending) || m.Status == string(coreV1.ClaimLost)
+}
+
+// PVModel is a persistent volume, Claim naming the claim bound to it as namespace/name
+type PVModel struct {
+	Name          string
+	Status        string
+	Claim         string
+	StorageClass  string
+	CapacityQty   *resource.Quantity
+	AccessModes   string
+	ReclaimPolicy string
+	Reason        string
+
+	CreationTimestamp metav1.Time
+	TimeSince         string
+}
+
+// Unhealthy reports whether the volume failed or lost its claim
+func (m PVModel) Unhealthy() bool {
+	return m.Status == string(coreV1.VolumeFailed) || m.Status == string(coreV1.VolumeReleased)
+}
+
+type StorageClassModel struct {
+	Name              string
+	Provisioner       string
+	ReclaimPolicy     string
+	VolumeBindingMode string
+	AllowExpansion    bool
+	Default           bool
+}
+
+// StorageModel is the claims, volumes and storage classes of a cluster
+type StorageModel struct {
+	Claims  []PVCModel
+	Volumes []PVModel
+	Classes []StorageClassModel
+}
+
+// NewStorageModel describes claims, volumes and classes sorted by name. pods
+// are searched for the claims they mount.
+func NewStorageModel(claims []*coreV1.PersistentVolumeClaim, volumes []*coreV1.PersistentVolume, classes []*storageV1.StorageClass, pods []*coreV1.Pod) *StorageModel {
+	storage := &StorageModel{}
+	provisioners := make(map[string]string)
+	for _, class := range classes {
+		model := NewStorageClassModel(class)
+		provisioners[model.Name] = model.Provisioner
+		storage.Classes = append(storage.Classes, model)
+	}
+
+	consumers := make(map[string][]string)
+	for _, pod := range pods {
+		for _, vol := range pod.Spec.Volumes {
+			if vol.PersistentVolumeClaim == nil {
+				continue
+			}
+			key := pod.Namespace + "/" + vol.PersistentVolumeClaim.ClaimName
+			consumers[key] = append(consumers[key], pod.Name)
+		}
+	}
+
+	for _, claim := range claims {
+		model := NewPVCModel(claim, consumers[claim.Namespace+"/"+claim.Name])
+		model.Provisioner = provisioners[model.StorageClass]
+		storage.Claims = append(storage.Claims, model)
+	}
+	for _, volume := range volumes {
+		storage.Volumes = append(storage.Volumes, NewPVModel(volume))
+	}
+
+	sort.Slice(storage.Claims, func(i, j int) bool {
+		a, b := storage.Claims[i], storage.Claims[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	sort.Slice(storage.Volumes, func(i, j int) bool {
+		return storage.Volumes[i].Name < storage.Volumes[j].Name
+	})
+	sort.Slice(storage.Classes, func(i, j int) bool {
+		return storage.Classes[i].Name < storage.Classes[j].Name
+	})
+	return storage
+}
+
+func NewPVCModel(claim *coreV1.PersistentVolumeClaim, pods []string) PVCModel {
+	model := PVCModel{
+		Namespace:         claim.Namespace,
+		Name:              claim.Name,
+		Status:            string(claim.Status.Phase),
+		Volume:            claim.Spec.VolumeName,
+		RequestedQty:      claim.Spec.Resources.Requests.Storage(),
+		CapacityQty:       claim.Status.Capacity.Storage(),
+		AccessModes:       AccessModesText(claim.Spec.AccessModes),
+		Pods:              pods,
+		CreationTimestamp: claim.CreationTimestamp,
+		TimeSince:         timeSince(claim.CreationTimestamp),
+	}
+	if claim.Spec.StorageClassName != nil {
+		model.StorageClass = *claim.Spec.StorageClassName
+	}
+	return model
+}
+
+func NewPVModel(volume *coreV1.PersistentVolume) PVModel {
+	model := PVModel{
+		Name:              volume.Name,
+		Status:            string(volume.Status.Phase),
+		StorageClass:      volume.Spec.StorageClassName,
+		CapacityQty:       volume.Spec.Capacity.Storage(),
+		AccessModes:       AccessModesText(volume.Spec.AccessModes),
+		ReclaimPolicy:     string(volume.Spec.PersistentVolumeReclaimPolicy),
+		Reason:            volume.Status.Reason,
+		CreationTimestamp: volume.CreationTimestamp,
+		TimeSince:         timeSince(volume.CreationTimestamp),
+	}
+	if ref := volume.Spec.ClaimRef; ref != nil {
+		model.Claim = ref.Namespace + "/" + ref.Name
+	}
+	return model
+}
+
+func NewStorageClassModel(class *storageV1.StorageClass) StorageClassModel {
+	model := StorageClassModel{
+		Name:           class.Name,
+		Provisioner:    class.Provisioner,
+		AllowExpansion: class.AllowVolumeExpansion != nil && *class.AllowVolumeExpansion,
+		Default:        class.Annotations[defaultClassAnnotation] == "true",
+	}
+	// the API server defaults both, but objects built elsewhere may not have them
+	model.ReclaimPolicy = string(coreV1.PersistentVolumeReclaimDelete)
+	if class.ReclaimPolicy != nil {
+		model.ReclaimPolicy = string(*class.ReclaimPolicy)
+	}
+	model.VolumeBindingMode = string(storageV1.VolumeBindingImmediate)
+	if class.VolumeBindingMode != nil {
+		model.VolumeBindingMode = string(*class.VolumeBindingMode)
+	}
+	return model
+}
+
+var accessModeNames = map[coreV1.PersistentVolumeAccessMode]string{
+	coreV1.ReadWriteOnce:    "RWO",
+	coreV1.ReadOnlyMany:     "ROX",
+	coreV1.ReadWriteMany:    "RWX",
+	coreV1.ReadWriteOncePod: "RWOP",
+}
+
+// AccessModesText returns access modes abbreviated as kubectl does
+func AccessModesText(modes []coreV1.PersistentVolumeAccessMode) string {
+	names := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		name, ok := accessModeNames[mode]
+		if !ok {
+			name = string(mode)
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ",")
+}
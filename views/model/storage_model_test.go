@@ -0,0 +1,78 @@
+package model
+
+import (
+	"testing"
+
+	coreV1 "k8s.io/api/core/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewStorageModel(t *testing.T) {
+	fast := "fast"
+	classes := []*storageV1.StorageClass{
+		{ObjectMeta: metav1.ObjectMeta{Name: "standard", Annotations: map[string]string{defaultClassAnnotation: "true"}}, Provisioner: "rancher.io/local-path"},
+		{ObjectMeta: metav1.ObjectMeta{Name: fast}, Provisioner: "ebs.csi.aws.com"},
+	}
+	claims := []*coreV1.PersistentVolumeClaim{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "db", Name: "data-pg-0"},
+			Spec: coreV1.PersistentVolumeClaimSpec{
+				StorageClassName: &fast,
+				VolumeName:       "pv-1",
+				AccessModes:      []coreV1.PersistentVolumeAccessMode{coreV1.ReadWriteOnce},
+				Resources:        coreV1.ResourceRequirements{Requests: coreV1.ResourceList{coreV1.ResourceStorage: resource.MustParse("10Gi")}},
+			},
+			Status: coreV1.PersistentVolumeClaimStatus{
+				Phase:    coreV1.ClaimBound,
+				Capacity: coreV1.ResourceList{coreV1.ResourceStorage: resource.MustParse("16Gi")},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "db", Name: "cache"},
+			Status:     coreV1.PersistentVolumeClaimStatus{Phase: coreV1.ClaimPending},
+		},
+	}
+	pods := []*coreV1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "db", Name: "pg-0"},
+		Spec: coreV1.PodSpec{Volumes: []coreV1.Volume{{Name: "data", VolumeSource: coreV1.VolumeSource{
+			PersistentVolumeClaim: &coreV1.PersistentVolumeClaimVolumeSource{ClaimName: "data-pg-0"},
+		}}}},
+	}}
+
+	storage := NewStorageModel(claims, nil, classes, pods)
+	if len(storage.Claims) != 2 || len(storage.Classes) != 2 {
+		t.Fatalf("unexpected storage %+v", storage)
+	}
+	pending, bound := storage.Claims[0], storage.Claims[1]
+	if !pending.Unhealthy() || pending.StorageClass != "" || pending.Provisioner != "" {
+		t.Errorf("expecting a pending claim without class, got %+v", pending)
+	}
+	if bound.Unhealthy() || bound.Provisioner != "ebs.csi.aws.com" || bound.AccessModes != "RWO" {
+		t.Errorf("unexpected bound claim %+v", bound)
+	}
+	if bound.RequestedQty.String() != "10Gi" || bound.CapacityQty.String() != "16Gi" {
+		t.Errorf("expecting 10Gi requested and 16Gi capacity, got %s and %s", bound.RequestedQty, bound.CapacityQty)
+	}
+	if len(bound.Pods) != 1 || bound.Pods[0] != "pg-0" {
+		t.Errorf("expecting claim mounted by pg-0, got %v", bound.Pods)
+	}
+}
+
+func TestNewPVModel(t *testing.T) {
+	pv := &coreV1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: coreV1.PersistentVolumeSpec{
+			Capacity:                      coreV1.ResourceList{coreV1.ResourceStorage: resource.MustParse("16Gi")},
+			AccessModes:                   []coreV1.PersistentVolumeAccessMode{coreV1.ReadWriteOnce, coreV1.ReadOnlyMany},
+			PersistentVolumeReclaimPolicy: coreV1.PersistentVolumeReclaimRetain,
+			ClaimRef:                      &coreV1.ObjectReference{Namespace: "db", Name: "data-pg-0"},
+		},
+		Status: coreV1.PersistentVolumeStatus{Phase: coreV1.VolumeReleased},
+	}
+	model := NewPVModel(pv)
+	if model.Claim != "db/data-pg-0" || model.AccessModes != "RWO,ROX" || model.ReclaimPolicy != "Retain" || !model.Unhealthy() {
+		t.Errorf("unexpected volume %+v", model)
+	}
+}
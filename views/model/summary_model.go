@@ -10,6 +10,7 @@ type ClusterSummary struct {
 	NodesReady              int
 	NodesCount              int
 	Namespaces              int
+	ActiveNamespaces        []string // watched namespaces, nil when all are watched
 	PodsRunning             int
 	PodsAvailable           int
 	Pressures               int
@@ -19,6 +20,7 @@ type ClusterSummary struct {
 	JobsCount               int
 	CronJobsCount           int
 	StatefulSetsReady       int
+	StatefulSetsDesired     int
 	DeploymentsTotal        int
 	DeploymentsReady        int
 	DaemonSetsDesired       int
@@ -34,14 +36,17 @@ type ClusterSummary struct {
 	PVCount                 int
 	PVsTotal                *resource.Quantity
 	PVCCount                int
+	PVCsBound               int
 	PVCsTotal               *resource.Quantity
 
-	KubeletReady		int
-	KubeletCount		int
-	ContainerdReady		int
-	ContainerdCount		int
-	SciniReady		int
-	SciniCount		int
-	EtcdReady		int
-	EtcdCount		int
+	Services  []ServiceSummary
+	EtcdReady int
+	EtcdCount int
+
+	ControlPlaneHealthy   int
+	ControlPlaneUnhealthy int
+
+	WarningEvents int // Warning events retained by the API server
+
+	History MetricsSamples // node usage over the history window
 }
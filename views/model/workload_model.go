@@ -0,0 +1,250 @@
+package model
+
+import (
+	"sort"
+	"strings"
+
+	appsV1 "k8s.io/api/apps/v1"
+	batchV1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	KindDeployment  = "Deployment"
+	KindStatefulSet = "StatefulSet"
+	KindDaemonSet   = "DaemonSet"
+	KindReplicaSet  = "ReplicaSet"
+	KindJob         = "Job"
+	KindCronJob     = "CronJob"
+)
+
+// WorkloadKinds are the kinds listed in workload panels
+var WorkloadKinds = []string{KindDeployment, KindStatefulSet, KindDaemonSet, KindJob, KindCronJob}
+
+var workloadKindAliases = map[string]string{
+	"deploy": KindDeployment, "deployment": KindDeployment, "deployments": KindDeployment,
+	"sts": KindStatefulSet, "statefulset": KindStatefulSet, "statefulsets": KindStatefulSet,
+	"ds": KindDaemonSet, "daemonset": KindDaemonSet, "daemonsets": KindDaemonSet,
+	"job": KindJob, "jobs": KindJob,
+	"cj": KindCronJob, "cronjob": KindCronJob, "cronjobs": KindCronJob,
+}
+
+// ParseWorkloadKind returns the workload kind named by a kubectl style name or short name
+func ParseWorkloadKind(name string) (string, bool) {
+	kind, ok := workloadKindAliases[strings.ToLower(name)]
+	return kind, ok
+}
+
+// WorkloadModel is a workload with the resources of its pods rolled up.
+// Desired, Ready, Updated and Available count replicas, or scheduled pods
+// for daemon sets. Jobs and cron jobs count Active, Succeeded and Failed pods.
+type WorkloadModel struct {
+	Kind      string
+	Namespace string
+	Name      string
+	UID       types.UID
+	Status    string // rollout status
+
+	Desired   int
+	Ready     int
+	Updated   int
+	Available int
+	Active    int
+	Succeeded int
+	Failed    int
+
+	Schedule     string
+	LastSchedule string
+
+	Images            []string
+	CreationTimestamp metav1.Time
+	TimeSince         string
+
+	Pods            int
+	ReadyPods       int
+	Restarts        int
+	RequestedCpuQty *resource.Quantity
+	RequestedMemQty *resource.Quantity
+	UsageCpuQty     *resource.Quantity
+	UsageMemQty     *resource.Quantity
+}
+
+// Key identifies the workload among workloads of all kinds
+func (w *WorkloadModel) Key() string {
+	return WorkloadKey(w.Kind, w.Namespace, w.Name)
+}
+
+func WorkloadKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// AddPod rolls pod up into the workload
+func (w *WorkloadModel) AddPod(pod PodModel) {
+	w.Pods++
+	if pod.TotalContainers > 0 && pod.ReadyContainers == pod.TotalContainers {
+		w.ReadyPods++
+	}
+	w.Restarts += pod.Restarts
+	addQty(w.RequestedCpuQty, pod.PodRequestedCpuQty)
+	addQty(w.RequestedMemQty, pod.PodRequestedMemQty)
+	addQty(w.UsageCpuQty, pod.PodUsageCpuQty)
+	addQty(w.UsageMemQty, pod.PodUsageMemQty)
+}
+
+func addQty(total, qty *resource.Quantity) {
+	if total != nil && qty != nil {
+		total.Add(*qty)
+	}
+}
+
+func newWorkloadModel(kind string, meta metav1.ObjectMeta, template v1.PodTemplateSpec) *WorkloadModel {
+	var images []string
+	for _, container := range template.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	return &WorkloadModel{
+		Kind:              kind,
+		Namespace:         meta.Namespace,
+		Name:              meta.Name,
+		UID:               meta.UID,
+		Images:            images,
+		CreationTimestamp: meta.CreationTimestamp,
+		TimeSince:         timeSince(meta.CreationTimestamp),
+		RequestedCpuQty:   resource.NewQuantity(0, resource.DecimalSI),
+		RequestedMemQty:   resource.NewQuantity(0, resource.DecimalSI),
+		UsageCpuQty:       resource.NewQuantity(0, resource.DecimalSI),
+		UsageMemQty:       resource.NewQuantity(0, resource.DecimalSI),
+	}
+}
+
+func NewDeploymentModel(dep *appsV1.Deployment) *WorkloadModel {
+	model := newWorkloadModel(KindDeployment, dep.ObjectMeta, dep.Spec.Template)
+	model.Desired = replicas(dep.Spec.Replicas)
+	model.Ready = int(dep.Status.ReadyReplicas)
+	model.Updated = int(dep.Status.UpdatedReplicas)
+	model.Available = int(dep.Status.AvailableReplicas)
+
+	// as reported by kubectl rollout status
+	switch {
+	case dep.Spec.Paused:
+		model.Status = "Paused"
+	case deploymentStalled(dep):
+		model.Status = "Stalled"
+	case dep.Status.ObservedGeneration < dep.Generation,
+		model.Updated < model.Desired,
+		int(dep.Status.Replicas) > model.Updated,
+		model.Available < model.Updated:
+		model.Status = "Progressing"
+	default:
+		model.Status = "Complete"
+	}
+	return model
+}
+
+func deploymentStalled(dep *appsV1.Deployment) bool {
+	for _, cond := range dep.Status.Conditions {
+		if cond.Type == appsV1.DeploymentProgressing && cond.Reason == "ProgressDeadlineExceeded" {
+			return true
+		}
+	}
+	return false
+}
+
+func NewStatefulSetModel(set *appsV1.StatefulSet) *WorkloadModel {
+	model := newWorkloadModel(KindStatefulSet, set.ObjectMeta, set.Spec.Template)
+	model.Desired = replicas(set.Spec.Replicas)
+	model.Ready = int(set.Status.ReadyReplicas)
+	model.Updated = int(set.Status.UpdatedReplicas)
+	model.Available = int(set.Status.AvailableReplicas)
+
+	switch {
+	case set.Status.ObservedGeneration < set.Generation,
+		model.Ready < model.Desired,
+		set.Spec.UpdateStrategy.Type == appsV1.RollingUpdateStatefulSetStrategyType && set.Status.UpdateRevision != set.Status.CurrentRevision:
+		model.Status = "Progressing"
+	default:
+		model.Status = "Complete"
+	}
+	return model
+}
+
+func NewDaemonSetModel(set *appsV1.DaemonSet) *WorkloadModel {
+	model := newWorkloadModel(KindDaemonSet, set.ObjectMeta, set.Spec.Template)
+	model.Desired = int(set.Status.DesiredNumberScheduled)
+	model.Ready = int(set.Status.NumberReady)
+	model.Updated = int(set.Status.UpdatedNumberScheduled)
+	model.Available = int(set.Status.NumberAvailable)
+
+	switch {
+	case set.Status.ObservedGeneration < set.Generation,
+		model.Updated < model.Desired,
+		model.Available < model.Desired:
+		model.Status = "Progressing"
+	default:
+		model.Status = "Complete"
+	}
+	return model
+}
+
+func NewJobModel(job *batchV1.Job) *WorkloadModel {
+	model := newWorkloadModel(KindJob, job.ObjectMeta, job.Spec.Template)
+	model.Desired = replicas(job.Spec.Completions)
+	model.Active = int(job.Status.Active)
+	model.Succeeded = int(job.Status.Succeeded)
+	model.Failed = int(job.Status.Failed)
+
+	model.Status = "Running"
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		model.Status = "Suspended"
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != v1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchV1.JobComplete:
+			model.Status = "Complete"
+		case batchV1.JobFailed:
+			model.Status = "Failed"
+		}
+	}
+	return model
+}
+
+func NewCronJobModel(cronJob *batchV1.CronJob) *WorkloadModel {
+	model := newWorkloadModel(KindCronJob, cronJob.ObjectMeta, cronJob.Spec.JobTemplate.Spec.Template)
+	model.Schedule = cronJob.Spec.Schedule
+	model.Active = len(cronJob.Status.Active)
+	model.LastSchedule = "-"
+	if cronJob.Status.LastScheduleTime != nil {
+		model.LastSchedule = timeSince(*cronJob.Status.LastScheduleTime)
+	}
+
+	switch {
+	case cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend:
+		model.Status = "Suspended"
+	case model.Active > 0:
+		model.Status = "Active"
+	default:
+		model.Status = "Scheduled"
+	}
+	return model
+}
+
+// replicas returns the value of an optional replica count, which defaults to 1
+func replicas(count *int32) int {
+	if count == nil {
+		return 1
+	}
+	return int(*count)
+}
+
+// SortWorkloadModels sorts workloads by kind, namespace and name
+func SortWorkloadModels(workloads []WorkloadModel) {
+	sort.Slice(workloads, func(i, j int) bool {
+		return workloads[i].Key() < workloads[j].Key()
+	})
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"testing"
+
+	appsV1 "k8s.io/api/apps/v1"
+	batchV1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewDeploymentModelStatus(t *testing.T) {
+	three := int32(3)
+	dep := &appsV1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "api", Generation: 2},
+		Spec: appsV1.DeploymentSpec{Replicas: &three, Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+			Containers: []v1.Container{{Image: "api:1.2"}, {Image: "envoy:1.20"}},
+		}}},
+		Status: appsV1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, ReadyReplicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3},
+	}
+	if model := NewDeploymentModel(dep); model.Status != "Complete" || model.Desired != 3 || len(model.Images) != 2 {
+		t.Errorf("unexpected complete deployment %+v", model)
+	}
+
+	dep.Status.UpdatedReplicas = 1
+	if model := NewDeploymentModel(dep); model.Status != "Progressing" {
+		t.Errorf("expecting a progressing rollout, got %s", model.Status)
+	}
+
+	dep.Status.Conditions = []appsV1.DeploymentCondition{{Type: appsV1.DeploymentProgressing, Reason: "ProgressDeadlineExceeded"}}
+	if model := NewDeploymentModel(dep); model.Status != "Stalled" {
+		t.Errorf("expecting a stalled rollout, got %s", model.Status)
+	}
+}
+
+func TestNewJobModelStatus(t *testing.T) {
+	job := &batchV1.Job{Status: batchV1.JobStatus{Succeeded: 1, Conditions: []batchV1.JobCondition{
+		{Type: batchV1.JobComplete, Status: v1.ConditionTrue},
+	}}}
+	if model := NewJobModel(job); model.Status != "Complete" || model.Desired != 1 || model.Succeeded != 1 {
+		t.Errorf("unexpected complete job %+v", model)
+	}
+
+	suspend := true
+	cronJob := &batchV1.CronJob{Spec: batchV1.CronJobSpec{Schedule: "*/5 * * * *", Suspend: &suspend}}
+	if model := NewCronJobModel(cronJob); model.Status != "Suspended" || model.LastSchedule != "-" {
+		t.Errorf("unexpected suspended cron job %+v", model)
+	}
+}
+
+func TestWorkloadAddPod(t *testing.T) {
+	workload := NewStatefulSetModel(&appsV1.StatefulSet{})
+	workload.AddPod(PodModel{
+		ReadyContainers: 1, TotalContainers: 1, Restarts: 2,
+		PodRequestedCpuQty: resource.NewMilliQuantity(250, resource.DecimalSI),
+		PodUsageCpuQty:     resource.NewMilliQuantity(100, resource.DecimalSI),
+	})
+	workload.AddPod(PodModel{
+		ReadyContainers: 0, TotalContainers: 1, Restarts: 1,
+		PodRequestedCpuQty: resource.NewMilliQuantity(250, resource.DecimalSI),
+	})
+	if workload.Pods != 2 || workload.ReadyPods != 1 || workload.Restarts != 3 {
+		t.Errorf("unexpected pod counts %+v", workload)
+	}
+	if workload.RequestedCpuQty.MilliValue() != 500 || workload.UsageCpuQty.MilliValue() != 100 {
+		t.Errorf("unexpected cpu requested %s and used %s", workload.RequestedCpuQty, workload.UsageCpuQty)
+	}
+}
+
+func TestParseWorkloadKind(t *testing.T) {
+	for name, expected := range map[string]string{"deploy": KindDeployment, "STS": KindStatefulSet, "cronjobs": KindCronJob} {
+		if kind, ok := ParseWorkloadKind(name); !ok || kind != expected {
+			t.Errorf("%s: expecting %s, got %s", name, expected, kind)
+		}
+	}
+	if _, ok := ParseWorkloadKind("pods"); ok {
+		t.Error("expecting pods not to be a workload kind")
+	}
+}
@@ -0,0 +1,388 @@
+package overview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/pjy0381/ktop/ui"
+	"github.com/pjy0381/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// columnWidth is the width policy of a column: its share of the spare width
+// of the table, 0 to fit its widest cell, and the width its cells are cut at,
+// 0 for no limit
+type columnWidth struct {
+	expansion int
+	maxWidth  int
+}
+
+var (
+	expandWidth  = columnWidth{expansion: 100}
+	fitWidth     = columnWidth{}
+	limitedWidth = columnWidth{expansion: 100, maxWidth: 40}
+)
+
+var usageColorKeys = ui.ColorKeys{0: "green", 50: "yellow", 90: "red"}
+
+// podCell is what the columns of the pod list draw a cell of
+type podCell struct {
+	pod             model.PodModel
+	filter          *model.Filter
+	indent          string // prefix of the pod name, in the tree
+	metricsDisabled bool
+}
+
+// podColumn is a column of the pod list
+type podColumn struct {
+	id      string // name of the column in the cols command and the config file
+	header  string
+	sortKey string
+	width   columnWidth
+	text    func(c podCell) string
+	color   func(c podCell) tcell.Color // nil for white
+	// group returns the text of the rows of controllers in the tree, nil for none
+	group func(c podGroupCell) string
+}
+
+// podGroupCell is what the columns of the pod list draw a cell of a
+// controller row of
+type podGroupCell struct {
+	group           model.PodGroupModel
+	marker          string // ▼ when the pods of the group are shown, ▶ when collapsed
+	metricsDisabled bool
+}
+
+// podColumns is the registry of the columns the pod list can draw
+var podColumns = []podColumn{
+	{
+		id: "ns", header: "NAMESPACE", sortKey: model.SortNamespace, width: expandWidth,
+		text: func(c podCell) string {
+			return highlight(c.pod.Namespace, c.filter.Matches(model.FilterNamespace, c.pod.Namespace))
+		},
+		group: func(c podGroupCell) string { return c.group.Namespace },
+	},
+	{
+		id: "node", header: "NODE", sortKey: model.SortNode, width: expandWidth,
+		text: func(c podCell) string {
+			return highlight(c.pod.Node, c.filter.Matches(model.FilterNode, c.pod.Node))
+		},
+	},
+	{
+		id: "name", header: "POD", sortKey: model.SortName, width: expandWidth,
+		text: func(c podCell) string {
+			return c.indent + highlight(c.pod.Name, c.filter.Matches("", c.pod.Name))
+		},
+		group: func(c podGroupCell) string { return c.marker + " " + ownerText(c.group.Owner) },
+	},
+	{
+		id: "ready", header: "READY", sortKey: model.SortReady, width: expandWidth,
+		text: func(c podCell) string {
+			return readyText(c.pod.ReadyContainers, c.pod.TotalContainers)
+		},
+		group: func(c podGroupCell) string { return readyText(c.group.ReadyPods, len(c.group.Pods)) },
+	},
+	{
+		id: "status", header: "STATUS", sortKey: model.SortStatus, width: expandWidth,
+		text: func(c podCell) string {
+			return highlight(c.pod.Status, c.filter.Matches(model.FilterStatus, c.pod.Status))
+		},
+		color: func(c podCell) tcell.Color {
+			switch {
+			case strings.Contains(c.pod.Status, "Running"):
+				return tcell.ColorDarkGreen
+			case strings.Contains(c.pod.Status, "Error"):
+				return tcell.ColorDarkRed
+			}
+			return tcell.ColorYellow
+		},
+	},
+	{
+		id: "restarts", header: "RESTARTS", sortKey: model.SortRestarts, width: expandWidth,
+		text:  func(c podCell) string { return fmt.Sprintf("%d", c.pod.Restarts) },
+		group: func(c podGroupCell) string { return fmt.Sprintf("%d", c.group.Restarts) },
+	},
+	{
+		id: "age", header: "AGE", sortKey: model.SortAge, width: expandWidth,
+		text: func(c podCell) string { return c.pod.TimeSince },
+	},
+	{
+		id: "vols", header: "VOLS", width: expandWidth,
+		text: func(c podCell) string { return fmt.Sprintf("%d/%d", c.pod.Volumes, c.pod.VolMounts) },
+	},
+	{
+		id: "ip", header: "IP", width: expandWidth,
+		text: func(c podCell) string { return highlight(c.pod.IP, c.filter.Matches("", c.pod.IP)) },
+	},
+	{
+		id: "cpu", header: "CPU", sortKey: model.SortCpu, width: expandWidth,
+		text: podCpuText,
+		group: func(c podGroupCell) string {
+			if c.metricsDisabled {
+				return cpuText(c.group.RequestedCpuQty)
+			}
+			return cpuText(c.group.UsageCpuQty)
+		},
+	},
+	{
+		id: "mem", header: "MEMORY", sortKey: model.SortMem, width: expandWidth,
+		text: podMemText,
+		group: func(c podGroupCell) string {
+			if c.metricsDisabled {
+				return memText(c.group.RequestedMemQty)
+			}
+			return memText(c.group.UsageMemQty)
+		},
+	},
+	{
+		id: "qos", header: "QOS", width: fitWidth,
+		text: func(c podCell) string { return model.OrDash(c.pod.QOSClass) },
+		color: func(c podCell) tcell.Color {
+			if c.pod.QOSClass == "BestEffort" {
+				return tcell.ColorYellow
+			}
+			return tcell.ColorWhite
+		},
+	},
+	{
+		id: "limits", header: "LIMITS", width: fitWidth,
+		text: func(c podCell) string {
+			return fmt.Sprintf("%s/%s", cpuText(c.pod.PodLimitCpuQty), memText(c.pod.PodLimitMemQty))
+		},
+	},
+	{
+		id: "owner", header: "OWNER", width: limitedWidth,
+		text: func(c podCell) string {
+			owner, ok := c.pod.TopController()
+			if !ok {
+				return "-"
+			}
+			return owner.Kind + "/" + owner.Name
+		},
+		group: func(c podGroupCell) string { return ownerText(c.group.Owner) },
+	},
+	{
+		id: "ipfamily", header: "IP FAMILY", width: fitWidth,
+		text: func(c podCell) string { return model.OrDash(c.pod.IPFamily()) },
+	},
+	{
+		id: "image", header: "IMAGE", width: limitedWidth,
+		text: func(c podCell) string { return model.OrDash(strings.Join(c.pod.Images, ",")) },
+	},
+}
+
+// defaultPodColumns are the ids of the columns of the pod list unless configured otherwise
+var defaultPodColumns = []string{"ns", "node", "name", "ready", "status", "restarts", "age", "vols", "ip", "cpu", "mem"}
+
+// podColumnIDs returns the ids of the registered pod columns
+func podColumnIDs() []string {
+	ids := make([]string, len(podColumns))
+	for i, col := range podColumns {
+		ids[i] = col.id
+	}
+	return ids
+}
+
+func findPodColumn(id string) (podColumn, bool) {
+	for _, col := range podColumns {
+		if col.id == id {
+			return col, true
+		}
+	}
+	return podColumn{}, false
+}
+
+func ownerText(owner model.OwnerModel) string {
+	if owner.Kind == "" {
+		return "(no controller)"
+	}
+	return owner.Kind + "/" + owner.Name
+}
+
+func readyText(ready, total int) string {
+	color := "[green]"
+	if ready != total {
+		color = "[red]"
+	}
+	return fmt.Sprintf(color+"%d[white]/%d", ready, total)
+}
+
+// podCpuText draws the cpu usage of a pod, or its requests without metrics,
+// as a bar of the allocatable cpu of its node
+func podCpuText(c podCell) string {
+	pod := c.pod
+	if c.metricsDisabled {
+		ratio := ui.GetRatio(float64(pod.PodRequestedCpuQty.MilliValue()), float64(pod.NodeAllocatableCpuQty.MilliValue()))
+		graph := ui.BarGraph(10, ratio, usageColorKeys)
+		return fmt.Sprintf("[white][%s[white]] %dm %02.1f%%", graph, pod.PodRequestedCpuQty.MilliValue(), ratio*100)
+	}
+	ratio := ui.GetRatio(float64(pod.PodUsageCpuQty.MilliValue()), float64(pod.NodeAllocatableCpuQty.MilliValue()))
+	graph := ui.BarGraph(10, ratio, usageColorKeys)
+	// scaled to the pod peak, a pod using a sliver of its node would draw a flat line
+	return fmt.Sprintf("[white][%s[white]] %dm %02.1f%%", graph, pod.PodUsageCpuQty.MilliValue(), ratio*100) +
+		historyText(pod.History.Cpu(), 0)
+}
+
+// podMemText draws the memory of a pod like podCpuText
+func podMemText(c podCell) string {
+	pod := c.pod
+	if c.metricsDisabled {
+		ratio := ui.GetRatio(float64(pod.PodRequestedMemQty.MilliValue()), float64(pod.NodeAllocatableMemQty.MilliValue()))
+		graph := ui.BarGraph(10, ratio, usageColorKeys)
+		return fmt.Sprintf("[white][%s[white]] %dGi %02.1f%%", graph, pod.PodRequestedMemQty.ScaledValue(resource.Giga), ratio*100)
+	}
+	ratio := ui.GetRatio(float64(pod.PodUsageMemQty.MilliValue()), float64(pod.NodeUsageMemQty.MilliValue()))
+	graph := ui.BarGraph(10, ratio, usageColorKeys)
+	return fmt.Sprintf("[white][%s[white]] %dMi %02.1f%%", graph, pod.PodUsageMemQty.ScaledValue(resource.Mega), ratio*100) +
+		historyText(pod.History.Mem(), 0)
+}
+
+// nodeCell is what the columns of the node list draw a cell of
+type nodeCell struct {
+	node            model.NodeModel
+	filter          *model.Filter
+	metricsDisabled bool
+}
+
+// nodeColumn is a column of the node list, like podColumn
+type nodeColumn struct {
+	id      string
+	header  string
+	sortKey string
+	width   columnWidth
+	text    func(c nodeCell) string
+	color   func(c nodeCell) tcell.Color // nil for white
+}
+
+// serviceColumnPrefix prefixes the ids of the host service columns, which
+// keeps services from naming a built-in column
+const serviceColumnPrefix = "svc:"
+
+// serviceColumnID returns the id of the node column of host service svc
+func serviceColumnID(svc string) string {
+	return serviceColumnPrefix + svc
+}
+
+// nodeColumns returns the registry of the columns the node list can draw,
+// with a column per host service, see serviceColumnID
+func nodeColumns(services []string) []nodeColumn {
+	columns := []nodeColumn{
+		{
+			id: "name", header: "NAME", sortKey: model.SortName, width: expandWidth,
+			text: func(c nodeCell) string {
+				return highlight(c.node.Name, c.filter.Matches(model.FilterNode, c.node.Name))
+			},
+		},
+		{
+			id: "status", header: "STATUS", sortKey: model.SortStatus, width: expandWidth,
+			text: func(c nodeCell) string {
+				return highlight(c.node.Status, c.filter.Matches(model.FilterStatus, c.node.Status))
+			},
+			color: func(c nodeCell) tcell.Color {
+				switch c.node.Status {
+				case "Ready":
+					return tcell.ColorDarkGreen
+				case "Error":
+					return tcell.ColorDarkRed
+				}
+				return tcell.ColorYellow
+			},
+		},
+		{
+			id: "age", header: "AGE", sortKey: model.SortAge, width: expandWidth,
+			text: func(c nodeCell) string { return c.node.TimeSinceStart },
+		},
+		{
+			id: "ips", header: "INT/EXT IPs", width: expandWidth,
+			text: func(c nodeCell) string {
+				return fmt.Sprintf("%s/%s", highlight(c.node.InternalIP, c.filter.Matches("", c.node.InternalIP)), highlight(c.node.ExternalIP, c.filter.Matches("", c.node.ExternalIP)))
+			},
+		},
+		{
+			id: "pods", header: "PODS/IMGs", sortKey: model.SortPodCount, width: expandWidth,
+			text: func(c nodeCell) string { return fmt.Sprintf("%d/%d", c.node.PodsCount, c.node.ContainerImagesCount) },
+		},
+	}
+	for _, svc := range services {
+		svc := svc
+		columns = append(columns, nodeColumn{
+			id: serviceColumnID(svc), header: serviceTitle(svc), width: expandWidth,
+			text:  func(c nodeCell) string { return string(c.node.Services[svc]) },
+			color: func(c nodeCell) tcell.Color { return serviceStateColor(c.node.Services[svc]) },
+		})
+	}
+	return append(columns,
+		nodeColumn{id: "cpu", header: "CPU", sortKey: model.SortCpu, width: expandWidth, text: nodeCpuText},
+		nodeColumn{id: "mem", header: "MEM", sortKey: model.SortMem, width: expandWidth, text: nodeMemText},
+		nodeColumn{
+			id: "pool", header: "POOL", width: fitWidth,
+			text: func(c nodeCell) string { return model.OrDash(c.node.Pool) },
+		},
+		nodeColumn{
+			id: "version", header: "VERSION", width: fitWidth,
+			text: func(c nodeCell) string { return model.OrDash(c.node.KubeletVersion) },
+		},
+		nodeColumn{
+			id: "runtime", header: "RUNTIME", width: limitedWidth,
+			text: func(c nodeCell) string { return model.OrDash(c.node.ContainerRuntimeVersion) },
+		},
+	)
+}
+
+func findNodeColumn(columns []nodeColumn, id string) (nodeColumn, bool) {
+	for _, col := range columns {
+		if col.id == id {
+			return col, true
+		}
+	}
+	return nodeColumn{}, false
+}
+
+// nodeColumnIDs returns the ids of the columns the node list can draw
+func nodeColumnIDs(services []string) []string {
+	var ids []string
+	for _, col := range nodeColumns(services) {
+		ids = append(ids, col.id)
+	}
+	return ids
+}
+
+// defaultNodeColumns returns the ids of the columns of the node list unless
+// configured otherwise, which include the host services
+func defaultNodeColumns(services []string) []string {
+	ids := []string{"name", "status", "age", "ips", "pods"}
+	for _, svc := range services {
+		ids = append(ids, serviceColumnID(svc))
+	}
+	return append(ids, "cpu", "mem")
+}
+
+// nodeCpuText draws the cpu usage of a node, or its pod requests without
+// metrics, as a bar of its allocatable cpu
+func nodeCpuText(c nodeCell) string {
+	node := c.node
+	if c.metricsDisabled {
+		ratio := ui.GetRatio(float64(node.RequestedPodCpuQty.MilliValue()), float64(node.AllocatableCpuQty.MilliValue()))
+		graph := ui.BarGraph(10, ratio, usageColorKeys)
+		return fmt.Sprintf("[white][%s[white]] %dm/%dm (%.1f%%)", graph, node.RequestedPodCpuQty.MilliValue(), node.AllocatableCpuQty.MilliValue(), ratio*100)
+	}
+	ratio := ui.GetRatio(float64(node.UsageCpuQty.MilliValue()), float64(node.AllocatableCpuQty.MilliValue()))
+	graph := ui.BarGraph(10, ratio, usageColorKeys)
+	return fmt.Sprintf("[white][%s[white]] %dm/%dm (%.1f%%)", graph, node.UsageCpuQty.MilliValue(), node.AllocatableCpuQty.MilliValue(), ratio*100) +
+		historyText(node.History.Cpu(), float64(node.AllocatableCpuQty.MilliValue()))
+}
+
+// nodeMemText draws the memory of a node like nodeCpuText
+func nodeMemText(c nodeCell) string {
+	node := c.node
+	if c.metricsDisabled {
+		ratio := ui.GetRatio(float64(node.RequestedPodMemQty.MilliValue()), float64(node.AllocatableMemQty.MilliValue()))
+		graph := ui.BarGraph(10, ratio, usageColorKeys)
+		return fmt.Sprintf("[white][%s[white]] %.1fGi/%.1fGi (%.1f%%)", graph, convertMilliValueToGigabytes(node.RequestedPodMemQty.MilliValue()), convertMilliValueToGigabytes(node.AllocatableMemQty.MilliValue()), ratio*100)
+	}
+	ratio := ui.GetRatio(float64(node.UsageMemQty.MilliValue()), float64(node.AllocatableMemQty.MilliValue()))
+	graph := ui.BarGraph(10, ratio, usageColorKeys)
+	return fmt.Sprintf("[white][%s[white]] %.1fGi/%.1fGi (%.1f%%)", graph, convertMilliValueToGigabytes(node.UsageMemQty.MilliValue()), convertMilliValueToGigabytes(node.AllocatableMemQty.MilliValue()), ratio*100) +
+		historyText(node.History.Mem(), float64(node.AllocatableMemQty.Value()))
+}
@@ -0,0 +1,135 @@
+package overview
+
+import (
+	"github.com/pjy0381/ktop/ui"
+)
+
+// registerCommands registers the commands of the overview with the
+// application, to be typed in the command input or bound to keys
+func (p *MainPanel) registerCommands() error {
+	commands := []ui.Command{
+		{Name: "nodes", Aliases: []string{"n"}, Args: "[[-]key]", Help: "show or hide the nodes, or sort them by key", Run: p.nodesCommand},
+		{Name: "pods", Aliases: []string{"p"}, Args: "[[-]key|tree]", Help: "show or hide the pods, sort them by key or group them under their controllers", Run: p.podsCommand},
+		{Name: "filter", Keys: []string{"/"}, Help: "filter the pods and nodes by the text typed after /", Run: p.startFilter},
+		{Name: "columns", Aliases: []string{"cols"}, Args: "[pods|nodes [columns]]", Help: "show, add (+id), hide (-id) or reset (default) the columns of the pods or nodes", Run: p.handleColumnsCommand},
+		{Name: "save", Aliases: []string{"s"}, Args: "[name]", Help: "save a snapshot of the pods, nodes and workloads", Run: p.saveSnapshot},
+		{Name: "snapshots", Aliases: []string{"u"}, Args: "[name]", Help: "show or hide the snapshots, or show the pods of a snapshot", Run: p.handleSnapshotsCommand},
+		{Name: "diff", Aliases: []string{"v"}, Args: "[from [to]]", Help: "show or hide the diff of a snapshot with the cluster or another snapshot", Run: p.handleDiffCommand},
+		{Name: "export", Args: "[name [file]]", Help: "write a snapshot to a file", Run: p.exportSnapshot},
+		{Name: "load", Args: "<file>", MinArgs: 1, Help: "add the snapshot of a file", Run: p.loadSnapshot},
+		{Name: "close", Aliases: []string{"c"}, Help: "close the detail panels and hide the lists", Run: func([]string) { p.closePanels() }},
+	}
+	commands = append(commands, p.liveOnly(
+		ui.Command{Name: "controlplane", Aliases: []string{"cp"}, Help: "show or hide the control plane components", Run: func([]string) {
+			p.togglePanel(&p.controlPlanePanel, &p.controlPlaneVisible)
+		}},
+		ui.Command{Name: "events", Aliases: []string{"e"}, Args: "[w|ns [namespace]|sel|all]", Help: "show or hide the events, or narrow them", Run: p.handleEventsCommand},
+		ui.Command{Name: "workloads", Aliases: []string{"w"}, Args: "<kind>", Help: "show or hide the workloads of a kind, such as deploy, sts, ds, job or cj", Run: p.handleWorkloadCommand},
+		ui.Command{Name: "storage", Aliases: []string{"st"}, Help: "show or hide the volumes and claims", Run: func([]string) { p.toggleStorage() }},
+		ui.Command{Name: "context", Aliases: []string{"ctx"}, Args: "[name]", Help: "show or hide the kubeconfig contexts, or switch to a context", Run: p.contextCommand},
+		ui.Command{Name: "namespace", Aliases: []string{"-n"}, Args: "<namespace>", MinArgs: 1, Help: "watch a namespace", Run: func(args []string) {
+			p.switchNamespace(args[0])
+		}},
+		ui.Command{Name: "all-namespaces", Aliases: []string{"-A"}, Help: "watch all namespaces", Run: func([]string) { p.switchNamespace("") }},
+		ui.Command{Name: "select", Aliases: []string{"sel"}, Args: "[selector...]", Help: "watch the pods matching label or field: selectors and the nodes matching node: selectors", Run: p.switchSelectors},
+	)...)
+	commands = append(commands, p.replayCommands()...)
+	return p.app.Commands().Register(commands...)
+}
+
+// runCommand runs a command line typed in the command input
+func (p *MainPanel) runCommand(line string) {
+	if err := p.app.Commands().Run(line); err != nil {
+		p.commandInput.SetPlaceholder(err.Error())
+	}
+}
+
+// startFilter focuses the command input to type a filter
+func (p *MainPanel) startFilter([]string) {
+	p.commandInput.SetText("/")
+	p.app.Focus(p.commandInput)
+}
+
+// nodesCommand sorts the nodes by args[0], see model.NodeSortKeys, or
+// toggles the node panel without args
+func (p *MainPanel) nodesCommand(args []string) {
+	if len(args) > 0 && !p.sortNodes(args[0]) {
+		return
+	}
+	if len(args) == 0 || !p.nodePanelVisible {
+		p.togglePanel(&p.nodePanel, &p.nodePanelVisible)
+	}
+}
+
+// podsCommand sorts the pods by args[0], see model.PodSortKeys, groups
+// them under their controllers with "tree", or toggles the pod panel
+// without args
+func (p *MainPanel) podsCommand(args []string) {
+	if len(args) > 0 && args[0] == "tree" {
+		pods := p.podPanel.(*podPanel)
+		pods.SetTree(!pods.Tree())
+		if !p.podPanelVisible {
+			p.togglePanel(&p.podPanel, &p.podPanelVisible)
+		}
+		return
+	}
+	if len(args) > 0 && !p.sortPods(args[0]) {
+		return
+	}
+	if len(args) == 0 || !p.podPanelVisible {
+		p.togglePanel(&p.podPanel, &p.podPanelVisible)
+	}
+}
+
+// contextCommand switches to context args[0], or toggles the context panel
+// without args
+func (p *MainPanel) contextCommand(args []string) {
+	if len(args) > 0 {
+		p.switchContext(args[0])
+		return
+	}
+	if !p.contextVisible {
+		p.contextPanel.Clear()
+		p.contextPanel.DrawBody(p.app.GetK8sClient().GetContextModels())
+	}
+	p.togglePanel(&p.contextPanel, &p.contextVisible)
+	if p.contextVisible {
+		p.app.Focus(p.contextPanel.GetRootView())
+	}
+}
+
+// closePanels closes the detail and log panels and hides the other panels
+func (p *MainPanel) closePanels() {
+	p.closePodLogs()
+	p.closePodDetail()
+	p.closeNodeDetail()
+	if p.contextVisible {
+		p.togglePanel(&p.contextPanel, &p.contextVisible)
+	}
+	if p.controlPlaneVisible {
+		p.togglePanel(&p.controlPlanePanel, &p.controlPlaneVisible)
+	}
+	if p.eventsVisible {
+		p.toggleEvents()
+	}
+	for i := range p.workloadPanels {
+		if p.workloadVisible[i] {
+			p.toggleWorkloads(i)
+		}
+	}
+	if p.storageVisible {
+		p.toggleStorage()
+	}
+	if p.nodePanelVisible {
+		p.togglePanel(&p.nodePanel, &p.nodePanelVisible)
+	}
+	if p.podPanelVisible {
+		p.togglePanel(&p.podPanel, &p.podPanelVisible)
+	}
+	if p.snapshotVisible {
+		p.togglePanel(&p.snapshotPanel, &p.snapshotVisible)
+	}
+	if p.diffVisible {
+		p.togglePanel(&p.diffPanel, &p.diffVisible)
+	}
+}
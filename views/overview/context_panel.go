@@ -0,0 +1,128 @@
+package overview
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/ui"
+	"github.com/pjy0381/ktop/views/model"
+)
+
+// contextPanel lists the kubeconfig contexts, calling onSelect with the
+// name of the context picked with Enter
+type contextPanel struct {
+	app      *application.Application
+	title    string
+	root     *tview.Flex
+	children []tview.Primitive
+	listCols []string
+	list     *tview.Table
+	laidout  bool
+	models   []model.ContextModel
+	onSelect func(name string)
+}
+
+func NewContextPanel(app *application.Application, title string, onSelect func(name string)) ui.Panel {
+	p := &contextPanel{app: app, title: title, onSelect: onSelect}
+	p.Layout(nil)
+	return p
+}
+
+func (p *contextPanel) GetTitle() string {
+	return p.title
+}
+
+func (p *contextPanel) Layout(_ interface{}) {
+	if !p.laidout {
+		p.list = tview.NewTable()
+		p.list.SetFixed(1, 0)
+		p.list.SetBorder(false)
+		p.list.SetBorders(false)
+		p.list.SetFocusFunc(func() {
+			p.list.SetSelectable(true, false)
+			p.list.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorYellow).Foreground(tcell.ColorBlue))
+		})
+		p.list.SetBlurFunc(func() {
+			p.list.SetSelectable(false, false)
+		})
+		p.list.SetSelectedFunc(func(row, _ int) {
+			if row < 1 || row > len(p.models) || p.onSelect == nil {
+				return
+			}
+			p.onSelect(p.models[row-1].Name)
+		})
+
+		p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(p.list, 0, 1, true)
+		p.root.SetBorder(true)
+		p.root.SetTitle(p.GetTitle())
+		p.root.SetTitleAlign(tview.AlignLeft)
+		p.laidout = true
+	}
+}
+
+func (p *contextPanel) DrawHeader(data interface{}) {
+	cols, ok := data.([]string)
+	if !ok {
+		panic(fmt.Sprintf("contextPanel.DrawHeader got unexpected data type %T", data))
+	}
+
+	p.listCols = cols
+	for i, col := range p.listCols {
+		p.list.SetCell(0, i,
+			tview.NewTableCell(col).
+				SetTextColor(tcell.ColorBlack).
+				SetBackgroundColor(tcell.ColorDarkGray).
+				SetAlign(tview.AlignLeft).
+				SetExpansion(100).
+				SetSelectable(false),
+		)
+	}
+	p.list.SetFixed(1, 0)
+}
+
+// DrawBody lists the contexts, the current one marked with *
+func (p *contextPanel) DrawBody(data interface{}) {
+	models, ok := data.([]model.ContextModel)
+	if !ok {
+		panic(fmt.Sprintf("contextPanel.DrawBody got unexpected type %T", data))
+	}
+	p.models = models
+
+	for i, kubeCtx := range models {
+		name := kubeCtx.Name
+		color := tcell.ColorWhite
+		if kubeCtx.Current {
+			name = "* " + name
+			color = tcell.ColorGreen
+		}
+		for col, text := range []string{name, kubeCtx.Cluster, kubeCtx.User, kubeCtx.Namespace} {
+			p.list.SetCell(
+				i+1, col,
+				&tview.TableCell{
+					Text:  text,
+					Color: color,
+					Align: tview.AlignLeft,
+				},
+			)
+		}
+	}
+}
+
+func (p *contextPanel) DrawFooter(_ interface{}) {}
+
+func (p *contextPanel) Clear() {
+	p.list.Clear()
+	p.Layout(nil)
+	p.DrawHeader(p.listCols)
+}
+
+func (p *contextPanel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+func (p *contextPanel) GetChildrenViews() []tview.Primitive {
+	return p.children
+}
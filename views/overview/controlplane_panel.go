@@ -0,0 +1,148 @@
+package overview
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/ui"
+	"github.com/pjy0381/ktop/views/model"
+)
+
+type controlPlanePanel struct {
+	app      *application.Application
+	title    string
+	root     *tview.Flex
+	children []tview.Primitive
+	listCols []string
+	list     *tview.Table
+	laidout  bool
+}
+
+func NewControlPlanePanel(app *application.Application, title string) ui.Panel {
+	p := &controlPlanePanel{app: app, title: title}
+	p.Layout(nil)
+	return p
+}
+
+func (p *controlPlanePanel) GetTitle() string {
+	return p.title
+}
+
+func (p *controlPlanePanel) Layout(_ interface{}) {
+	if !p.laidout {
+		p.list = tview.NewTable()
+		p.list.SetFixed(1, 0)
+		p.list.SetBorder(false)
+		p.list.SetBorders(false)
+		p.list.SetFocusFunc(func() {
+			p.list.SetSelectable(true, false)
+			p.list.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorYellow).Foreground(tcell.ColorBlue))
+		})
+		p.list.SetBlurFunc(func() {
+			p.list.SetSelectable(false, false)
+		})
+
+		p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(p.list, 0, 1, true)
+		p.root.SetBorder(true)
+		p.root.SetTitle(p.GetTitle())
+		p.root.SetTitleAlign(tview.AlignLeft)
+		p.laidout = true
+	}
+}
+
+func (p *controlPlanePanel) DrawHeader(data interface{}) {
+	cols, ok := data.([]string)
+	if !ok {
+		panic(fmt.Sprintf("controlPlanePanel.DrawHeader got unexpected data type %T", data))
+	}
+
+	p.listCols = cols
+	for i, col := range p.listCols {
+		p.list.SetCell(0, i,
+			tview.NewTableCell(col).
+				SetTextColor(tcell.ColorBlack).
+				SetBackgroundColor(tcell.ColorDarkGray).
+				SetAlign(tview.AlignLeft).
+				SetExpansion(100).
+				SetSelectable(false),
+		)
+	}
+	p.list.SetFixed(1, 0)
+}
+
+// DrawBody lists each control plane component instance followed by
+// every named API server health check
+func (p *controlPlanePanel) DrawBody(data interface{}) {
+	cp, ok := data.(model.ControlPlaneModel)
+	if !ok {
+		panic(fmt.Sprintf("controlPlanePanel.DrawBody got unexpected type %T", data))
+	}
+
+	healthy, unhealthy := cp.Healthy()
+	p.root.SetTitle(fmt.Sprintf("%s(%d/%d) ", p.GetTitle(), healthy, healthy+unhealthy))
+	p.root.SetTitleAlign(tview.AlignLeft)
+
+	row := 1
+	for _, comp := range cp.Components {
+		p.drawRow(row, comp.Name, comp.Node, "ready", comp.Status, comp.Healthy)
+		row++
+	}
+
+	for _, check := range cp.Checks {
+		status := "ok"
+		if !check.Passed {
+			status = "failed"
+			if check.Message != "" {
+				status = check.Message
+			}
+		}
+		p.drawRow(row, "kube-apiserver", check.Endpoint, check.Name, status, check.Passed)
+		row++
+	}
+}
+
+func (p *controlPlanePanel) drawRow(row int, component, instance, check, status string, passed bool) {
+	statusColor := tcell.ColorDarkGreen
+	if !passed {
+		statusColor = tcell.ColorDarkRed
+	}
+
+	for col, text := range []string{component, instance, check} {
+		p.list.SetCell(
+			row, col,
+			&tview.TableCell{
+				Text:  text,
+				Color: tcell.ColorWhite,
+				Align: tview.AlignLeft,
+			},
+		)
+	}
+
+	p.list.SetCell(
+		row, 3,
+		&tview.TableCell{
+			Text:  status,
+			Color: statusColor,
+			Align: tview.AlignLeft,
+		},
+	)
+}
+
+func (p *controlPlanePanel) DrawFooter(_ interface{}) {}
+
+func (p *controlPlanePanel) Clear() {
+	p.list.Clear()
+	p.Layout(nil)
+	p.DrawHeader(p.listCols)
+}
+
+func (p *controlPlanePanel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+func (p *controlPlanePanel) GetChildrenViews() []tview.Primitive {
+	return p.children
+}
@@ -0,0 +1,104 @@
+package overview
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/pjy0381/ktop/ui"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// newDetailTable returns a bordered table for a section of a detail panel
+func newDetailTable(title string) *tview.Table {
+	table := tview.NewTable()
+	table.SetFixed(1, 0)
+	table.SetBorder(true)
+	table.SetBorders(false)
+	table.SetTitle(title)
+	table.SetTitleAlign(tview.AlignLeft)
+	table.SetFocusFunc(func() {
+		table.SetSelectable(true, false)
+		table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorYellow).Foreground(tcell.ColorBlue))
+	})
+	table.SetBlurFunc(func() {
+		table.SetSelectable(false, false)
+	})
+	return table
+}
+
+func drawDetailHeader(table *tview.Table, cols []string) {
+	for i, col := range cols {
+		table.SetCell(0, i,
+			tview.NewTableCell(col).
+				SetTextColor(tcell.ColorBlack).
+				SetBackgroundColor(tcell.ColorDarkGray).
+				SetAlign(tview.AlignLeft).
+				SetExpansion(100).
+				SetSelectable(false),
+		)
+	}
+}
+
+func drawDetailRow(table *tview.Table, row int, color tcell.Color, cells ...string) {
+	for col, text := range cells {
+		table.SetCell(
+			row, col,
+			&tview.TableCell{
+				Text:  text,
+				Color: color,
+				Align: tview.AlignLeft,
+			},
+		)
+	}
+}
+
+// clearDetailTable removes all rows but the header
+func clearDetailTable(table *tview.Table) {
+	for table.GetRowCount() > 1 {
+		table.RemoveRow(table.GetRowCount() - 1)
+	}
+}
+
+func cpuText(qty *resource.Quantity) string {
+	if qty == nil || qty.IsZero() {
+		return "-"
+	}
+	return fmt.Sprintf("%dm", qty.MilliValue())
+}
+
+func memText(qty *resource.Quantity) string {
+	if qty == nil || qty.IsZero() {
+		return "-"
+	}
+	return fmt.Sprintf("%dMi", qty.ScaledValue(resource.Mega))
+}
+
+// storageText returns a storage quantity as written in manifests, such as 10Gi
+func storageText(qty *resource.Quantity) string {
+	if qty == nil || qty.IsZero() {
+		return "-"
+	}
+	return qty.String()
+}
+
+func timeSinceText(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return duration.HumanDuration(time.Since(t))
+}
+
+// sparklineWidth is the number of characters of the usage history sparklines
+const sparklineWidth = 12
+
+// historyText returns values drawn as a sparkline scaled to max, preceded by
+// a space, or nothing without history
+func historyText(values []float64, max float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return " [aqua]" + ui.Sparkline(values, sparklineWidth, max) + "[white]"
+}
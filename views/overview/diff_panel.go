@@ -0,0 +1,107 @@
+package overview
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/ui"
+	"github.com/pjy0381/ktop/views/model"
+)
+
+// diffPanel lists the objects added (green), removed (red) and changed
+// (yellow) between two snapshots, with a row per changed field
+type diffPanel struct {
+	app      *application.Application
+	title    string
+	root     *tview.Flex
+	children []tview.Primitive
+	list     *tview.Table
+	laidout  bool
+}
+
+func NewDiffPanel(app *application.Application, title string) ui.Panel {
+	p := &diffPanel{app: app, title: title}
+	p.Layout(nil)
+	p.DrawHeader(nil)
+	return p
+}
+
+func (p *diffPanel) GetTitle() string {
+	return p.title
+}
+
+func (p *diffPanel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.list = newDetailTable("")
+	p.list.SetBorder(false)
+	p.children = []tview.Primitive{p.list}
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.list, 0, 1, true)
+	p.root.SetBorder(true)
+	p.root.SetTitle(p.GetTitle())
+	p.root.SetTitleAlign(tview.AlignLeft)
+	p.laidout = true
+}
+
+func (p *diffPanel) DrawHeader(_ interface{}) {
+	drawDetailHeader(p.list, []string{"CHANGE", "KIND", "NAMESPACE", "NAME", "FIELD", "FROM", "TO", "DELTA"})
+}
+
+func (p *diffPanel) DrawBody(data interface{}) {
+	diff, ok := data.(model.SnapshotDiff)
+	if !ok {
+		panic(fmt.Sprintf("diffPanel.DrawBody got unexpected type %T", data))
+	}
+
+	row := 1
+	for _, change := range diff.Changes {
+		color := tcell.ColorYellow
+		switch change.Change {
+		case model.ChangeAdded:
+			color = tcell.ColorGreen
+		case model.ChangeRemoved:
+			color = tcell.ColorRed
+		}
+		object := []string{change.Change, change.Kind, model.OrDash(change.Namespace), change.Name}
+		if len(change.Fields) == 0 {
+			drawDetailRow(p.list, row, color, append(object, "-", "-", "-", "-")...)
+			row++
+			continue
+		}
+		for i, field := range change.Fields {
+			// the object is only named on the row of its first field
+			if i > 0 {
+				object = []string{"", "", "", ""}
+			}
+			drawDetailRow(p.list, row, color, append(object, field.Field, model.OrDash(field.From), model.OrDash(field.To), model.OrDash(field.Delta))...)
+			row++
+		}
+	}
+
+	p.root.SetTitle(fmt.Sprintf("%s%s (%s) → %s (%s): [green]%d added[white], [red]%d removed[white], [yellow]%d changed[white] ",
+		p.title, diff.From, diff.FromTime.Local().Format("15:04:05"), diff.To, diff.ToTime.Local().Format("15:04:05"),
+		diff.Added, diff.Removed, diff.Changed,
+	))
+}
+
+func (p *diffPanel) DrawFooter(_ interface{}) {}
+
+func (p *diffPanel) Clear() {
+	p.list.Clear()
+	p.DrawHeader(nil)
+	p.root.SetTitle(p.title)
+}
+
+func (p *diffPanel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+func (p *diffPanel) GetChildrenViews() []tview.Primitive {
+	return p.children
+}
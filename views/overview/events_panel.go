@@ -0,0 +1,147 @@
+package overview
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/ui"
+	"github.com/pjy0381/ktop/views/model"
+)
+
+// eventsPanelLimit is the number of most recent matching events listed
+const eventsPanelLimit = 500
+
+// eventsPanel lists the cluster events most recent first, narrowed by a filter
+type eventsPanel struct {
+	app      *application.Application
+	title    string
+	root     *tview.Flex
+	children []tview.Primitive
+	listCols []string
+	list     *tview.Table
+	laidout  bool
+	filter   model.EventFilter
+	events   []model.EventModel
+}
+
+func NewEventsPanel(app *application.Application, title string) ui.Panel {
+	p := &eventsPanel{app: app, title: title}
+	p.Layout(nil)
+	return p
+}
+
+func (p *eventsPanel) GetTitle() string {
+	return p.title
+}
+
+func (p *eventsPanel) Layout(_ interface{}) {
+	if !p.laidout {
+		p.list = tview.NewTable()
+		p.list.SetFixed(1, 0)
+		p.list.SetBorder(false)
+		p.list.SetBorders(false)
+		p.list.SetFocusFunc(func() {
+			p.list.SetSelectable(true, false)
+			p.list.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorYellow).Foreground(tcell.ColorBlue))
+		})
+		p.list.SetBlurFunc(func() {
+			p.list.SetSelectable(false, false)
+		})
+
+		p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(p.list, 0, 1, true)
+		p.root.SetBorder(true)
+		p.root.SetTitle(p.GetTitle())
+		p.root.SetTitleAlign(tview.AlignLeft)
+		p.laidout = true
+	}
+}
+
+func (p *eventsPanel) DrawHeader(data interface{}) {
+	cols, ok := data.([]string)
+	if !ok {
+		panic(fmt.Sprintf("eventsPanel.DrawHeader got unexpected data type %T", data))
+	}
+
+	p.listCols = cols
+	for i, col := range p.listCols {
+		p.list.SetCell(0, i,
+			tview.NewTableCell(col).
+				SetTextColor(tcell.ColorBlack).
+				SetBackgroundColor(tcell.ColorDarkGray).
+				SetAlign(tview.AlignLeft).
+				SetExpansion(100).
+				SetSelectable(false),
+		)
+	}
+	p.list.SetFixed(1, 0)
+}
+
+// DrawBody lists the events matching the panel filter, warnings in yellow
+func (p *eventsPanel) DrawBody(data interface{}) {
+	events, ok := data.([]model.EventModel)
+	if !ok {
+		panic(fmt.Sprintf("eventsPanel.DrawBody got unexpected type %T", data))
+	}
+	p.events = events
+
+	matched := model.FilterEventModels(events, p.filter)
+	title := fmt.Sprintf("%s(%d of %d) ", p.GetTitle(), len(matched), len(events))
+	if !p.filter.IsEmpty() {
+		title = fmt.Sprintf("%s[%s] (%d of %d) ", p.GetTitle(), p.filter, len(matched), len(events))
+	}
+	p.root.SetTitle(title)
+
+	if len(matched) > eventsPanelLimit {
+		matched = matched[:eventsPanelLimit]
+	}
+	for i, event := range matched {
+		color := tcell.ColorWhite
+		if event.Type == model.EventTypeWarning {
+			color = tcell.ColorYellow
+		}
+		cells := []string{event.Type, event.Reason, event.Object, event.Namespace, fmt.Sprintf("%d", event.Count), event.Age, event.Message}
+		for col, text := range cells {
+			p.list.SetCell(
+				i+1, col,
+				&tview.TableCell{
+					Text:  text,
+					Color: color,
+					Align: tview.AlignLeft,
+				},
+			)
+		}
+	}
+}
+
+func (p *eventsPanel) DrawFooter(_ interface{}) {}
+
+func (p *eventsPanel) Clear() {
+	p.list.Clear()
+	p.Layout(nil)
+	p.DrawHeader(p.listCols)
+}
+
+func (p *eventsPanel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+func (p *eventsPanel) GetChildrenViews() []tview.Primitive {
+	return p.children
+}
+
+// Filter returns the filter of the listed events
+func (p *eventsPanel) Filter() model.EventFilter {
+	return p.filter
+}
+
+// SetFilter narrows the listed events to those matching filter, redrawing
+// the last events drawn
+func (p *eventsPanel) SetFilter(filter model.EventFilter) {
+	p.filter = filter
+	events := p.events
+	p.Clear()
+	p.DrawBody(events)
+}
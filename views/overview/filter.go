@@ -0,0 +1,36 @@
+package overview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/pjy0381/ktop/views/model"
+)
+
+// highlight returns text with the ranges of matches drawn black on yellow
+func highlight(text string, matches [][]int) string {
+	if len(matches) == 0 {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		b.WriteString(text[last:match[0]])
+		b.WriteString("[black:yellow]")
+		b.WriteString(text[match[0]:match[1]])
+		b.WriteString("[-:-]")
+		last = match[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// filteredCount returns the count of a panel title, as "shown of total" with
+// the filter text while a filter narrows the rows
+func filteredCount(shown, total int, filter *model.Filter) string {
+	if filter == nil {
+		return fmt.Sprintf("%d", total)
+	}
+	return fmt.Sprintf("%d of %d /%s", shown, total, tview.Escape(filter.String()))
+}
@@ -1,19 +1,25 @@
 package overview
 
 import (
-	"strconv"
 	"strings"
 	"context"
 	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/k8s"
+	"github.com/pjy0381/ktop/session"
+	"github.com/pjy0381/ktop/snapshot"
 	"github.com/pjy0381/ktop/ui"
 	"github.com/pjy0381/ktop/views/model"
 )
 
+// detailRefreshInterval is how often an open pod or node detail panel is refreshed
+const detailRefreshInterval = 5 * time.Second
+
 type MainPanel struct {
         commandInput *tview.InputField
 	app                 *application.Application
@@ -28,16 +34,53 @@ type MainPanel struct {
 
 	nodePanelVisible    bool
 	podPanelVisible     bool
-	savePodPanelVisible bool
-	savePodPanel	    ui.Panel
-	lessPanel	    ui.Panel
-	lessVisible	    bool
+	snapshotPanel       ui.Panel
+	snapshotVisible     bool
+	diffPanel           ui.Panel
+	diffVisible         bool
+	controlPlanePanel   ui.Panel
+	controlPlaneVisible bool
+	contextPanel        ui.Panel
+	contextVisible      bool
+	podDetailPanel      ui.Panel
+	podDetailVisible    bool
+	podDetailCancel     context.CancelFunc
+	nodeDetailPanel     ui.Panel
+	nodeDetailVisible   bool
+	nodeDetailCancel    context.CancelFunc
+	podLogPanel         ui.Panel
+	podLogVisible       bool
+	eventsPanel         ui.Panel
+	eventsVisible       bool
+	workloadPanels      []ui.Panel // by model.WorkloadKinds
+	workloadVisible     []bool
+	storagePanel        ui.Panel
+	storageVisible      bool
+	detailPod           model.PodModel
+	detailNode          string
 
-	sortPodBy	    int
-	sortNodeBy	    int
+	sortPodBy	    model.SortOrder
+	sortNodeBy	    model.SortOrder
+	onSortChanged       func(pods, nodes model.SortOrder) error
+	podColumns          []string // ids, the defaults when nil
+	nodeColumns         []string
 	currentPodModels    []model.PodModel
 	currentNodeModels   []model.NodeModel
-	savePodModels	    []model.PodModel
+
+	// snapshots saved with s, in saving order. The diff panel compares
+	// snapshot diffFrom to snapshot diffTo, or to the cluster when diffTo is "".
+	snapshots    []*model.Snapshot
+	snapshotSeq  int
+	snapshotDir  string // where snapshots are also saved, when set
+	diffFrom     string
+	diffTo       string
+
+	ctx                 context.Context
+	ctrl                *k8s.Controller
+
+	// replays draw the models of a recorded session instead of a controller
+	player       *session.Player
+	replayStatus *tview.TextView
 
 }
 
@@ -57,7 +100,7 @@ func (p *MainPanel) Layout(data interface{}) {
 	p.initializePanels()
 
 	view := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(p.clusterSummaryPanel.GetRootView(), 4, 1, false).
+		AddItem(p.clusterSummaryPanel.GetRootView(), 5, 1, false).
 		AddItem(p.commandInput, 1, 1, false)
 
 	p.root = view
@@ -66,203 +109,855 @@ func (p *MainPanel) Layout(data interface{}) {
 func (p *MainPanel) initializeInputField() {
 	p.commandInput = tview.NewInputField()
 	p.commandInput.SetInputCapture(p.handleInput)
+	// text starting with / filters the pods and nodes as it is typed
+	p.commandInput.SetChangedFunc(func(text string) {
+		if strings.HasPrefix(text, "/") {
+			p.setFilter(text[1:])
+		}
+	})
 	p.children = append(p.children, p.commandInput)
 }
 
 func (p *MainPanel) initializePanels() {
-	p.nodePanel = NewNodePanel(p.app, fmt.Sprintf(" %c Nodes ", ui.Icons.Factory))
-	p.nodePanel.DrawHeader([]string{"NAME", "STATUS", "AGE",  "INT/EXT IPs",  "PODS/IMGs", "Kubelet", "Containerd", "Scini", "CPU", "MEM"})
+	services := p.app.GetK8sClient().Controller().HostServices()
+	p.nodePanel = NewNodePanel(p.app, fmt.Sprintf(" %c Nodes ", ui.Icons.Factory), services)
+	if p.nodeColumns == nil {
+		p.nodeColumns = defaultNodeColumns(services)
+	}
+	p.nodePanel.DrawHeader(p.nodeColumns)
+	p.nodePanel.(*nodePanel).SetSelectedFunc(p.openNodeDetail)
+	p.nodePanel.(*nodePanel).SetSortFunc(func(key string) { p.sortNodes(key) })
+	p.nodePanel.(*nodePanel).SetSort(p.sortNodeBy)
+	p.nodeDetailPanel = NewNodeDetailPanel(p.app, fmt.Sprintf(" %c Node ", ui.Icons.Factory), p.closeNodeDetail, p.openPodDetail)
 
 	p.clusterSummaryPanel = NewClusterSummaryPanel(p.app, fmt.Sprintf(" %c Cluster Summary ", ui.Icons.Thermometer))
 	p.clusterSummaryPanel.Layout(nil)
 	p.clusterSummaryPanel.DrawHeader(nil)
 
 	p.podPanel = NewPodPanel(p.app, fmt.Sprintf(" %c Pods ", ui.Icons.Package))
-	p.podPanel.DrawHeader([]string{"NAMESPACE", "NODE", "POD", "READY", "STATUS", "RESTARTS", "AGE", "VOLS", "IP", "CPU", "MEMORY"})
+	if p.podColumns == nil {
+		p.podColumns = defaultPodColumns
+	}
+	p.podPanel.DrawHeader(p.podColumns)
+	p.podPanel.(*podPanel).SetSelectedFunc(p.openPodDetail)
+	p.podPanel.(*podPanel).SetSortFunc(func(key string) { p.sortPods(key) })
+	p.podPanel.(*podPanel).SetSort(p.sortPodBy)
+	p.podPanel.(*podPanel).SetLogsFunc(p.openPodLogs)
+	p.podDetailPanel = NewPodDetailPanel(p.app, fmt.Sprintf(" %c Pod ", ui.Icons.Package), p.closePodDetail, p.openPodLogs)
+	p.podLogPanel = NewPodLogPanel(p.app, fmt.Sprintf(" %c Logs ", ui.Icons.Package), p.closePodLogs)
 
-	p.savePodPanel = NewPodPanel(p.app, fmt.Sprintf(" %c SavePods ", ui.Icons.Package))
-        p.savePodPanel.DrawHeader([]string{"NAMESPACE", "POD", "READY", "STATUS", "RESTARTS", "AGE", "VOLS", "IP", "NODE", "CPU", "MEMORY"})
+	p.snapshotPanel = NewSnapshotPanel(p.app, fmt.Sprintf(" %c Snapshots ", ui.Icons.Package))
+	p.snapshotPanel.(*snapshotPanel).pods.SetColumns(p.podColumns)
+	p.diffPanel = NewDiffPanel(p.app, fmt.Sprintf(" %c Diff ", ui.Icons.Package))
 
-	p.lessPanel = NewPodPanel(p.app, fmt.Sprintf(" %c LeesPods ", ui.Icons.Package))
-        p.lessPanel.DrawHeader([]string{"NAMESPACE", "NODE", "POD"})
+	p.controlPlanePanel = NewControlPlanePanel(p.app, fmt.Sprintf(" %c Control Plane ", ui.Icons.Controller))
+	p.controlPlanePanel.DrawHeader([]string{"COMPONENT", "INSTANCE", "CHECK", "STATUS"})
+
+	for _, kind := range model.WorkloadKinds {
+		panel := NewWorkloadPanel(p.app, fmt.Sprintf(" %c %ss ", ui.Icons.Package, kind), kind)
+		panel.DrawHeader(workloadColumns(kind))
+		p.workloadPanels = append(p.workloadPanels, panel)
+		p.workloadVisible = append(p.workloadVisible, false)
+	}
+
+	p.storagePanel = NewStoragePanel(p.app, fmt.Sprintf(" %c Storage ", ui.Icons.Drum))
+
+	p.eventsPanel = NewEventsPanel(p.app, fmt.Sprintf(" %c Events ", ui.Icons.TrafficLight))
+	p.eventsPanel.DrawHeader([]string{"TYPE", "REASON", "OBJECT", "NAMESPACE", "COUNT", "AGE", "MESSAGE"})
+
+	p.contextPanel = NewContextPanel(p.app, fmt.Sprintf(" %c Contexts ", ui.Icons.Anchor), p.pickContext)
+	p.contextPanel.DrawHeader([]string{"CONTEXT", "CLUSTER", "USER", "NAMESPACE"})
 }
 
-func CopyPodPanel(newPanel *podPanel, newPodsSize int) *podPanel {
-    location, err := time.LoadLocation("Asia/Seoul")
-    if err != nil {
-		fmt.Println("타임존 로드 오류:", err)
-		return nil
-    }
-    currentTime := time.Now().In(location)
-    formattedTime := currentTime.Format("2006-01-02 15:04:05")
-
-    copiedPanel := &podPanel{
-        app:      newPanel.app,
-        title:    fmt.Sprintf(" %c SavePods (%d) %s ", ui.Icons.Package, newPodsSize, formattedTime),
-        root:     tview.NewFlex().SetDirection(tview.FlexRow),
-        children: []tview.Primitive{},
-        listCols: newPanel.listCols,
-        list:     tview.NewTable(),
-        laidout:  false,
+// handleInput runs the command typed on Enter. Esc drops what was typed,
+// clearing the filter typed after /.
+func (p *MainPanel) handleInput(event *tcell.EventKey) *tcell.EventKey {
+    if event.Key() == tcell.KeyEscape {
+	if strings.HasPrefix(p.commandInput.GetText(), "/") {
+	    p.setFilter("")
+	}
+	p.commandInput.SetText("")
+	return nil
     }
-    copiedPanel.Layout(nil)
-
-    // Copy the contents of the source list to the new list
-    for row := 0; row < newPanel.list.GetRowCount(); row++ {
-        for col := 0; col < newPanel.list.GetColumnCount(); col++ {
-            cell := newPanel.list.GetCell(row, col)
-            copiedPanel.list.SetCell(row, col, &tview.TableCell{
-                Text:    cell.Text,
-                Color:   cell.Color,
-                Align:   cell.Align,
-            })
-        }
+    if event.Key() == tcell.KeyEnter {
+        inputText := p.commandInput.GetText()
+	// the filter was applied while typed, Enter keeps it
+	if strings.HasPrefix(inputText, "/") {
+	    p.commandInput.SetText("")
+	    return event
+	}
+	p.runCommand(inputText)
+        p.commandInput.SetText("")
     }
+    return event
+}
 
-    // Copy the header cells with proper SetExpansion
-    for i, col := range copiedPanel.listCols {
-        copiedPanel.list.SetCell(0, i,
-            tview.NewTableCell(col).
-                SetTextColor(tcell.ColorWhite).
-                SetBackgroundColor(tcell.ColorDarkGreen).
-                SetAlign(tview.AlignLeft).
-                SetExpansion(1). // SetExpansion to 1 for each header cell
-                SetSelectable(false),
-        )
-    }
+// handleColumnsCommand shows the columns of the pod and node lists, or with
+// "pods" or "nodes" the columns that list can draw, followed by arguments
+// changing its columns, see model.ApplyColumns
+func (p *MainPanel) handleColumnsCommand(args []string) {
+	pods, nodes := p.podPanel.(*podPanel), p.nodePanel.(*nodePanel)
+	if len(args) == 0 {
+		p.commandInput.SetPlaceholder(fmt.Sprintf("pods: %s  nodes: %s",
+			strings.Join(pods.Columns(), ","), strings.Join(nodes.Columns(), ",")))
+		return
+	}
+
+	var known, defaults []string
+	switch args[0] {
+	case "pods":
+		known, defaults = podColumnIDs(), defaultPodColumns
+	case "nodes":
+		known, defaults = nodes.ColumnIDs(), defaultNodeColumns(nodes.services)
+	default:
+		p.commandInput.SetPlaceholder(fmt.Sprintf("cols: unknown list %q, expecting pods or nodes", args[0]))
+		return
+	}
+	if len(args) == 1 {
+		p.commandInput.SetPlaceholder(fmt.Sprintf("%s columns: %s", args[0], strings.Join(known, ",")))
+		return
+	}
+
+	if args[0] == "pods" {
+		columns, err := model.ApplyColumns(pods.Columns(), defaults, known, args[1:])
+		if err != nil {
+			p.commandInput.SetPlaceholder(fmt.Sprintf("cols: %s", err))
+			return
+		}
+		p.setPodColumns(columns)
+		return
+	}
+	columns, err := model.ApplyColumns(nodes.Columns(), defaults, known, args[1:])
+	if err != nil {
+		p.commandInput.SetPlaceholder(fmt.Sprintf("cols: %s", err))
+		return
+	}
+	p.nodeColumns = columns
+	nodes.SetColumns(columns)
+}
 
-    return copiedPanel
+// setPodColumns draws the columns of ids in the pod list and the pods of snapshots
+func (p *MainPanel) setPodColumns(ids []string) {
+	p.podColumns = ids
+	p.podPanel.(*podPanel).SetColumns(ids)
+	p.snapshotPanel.(*snapshotPanel).pods.SetColumns(ids)
 }
 
-func addDataBasedOnSavePanel(newPanel, savePanel, copiedPanel *podPanel, textColor tcell.Color) {
-    if newPanel == nil || savePanel == nil || copiedPanel == nil {
-        return
-    }
+// SetColumns sets the column ids of the pod and node lists, such as from the
+// config file, an empty list keeping the default columns. It must be called
+// before the panel is laid out.
+func (p *MainPanel) SetColumns(pods, nodes []string) error {
+	if len(pods) > 0 {
+		columns, err := model.ApplyColumns(defaultPodColumns, defaultPodColumns, podColumnIDs(), []string{strings.Join(pods, ",")})
+		if err != nil {
+			return fmt.Errorf("pod columns: %w", err)
+		}
+		p.podColumns = columns
+	}
+	if len(nodes) > 0 {
+		services := p.app.GetK8sClient().Controller().HostServices()
+		defaults := defaultNodeColumns(services)
+		columns, err := model.ApplyColumns(defaults, defaults, nodeColumnIDs(services), []string{strings.Join(nodes, ",")})
+		if err != nil {
+			return fmt.Errorf("node columns: %w", err)
+		}
+		p.nodeColumns = columns
+	}
+	return nil
+}
 
-    copiedPanelRowCount := copiedPanel.list.GetRowCount() - 1
+// setFilter narrows the pod and node lists to the rows matching text, kept
+// across refreshes until cleared with an empty text. See model.Filter.
+func (p *MainPanel) setFilter(text string) {
+	filter := model.NewFilter(text)
+	p.podPanel.(*podPanel).SetFilter(filter)
+	p.nodePanel.(*nodePanel).SetFilter(filter)
+	placeholder := ""
+	if filter != nil {
+		placeholder = fmt.Sprintf("filter: %s (/ and Enter to clear)", filter)
+	}
+	p.commandInput.SetPlaceholder(placeholder)
+}
 
-    for row := 1; row < newPanel.list.GetRowCount(); row++ {
-        newPanelCell := newPanel.list.GetCell(row, 2).Text
-        found := false
+// switchNamespace restarts the controller on namespace ("" for all) in the
+// background since the restart waits for the refresh loops, which draw the screen.
+// The pod and node lists are cleared and a loading state is shown until the new caches sync.
+func (p *MainPanel) switchNamespace(namespace string) {
+	label := namespace
+	if label == "" {
+		label = "all namespaces"
+	}
+	p.clearClusterViews()
+	p.commandInput.SetPlaceholder(fmt.Sprintf("loading %s...", label))
 
-	//data check
-        for col := 0; col < savePanel.list.GetRowCount(); col++ {
-            if savePanel.list.GetCell(col, 2).Text == newPanelCell {
-                found = true
-                break
-            }
-        }
+	go func() {
+		placeholder := ""
+		if err := p.app.GetK8sClient().Controller().Restart(namespace); err != nil {
+			placeholder = fmt.Sprintf("namespace switch failed: %s", err)
+		}
+		p.app.QueueUpdateDraw(func() {
+			p.commandInput.SetPlaceholder(placeholder)
+		})
+	}()
+}
 
-	// 데이터 추가
-        if !found {
-            copiedPanelRowCount++
-            for col := 0; col < 3; col++ {
-                cell := newPanel.list.GetCell(row, col)
-                color := cell.Color
-                if col == 2 {
-                    color = textColor
-                }
-                copiedPanel.list.SetCell(copiedPanelRowCount, col, &tview.TableCell{
-                    Text:  cell.Text,
-                    Color: color,
-                    Align: cell.Align,
-                })
-            }
-        }
-    }
+// switchSelectors restarts the controller in the background, like
+// switchNamespace, watching the pods and nodes selected by args,
+// or all of them without args. See k8s.ParseSelectorArgs.
+func (p *MainPanel) switchSelectors(args []string) {
+	sel, err := k8s.ParseSelectorArgs(args)
+	if err != nil {
+		p.commandInput.SetPlaceholder(fmt.Sprintf("sel: %s", err))
+		return
+	}
+	label := sel.String()
+	if sel.Empty() {
+		label = "all objects"
+	}
+	p.clearClusterViews()
+	p.commandInput.SetPlaceholder(fmt.Sprintf("loading %s...", label))
+
+	go func() {
+		placeholder := ""
+		ctrl := p.app.GetK8sClient().Controller()
+		if err := ctrl.RestartSelecting(sel); err != nil {
+			placeholder = fmt.Sprintf("selector switch failed: %s", err)
+			// rejected selectors leave the controller running, its lists to be drawn again
+			ctrl.Resync()
+		}
+		p.app.QueueUpdateDraw(func() {
+			p.commandInput.SetPlaceholder(placeholder)
+		})
+	}()
 }
 
-func LessPods(savePanel *podPanel, newPanel *podPanel, copiedPanel *podPanel) *podPanel {
-    addDataBasedOnSavePanel(newPanel, savePanel, copiedPanel, tcell.ColorGreen)
-    addDataBasedOnSavePanel(savePanel, newPanel, copiedPanel, tcell.ColorRed)
+// openPodDetail describes pod in the pod detail panel, refreshing it until closed
+func (p *MainPanel) openPodDetail(pod model.PodModel) {
+	if p.needsLive("pod details") {
+		return
+	}
+	p.closePodDetail()
+
+	p.detailPod = pod
+	ctx, cancel := context.WithCancel(p.ctx)
+	p.podDetailCancel = cancel
+	p.podDetailPanel.Clear()
+	p.togglePanel(&p.podDetailPanel, &p.podDetailVisible)
+	p.app.Focus(p.podDetailPanel.GetRootView())
 
-    return copiedPanel
+	ctrl := p.ctrl
+	p.refreshDetail(ctx, p.podDetailPanel, func(ctx context.Context) (interface{}, error) {
+		detail, err := ctrl.GetPodDetailModel(ctx, pod.Namespace, pod.Name)
+		if err != nil {
+			return nil, err
+		}
+		return *detail, nil
+	})
 }
 
-func (p *MainPanel) handleInput(event *tcell.EventKey) *tcell.EventKey {
-    if event.Key() == tcell.KeyEnter {
-        inputText := p.commandInput.GetText()
-	commandText := strings.Split(inputText, " ")
-        switch commandText[0] {
-        case "q":
-            p.app.Stop()
-        case "n":
-            // 정 렬  기 준  부 여
-            sortBy := parseSortValue(commandText)
-            p.sortNodeBy = sortBy
-            // 재 정 렬
-            model.SortNodeModelsByField(p.currentNodeModels, p.sortNodeBy)
-            p.refreshNodeView(context.Background(), p.currentNodeModels)
-            // view on/off
-	    if len(commandText) == 1 || !p.nodePanelVisible {
-		p.togglePanel(&p.nodePanel, &p.nodePanelVisible)
-	    }
-        case "p":
-	    // 정렬 기준 부여
-	    sortBy := parseSortValue(commandText)
-	    p.sortPodBy = sortBy
-
-	    // 재정렬
-	    model.SortPodModelsByField(p.currentPodModels, p.sortPodBy)
-	    p.refreshPods(context.Background(), p.currentPodModels)
-
-	    // view on/off
-            if len(commandText) == 1 || !p.podPanelVisible {
-		p.togglePanel(&p.podPanel, &p.podPanelVisible)
-	    }
-        case "s":
-	    p.savePodModels = p.currentPodModels
-	    p.savePodPanel = CopyPodPanel(p.podPanel.(*podPanel), len(p.currentPodModels))
-	case "u":
-            p.togglePanel(&p.savePodPanel, &p.savePodPanelVisible)
-	case "v":
-	    if !p.lessVisible {
-		p.lessPanel = LessPods(p.savePodPanel.(*podPanel), p.podPanel.(*podPanel), p.lessPanel.(*podPanel))
-	    }
-	    p.lessPanel.DrawHeader([]string{"NAMESPACE", "Node", "Pod"})
-	    p.togglePanel(&p.lessPanel, &p.lessVisible)
-	case "c":
-	    if p.nodePanelVisible {
-		p.togglePanel(&p.nodePanel, &p.nodePanelVisible)
-	    }
-	    if p.podPanelVisible {
-		p.togglePanel(&p.podPanel, &p.podPanelVisible)
-	    }
-            if p.savePodPanelVisible {
-                p.togglePanel(&p.savePodPanel, &p.savePodPanelVisible)
-            }
-            if p.lessVisible {
-                p.togglePanel(&p.lessPanel, &p.lessVisible)
-            }
-	case "-n":
-	    if len(commandText) > 1 {
- 	        p.app.GetK8sClient().NewNamespace(commandText[1])
-	    }
-            ctrl := p.app.GetK8sClient().Controller()
-            if err := ctrl.Start(context.Background(), time.Second*1); err != nil {
-                panic(fmt.Sprintf("main panel: controller start: %s", err))
-            }
-	case "-A":
-	    p.app.GetK8sClient().NewNamespace("")
-            ctrl := p.app.GetK8sClient().Controller()
-            if err := ctrl.Start(context.Background(), time.Second*1); err != nil {
-                panic(fmt.Sprintf("main panel: controller start: %s", err))
-            }
+// closePodDetail closes the pod detail panel, returning the focus to the
+// node detail panel when the pod was opened from it
+func (p *MainPanel) closePodDetail() {
+	if p.podDetailCancel != nil {
+		p.podDetailCancel()
+		p.podDetailCancel = nil
+	}
+	if p.podDetailVisible {
+		p.togglePanel(&p.podDetailPanel, &p.podDetailVisible)
+		if p.nodeDetailVisible {
+			p.app.Focus(p.nodeDetailPanel.GetRootView())
+		} else {
+			p.app.Focus(p.commandInput)
+		}
 	}
-        p.commandInput.SetText("")
-    }
-    return event
 }
 
-func parseSortValue(commandText []string) int {
-    if len(commandText) > 1 {
-        if sortBy, err := strconv.Atoi(commandText[1]); err == nil {
-            return sortBy
-        }
-    }
-    return 0
+// saveSnapshot saves the displayed pods and nodes, with the workloads of the
+// cluster, as snapshot args[0] or as the next snapshot number. A snapshot
+// replaces the one of the same name.
+func (p *MainPanel) saveSnapshot(args []string) {
+	var name string
+	if len(args) > 0 && args[0] != "" {
+		name = args[0]
+	} else {
+		p.snapshotSeq++
+		name = fmt.Sprintf("%d", p.snapshotSeq)
+	}
+
+	snap := p.currentSnapshot(p.ctx, name)
+	p.addSnapshot(snap)
+	placeholder := fmt.Sprintf("saved snapshot %s: %d pods, %d nodes, %d workloads",
+		name, len(snap.Pods), len(snap.Nodes), len(snap.Workloads))
+	if p.snapshotDir != "" {
+		path := filepath.Join(p.snapshotDir, snapshot.FileName(snap))
+		if err := snapshot.Save(path, snap); err != nil {
+			placeholder = fmt.Sprintf("snapshot %s not saved to disk: %s", name, err)
+		} else {
+			placeholder += " to " + path
+		}
+	}
+	p.commandInput.SetPlaceholder(placeholder)
+}
+
+// addSnapshot adds snapshot in place of the one of the same name
+func (p *MainPanel) addSnapshot(snapshot *model.Snapshot) {
+	var snapshots []*model.Snapshot
+	for _, s := range p.snapshots {
+		if s.Name != snapshot.Name {
+			snapshots = append(snapshots, s)
+		}
+	}
+	p.snapshots = append(snapshots, snapshot)
+
+	p.snapshotPanel.Clear()
+	p.snapshotPanel.DrawBody(p.snapshots)
+}
+
+// SetSnapshotDir makes the snapshots saved with s also be written to files in dir
+func (p *MainPanel) SetSnapshotDir(dir string) {
+	p.snapshotDir = dir
+}
+
+// exportSnapshot writes snapshot args[0], or the last one, to file args[1],
+// or to a file named after the snapshot in the current directory
+func (p *MainPanel) exportSnapshot(args []string) {
+	if len(p.snapshots) == 0 {
+		p.commandInput.SetPlaceholder("no snapshot to export, save one with s [name]")
+		return
+	}
+	snap := p.snapshots[len(p.snapshots)-1]
+	if len(args) > 0 {
+		if snap = p.snapshot(args[0]); snap == nil {
+			p.commandInput.SetPlaceholder(fmt.Sprintf("unknown snapshot %q", args[0]))
+			return
+		}
+	}
+	path := snapshot.FileName(snap)
+	if len(args) > 1 {
+		path = args[1]
+	}
+	if err := snapshot.Save(path, snap); err != nil {
+		p.commandInput.SetPlaceholder(fmt.Sprintf("export failed: %s", err))
+		return
+	}
+	p.commandInput.SetPlaceholder(fmt.Sprintf("exported snapshot %s to %s", snap.Name, path))
+}
+
+// loadSnapshot adds the snapshot of file args[0], in place of a snapshot of the same name
+func (p *MainPanel) loadSnapshot(args []string) {
+	if len(args) == 0 {
+		p.commandInput.SetPlaceholder("usage: load <file>")
+		return
+	}
+	snap, err := snapshot.Load(args[0])
+	if err != nil {
+		p.commandInput.SetPlaceholder(fmt.Sprintf("load failed: %s", err))
+		return
+	}
+	p.addSnapshot(snap)
+	p.commandInput.SetPlaceholder(fmt.Sprintf("loaded snapshot %s of %s taken %s",
+		snap.Name, snap.Context, snap.Time.Local().Format("2006-01-02 15:04:05")))
+}
+
+// currentSnapshot returns the displayed pods and nodes with the workloads of
+// the cluster, at the time of the replayed frame in replays
+func (p *MainPanel) currentSnapshot(ctx context.Context, name string) *model.Snapshot {
+	var snap *model.Snapshot
+	if p.replaying() {
+		snap = model.NewSnapshot(name, p.player.State().Time, p.currentPodModels, p.currentNodeModels, nil)
+	} else {
+		workloads, _ := p.ctrl.GetWorkloadModels(ctx)
+		snap = model.NewSnapshot(name, time.Now(), p.currentPodModels, p.currentNodeModels, workloads)
+	}
+	client := p.app.GetK8sClient()
+	snap.Context = client.ClusterContext()
+	snap.Namespaces = client.Namespaces()
+	return snap
+}
+
+// snapshot returns the snapshot name, or nil
+func (p *MainPanel) snapshot(name string) *model.Snapshot {
+	for _, s := range p.snapshots {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// handleSnapshotsCommand toggles the snapshots panel, or with args shows the
+// pods of snapshot args[0]
+func (p *MainPanel) handleSnapshotsCommand(args []string) {
+	if len(args) == 0 {
+		p.togglePanel(&p.snapshotPanel, &p.snapshotVisible)
+		return
+	}
+	if !p.snapshotPanel.(*snapshotPanel).Show(args[0]) {
+		p.commandInput.SetPlaceholder(fmt.Sprintf("unknown snapshot %q", args[0]))
+		return
+	}
+	if !p.snapshotVisible {
+		p.togglePanel(&p.snapshotPanel, &p.snapshotVisible)
+	}
+}
+
+// handleDiffCommand toggles the diff of the last snapshot with the cluster.
+// With args it shows the diff of snapshot args[0] with the cluster, or with
+// snapshot args[1]. Diffs with the cluster follow its refreshes.
+func (p *MainPanel) handleDiffCommand(args []string) {
+	if len(args) == 0 && p.diffVisible {
+		p.togglePanel(&p.diffPanel, &p.diffVisible)
+		return
+	}
+	if len(p.snapshots) == 0 {
+		p.commandInput.SetPlaceholder("no snapshot to compare, save one with s [name]")
+		return
+	}
+
+	from, to := p.snapshots[len(p.snapshots)-1].Name, ""
+	if len(args) > 0 {
+		from = args[0]
+	}
+	if len(args) > 1 {
+		to = args[1]
+	}
+	for _, name := range []string{from, to} {
+		if name != "" && p.snapshot(name) == nil {
+			p.commandInput.SetPlaceholder(fmt.Sprintf("unknown snapshot %q", name))
+			return
+		}
+	}
+
+	p.diffFrom, p.diffTo = from, to
+	p.drawDiff(p.ctx)
+	if !p.diffVisible {
+		p.togglePanel(&p.diffPanel, &p.diffVisible)
+	}
+}
+
+// drawDiff draws the diff of snapshot diffFrom with snapshot diffTo or the cluster
+func (p *MainPanel) drawDiff(ctx context.Context) {
+	from := p.snapshot(p.diffFrom)
+	if from == nil {
+		return
+	}
+	to := p.snapshot(p.diffTo)
+	if to == nil {
+		to = p.currentSnapshot(ctx, "now")
+	}
+	p.diffPanel.Clear()
+	p.diffPanel.DrawBody(model.DiffSnapshots(from, to))
+}
+
+// handleEventsCommand toggles the events panel, or with args narrows its events:
+// "w" toggles warnings only, "ns <namespace>" keeps a namespace ("ns" alone
+// clears it), "sel" keeps the selected pod or node and "all" clears the filter.
+func (p *MainPanel) handleEventsCommand(args []string) {
+	if len(args) == 0 {
+		p.toggleEvents()
+		return
+	}
+
+	events := p.eventsPanel.(*eventsPanel)
+	filter := events.Filter()
+	switch args[0] {
+	case "w":
+		filter.WarningsOnly = !filter.WarningsOnly
+	case "ns":
+		filter.Namespace = ""
+		if len(args) > 1 {
+			filter.Namespace = args[1]
+		}
+	case "sel":
+		filter.Namespace, filter.Kind, filter.Name = p.selectedObject()
+	case "all":
+		filter = model.EventFilter{}
+	default:
+		p.commandInput.SetPlaceholder(fmt.Sprintf("unknown events filter %q", args[0]))
+		return
+	}
+	events.SetFilter(filter)
+	if !p.eventsVisible {
+		p.toggleEvents()
+	}
+}
+
+// toggleEvents shows or hides the events panel, which is only refreshed while visible
+func (p *MainPanel) toggleEvents() {
+	p.togglePanel(&p.eventsPanel, &p.eventsVisible)
+	if !p.eventsVisible {
+		p.ctrl.SetEventRefreshFunc(nil)
+		return
+	}
+	p.ctrl.SetEventRefreshFunc(p.refreshEvents)
+	if events, err := p.ctrl.GetEventModels(p.ctx); err == nil {
+		p.eventsPanel.Clear()
+		p.eventsPanel.DrawBody(events)
+	}
+}
+
+// selectedObject returns the pod or node whose events the "e sel" command
+// keeps: the one of an open detail panel, else the selected pod or node row
+func (p *MainPanel) selectedObject() (namespace, kind, name string) {
+	switch {
+	case p.podDetailVisible:
+		return p.detailPod.Namespace, "Pod", p.detailPod.Name
+	case p.nodeDetailVisible:
+		return "", "Node", p.detailNode
+	}
+	if p.podPanelVisible {
+		if pod, ok := p.podPanel.(*podPanel).Selected(); ok {
+			return pod.Namespace, "Pod", pod.Name
+		}
+	}
+	if p.nodePanelVisible {
+		if node, ok := p.nodePanel.(*nodePanel).Selected(); ok {
+			return "", "Node", node.Name
+		}
+	}
+	return "", "", ""
+}
+
+func (p *MainPanel) refreshEvents(ctx context.Context, events []model.EventModel) error {
+	p.eventsPanel.Clear()
+	p.eventsPanel.DrawBody(events)
+	if p.refresh != nil {
+		p.refresh()
+	}
+	return nil
+}
+
+// handleWorkloadCommand toggles the workload panel of the kind named by
+// args[0], such as deploy, sts, ds, job or cj
+func (p *MainPanel) handleWorkloadCommand(args []string) {
+	if len(args) == 0 {
+		p.commandInput.SetPlaceholder("usage: w deploy|sts|ds|job|cj")
+		return
+	}
+	kind, ok := model.ParseWorkloadKind(args[0])
+	if !ok {
+		p.commandInput.SetPlaceholder(fmt.Sprintf("unknown workload kind %q", args[0]))
+		return
+	}
+	for i, panelKind := range model.WorkloadKinds {
+		if panelKind == kind {
+			p.toggleWorkloads(i)
+		}
+	}
+}
+
+// toggleWorkloads shows or hides the workload panel i. Workloads are only
+// refreshed while a workload panel is visible, the first refresh happening in the background.
+func (p *MainPanel) toggleWorkloads(i int) {
+	p.togglePanel(&p.workloadPanels[i], &p.workloadVisible[i])
+	if !p.workloadsShown() {
+		p.ctrl.SetWorkloadRefreshFunc(nil)
+		return
+	}
+	p.ctrl.SetWorkloadRefreshFunc(p.refreshWorkloads)
+	if !p.workloadVisible[i] {
+		return
+	}
+	ctx, ctrl := p.ctx, p.ctrl
+	go func() {
+		if workloads, err := ctrl.GetWorkloadModels(ctx); err == nil {
+			p.refreshWorkloads(ctx, workloads)
+		}
+	}()
+}
+
+// toggleStorage shows or hides the storage panel, which is only refreshed
+// while visible, the first refresh happening in the background
+func (p *MainPanel) toggleStorage() {
+	p.togglePanel(&p.storagePanel, &p.storageVisible)
+	if !p.storageVisible {
+		p.ctrl.SetStorageRefreshFunc(nil)
+		return
+	}
+	p.ctrl.SetStorageRefreshFunc(p.refreshStorage)
+	ctx, ctrl := p.ctx, p.ctrl
+	go func() {
+		if storage, err := ctrl.GetStorageModel(ctx); err == nil {
+			p.refreshStorage(ctx, *storage)
+		}
+	}()
+}
+
+func (p *MainPanel) refreshStorage(ctx context.Context, storage model.StorageModel) error {
+	p.storagePanel.Clear()
+	p.storagePanel.DrawBody(storage)
+	if p.refresh != nil {
+		p.refresh()
+	}
+	return nil
+}
+
+func (p *MainPanel) workloadsShown() bool {
+	for _, visible := range p.workloadVisible {
+		if visible {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *MainPanel) refreshWorkloads(ctx context.Context, workloads []model.WorkloadModel) error {
+	for i, panel := range p.workloadPanels {
+		if !p.workloadVisible[i] {
+			continue
+		}
+		panel.Clear()
+		panel.DrawBody(workloads)
+	}
+	if p.refresh != nil {
+		p.refresh()
+	}
+	return nil
+}
+
+// openPodLogs streams the logs of pod in the log panel until closed
+func (p *MainPanel) openPodLogs(pod model.PodModel) {
+	if p.needsLive("pod logs") {
+		return
+	}
+	logPanel := p.podLogPanel.(*podLogPanel)
+	logPanel.Open(p.ctx, p.ctrl, pod)
+	if !p.podLogVisible {
+		p.togglePanel(&p.podLogPanel, &p.podLogVisible)
+	}
+	p.app.Focus(logPanel.GetRootView())
+}
+
+// closePodLogs stops the log stream and returns the focus to the pod detail
+// panel when the logs were opened from it
+func (p *MainPanel) closePodLogs() {
+	p.podLogPanel.(*podLogPanel).Close()
+	if !p.podLogVisible {
+		return
+	}
+	p.togglePanel(&p.podLogPanel, &p.podLogVisible)
+	if p.podDetailVisible {
+		p.app.Focus(p.podDetailPanel.GetRootView())
+	} else {
+		p.app.Focus(p.commandInput)
+	}
+}
+
+// openNodeDetail describes node in the node detail panel, refreshing it until closed
+func (p *MainPanel) openNodeDetail(node model.NodeModel) {
+	if p.needsLive("node details") {
+		return
+	}
+	p.closePodDetail()
+	p.closeNodeDetail()
+
+	p.detailNode = node.Name
+	ctx, cancel := context.WithCancel(p.ctx)
+	p.nodeDetailCancel = cancel
+	p.nodeDetailPanel.Clear()
+	p.togglePanel(&p.nodeDetailPanel, &p.nodeDetailVisible)
+	p.app.Focus(p.nodeDetailPanel.GetRootView())
+
+	ctrl := p.ctrl
+	p.refreshDetail(ctx, p.nodeDetailPanel, func(ctx context.Context) (interface{}, error) {
+		detail, err := ctrl.GetNodeDetailModel(ctx, node.Name)
+		if err != nil {
+			return nil, err
+		}
+		return *detail, nil
+	})
+}
+
+func (p *MainPanel) closeNodeDetail() {
+	if p.nodeDetailCancel != nil {
+		p.nodeDetailCancel()
+		p.nodeDetailCancel = nil
+	}
+	if p.nodeDetailVisible {
+		p.togglePanel(&p.nodeDetailPanel, &p.nodeDetailVisible)
+		p.app.Focus(p.commandInput)
+	}
 }
 
+// refreshDetail draws the model returned by load in panel every
+// detailRefreshInterval until ctx is cancelled. Failed loads keep the last drawing.
+func (p *MainPanel) refreshDetail(ctx context.Context, panel ui.Panel, load func(context.Context) (interface{}, error)) {
+	go func() {
+		ticker := time.NewTicker(detailRefreshInterval)
+		defer ticker.Stop()
+		for {
+			detail, err := load(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if err == nil {
+				panel.Clear()
+				panel.DrawBody(detail)
+				if p.refresh != nil {
+					p.refresh()
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// pickContext closes the context picker and switches to the picked context
+func (p *MainPanel) pickContext(name string) {
+	if p.contextVisible {
+		p.togglePanel(&p.contextPanel, &p.contextVisible)
+	}
+	p.app.Focus(p.commandInput)
+	p.switchContext(name)
+}
+
+// switchContext displays the cluster of the kubeconfig context name. The
+// connection happens in the background and the new controller is started
+// before the previous one is stopped, so a failed switch keeps the current
+// cluster. The new cluster is displayed from the event loop.
+func (p *MainPanel) switchContext(name string) {
+	current := p.app.GetK8sClient()
+	if name == current.ClusterContext() {
+		return
+	}
+	p.commandInput.SetPlaceholder(fmt.Sprintf("connecting to context %s...", name))
+
+	go func() {
+		client, err := p.connectContext(current, name)
+		if err != nil {
+			p.app.QueueUpdateDraw(func() {
+				p.commandInput.SetPlaceholder(fmt.Sprintf("context switch failed: %s", err))
+			})
+			return
+		}
+		shown := make(chan struct{})
+		p.app.QueueUpdateDraw(func() {
+			p.app.SetK8sClient(client)
+			p.clearSnapshots()
+			p.commandInput.SetPlaceholder("")
+			close(shown)
+		})
+		// the previous controller stops once the panels show the new one
+		select {
+		case <-shown:
+		case <-p.ctx.Done():
+			return
+		}
+		// clusters of the fleet keep running for the fleet summary
+		if p.fleetClient(current.ClusterContext()) != current {
+			current.Controller().Stop()
+		}
+	}()
+}
+
+// connectContext returns the client of context name, connected and with its
+// controller started, such as a cluster of the fleet
+func (p *MainPanel) connectContext(current *k8s.Client, name string) (*k8s.Client, error) {
+	if client := p.fleetClient(name); client != nil {
+		return client, nil
+	}
+	client, err := current.ForContext(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.AssertCoreAuthz(p.ctx); err != nil {
+		return nil, err
+	}
+	if err := client.Controller().Start(p.ctx, time.Second*1); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// fleetClient returns the client of the fleet cluster of context name, or nil
+func (p *MainPanel) fleetClient(name string) *k8s.Client {
+	fleet := p.app.GetFleet()
+	if fleet == nil {
+		return nil
+	}
+	if member := fleet.Member(name); member != nil {
+		return member.Client()
+	}
+	return nil
+}
+
+// clearSnapshots drops the snapshots, which belong to the previous cluster
+func (p *MainPanel) clearSnapshots() {
+	p.snapshots = nil
+	p.snapshotPanel.Clear()
+	p.diffPanel.Clear()
+	if p.diffVisible {
+		p.togglePanel(&p.diffPanel, &p.diffVisible)
+	}
+}
+
+// clearClusterViews drops the models of the displayed cluster until the
+// controller refreshes them
+func (p *MainPanel) clearClusterViews() {
+	p.currentPodModels = nil
+	p.currentNodeModels = nil
+	p.podPanel.Clear()
+	p.nodePanel.Clear()
+}
+
+// sortPods sorts the pods by arg, a sort key prefixed with - for a descending
+// order. It reports whether arg is valid.
+func (p *MainPanel) sortPods(arg string) bool {
+	order, err := model.ParsePodSortOrder(arg, p.sortPodBy)
+	if err != nil {
+		p.commandInput.SetPlaceholder(fmt.Sprintf("p: %s", err))
+		return false
+	}
+	p.sortPodBy = order
+	p.podPanel.(*podPanel).SetSort(order)
+	p.refreshPods(context.Background(), p.currentPodModels)
+	p.sortChanged()
+	return true
+}
+
+// sortNodes sorts the nodes by arg, like sortPods
+func (p *MainPanel) sortNodes(arg string) bool {
+	order, err := model.ParseNodeSortOrder(arg, p.sortNodeBy)
+	if err != nil {
+		p.commandInput.SetPlaceholder(fmt.Sprintf("n: %s", err))
+		return false
+	}
+	p.sortNodeBy = order
+	p.nodePanel.(*nodePanel).SetSort(order)
+	p.refreshNodeView(context.Background(), p.currentNodeModels)
+	p.sortChanged()
+	return true
+}
+
+func (p *MainPanel) sortChanged() {
+	if p.onSortChanged == nil {
+		return
+	}
+	if err := p.onSortChanged(p.sortPodBy, p.sortNodeBy); err != nil {
+		p.commandInput.SetPlaceholder(fmt.Sprintf("sort not saved: %s", err))
+	}
+}
+
+// SetSortOrders sorts the pods and nodes, such as by the orders of a previous
+// session. Before the panel is laid out, the orders are kept for its lists.
+func (p *MainPanel) SetSortOrders(pods, nodes model.SortOrder) {
+	p.sortPodBy, p.sortNodeBy = pods, nodes
+	if p.podPanel == nil {
+		return
+	}
+	p.podPanel.(*podPanel).SetSort(pods)
+	p.nodePanel.(*nodePanel).SetSort(nodes)
+}
+
+// SetSortChangedFunc sets the function called with the pod and node orders
+// when either is changed, such as to save them for the next session
+func (p *MainPanel) SetSortChangedFunc(fn func(pods, nodes model.SortOrder) error) {
+	p.onSortChanged = fn
+}
 
 func (p *MainPanel) togglePanel(panel *ui.Panel, visible *bool) {
     if *visible {
@@ -300,11 +995,18 @@ func (p *MainPanel) GetChildrenViews() []tview.Primitive {
 }
 
 func (p *MainPanel) Run(ctx context.Context) error {
+	p.ctx = ctx
+	if err := p.registerCommands(); err != nil {
+		return err
+	}
 	p.Layout(nil)
+	if p.replaying() {
+		p.runReplay(ctx)
+		return nil
+	}
 	ctrl := p.app.GetK8sClient().Controller()
-	ctrl.SetClusterSummaryRefreshFunc(p.refreshWorkloadSummary)
-	ctrl.SetNodeRefreshFunc(p.refreshNodeView)
-	ctrl.SetPodRefreshFunc(p.refreshPods)
+	p.bindController(ctrl)
+	p.app.OnK8sClientChanged(p.showClient)
 
 	if err := ctrl.Start(ctx, time.Second*1); err != nil {
 		panic(fmt.Sprintf("main panel: controller start: %s", err))
@@ -313,8 +1015,55 @@ func (p *MainPanel) Run(ctx context.Context) error {
 	return nil
 }
 
+// bindController draws the models refreshed by ctrl
+func (p *MainPanel) bindController(ctrl *k8s.Controller) {
+	ctrl.SetClusterSummaryRefreshFunc(p.refreshWorkloadSummary)
+	ctrl.SetNodeRefreshFunc(p.refreshNodeView)
+	ctrl.SetPodRefreshFunc(p.refreshPods)
+	ctrl.SetControlPlaneRefreshFunc(p.refreshControlPlane)
+	if p.eventsVisible {
+		ctrl.SetEventRefreshFunc(p.refreshEvents)
+	}
+	if p.workloadsShown() {
+		ctrl.SetWorkloadRefreshFunc(p.refreshWorkloads)
+	}
+	if p.storageVisible {
+		ctrl.SetStorageRefreshFunc(p.refreshStorage)
+	}
+	p.ctrl = ctrl
+}
+
+// showClient displays the cluster of client in place of the previous one,
+// which keeps running without drawing
+func (p *MainPanel) showClient(client *k8s.Client) {
+	if p.ctrl != nil {
+		p.ctrl.SetClusterSummaryRefreshFunc(nil)
+		p.ctrl.SetNodeRefreshFunc(nil)
+		p.ctrl.SetPodRefreshFunc(nil)
+		p.ctrl.SetControlPlaneRefreshFunc(nil)
+		p.ctrl.SetEventRefreshFunc(nil)
+		p.ctrl.SetWorkloadRefreshFunc(nil)
+		p.ctrl.SetStorageRefreshFunc(nil)
+	}
+	p.closePodLogs()
+	p.closePodDetail()
+	p.closeNodeDetail()
+	p.clearClusterViews()
+	p.controlPlanePanel.Clear()
+	p.eventsPanel.Clear()
+	for _, panel := range p.workloadPanels {
+		panel.Clear()
+	}
+	p.storagePanel.Clear()
+	p.bindController(client.Controller())
+	client.Controller().Resync()
+	if summary, _ := client.Controller().GetClusterSummary(); summary != nil {
+		p.refreshWorkloadSummary(context.Background(), *summary)
+	}
+}
+
 func (p *MainPanel) refreshNodeView(ctx context.Context, models []model.NodeModel) error {
-	model.SortNodeModelsByField(models, p.sortNodeBy)
+	model.SortNodes(models, p.sortNodeBy)
 	p.currentNodeModels = models
 
 	p.nodePanel.Clear()
@@ -329,15 +1078,16 @@ func (p *MainPanel) refreshNodeView(ctx context.Context, models []model.NodeMode
 }
 
 func (p *MainPanel) refreshPods(ctx context.Context, models []model.PodModel) error {
-	model.SortPodModelsByField(models, p.sortPodBy)
+	model.SortPods(models, p.sortPodBy)
 	p.currentPodModels = models
 
 	// refresh pod list
 	p.podPanel.Clear()
 	p.podPanel.DrawBody(models)
 
-	p.lessPanel.Clear()
-	p.lessPanel = LessPods(p.savePodPanel.(*podPanel), p.podPanel.(*podPanel), p.lessPanel.(*podPanel))
+	if p.diffVisible && p.diffTo == "" {
+		p.drawDiff(ctx)
+	}
 	// required: always refresh screen
 	if p.refresh != nil {
 		p.refresh()
@@ -346,6 +1096,16 @@ func (p *MainPanel) refreshPods(ctx context.Context, models []model.PodModel) er
 	return nil
 }
 
+func (p *MainPanel) refreshControlPlane(ctx context.Context, cp model.ControlPlaneModel) error {
+	p.controlPlanePanel.Clear()
+	p.controlPlanePanel.DrawBody(cp)
+	if p.refresh != nil {
+		p.refresh()
+	}
+
+	return nil
+}
+
 func (p *MainPanel) refreshWorkloadSummary(ctx context.Context, summary model.ClusterSummary) error {
 	p.clusterSummaryPanel.Clear()
 	p.clusterSummaryPanel.DrawBody(summary)
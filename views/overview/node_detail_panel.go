@@ -0,0 +1,221 @@
+package overview
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/ui"
+	"github.com/pjy0381/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// nodeDetailPanel describes a single node: its conditions, taints, capacity,
+// system info, attached volumes, images and the pods scheduled on it. Pressing
+// q or Backspace calls onClose and Enter on a pod calls onSelectPod.
+type nodeDetailPanel struct {
+	app         *application.Application
+	title       string
+	root        *tview.Flex
+	children    []tview.Primitive
+	info        *tview.TextView
+	conditions  *tview.Table
+	taints      *tview.Table
+	resources   *tview.Table
+	pods        *tview.Table
+	images      *tview.Table
+	volumes     *tview.Table
+	metadata    *tview.Table
+	laidout     bool
+	podModels   []model.PodModel
+	onClose     func()
+	onSelectPod func(model.PodModel)
+}
+
+func NewNodeDetailPanel(app *application.Application, title string, onClose func(), onSelectPod func(model.PodModel)) ui.Panel {
+	p := &nodeDetailPanel{app: app, title: title, onClose: onClose, onSelectPod: onSelectPod}
+	p.Layout(nil)
+	p.DrawHeader(nil)
+	return p
+}
+
+func (p *nodeDetailPanel) GetTitle() string {
+	return p.title
+}
+
+func (p *nodeDetailPanel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.info = tview.NewTextView().SetDynamicColors(true).SetWrap(true)
+	p.conditions = newDetailTable(" Conditions ")
+	p.taints = newDetailTable(" Taints ")
+	p.resources = newDetailTable(" Resources ")
+	p.pods = newDetailTable(" Pods ")
+	p.images = newDetailTable(" Images ")
+	p.volumes = newDetailTable(" Attached Volumes ")
+	p.metadata = newDetailTable(" Labels & Annotations ")
+	p.children = []tview.Primitive{p.pods, p.conditions, p.taints, p.resources, p.images, p.volumes, p.metadata}
+
+	p.pods.SetSelectedFunc(func(row, _ int) {
+		if row < 1 || row > len(p.podModels) || p.onSelectPod == nil {
+			return
+		}
+		p.onSelectPod(p.podModels[row-1])
+	})
+
+	status := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(p.conditions, 0, 3, false).
+		AddItem(p.taints, 0, 2, false).
+		AddItem(p.resources, 0, 3, false)
+	inventory := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(p.images, 0, 2, false).
+		AddItem(p.volumes, 0, 2, false).
+		AddItem(p.metadata, 0, 3, false)
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.info, 3, 0, false).
+		AddItem(status, 0, 1, false).
+		AddItem(p.pods, 0, 2, true).
+		AddItem(inventory, 0, 1, false)
+	p.root.SetBorder(true)
+	p.root.SetTitle(p.GetTitle())
+	p.root.SetTitleAlign(tview.AlignLeft)
+	p.root.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2 || event.Rune() == 'q' {
+			if p.onClose != nil {
+				p.onClose()
+			}
+			return nil
+		}
+		return event
+	})
+	p.laidout = true
+}
+
+func (p *nodeDetailPanel) DrawHeader(_ interface{}) {
+	drawDetailHeader(p.conditions, []string{"CONDITION", "STATUS", "REASON", "AGE"})
+	drawDetailHeader(p.taints, []string{"KEY", "VALUE", "EFFECT"})
+	drawDetailHeader(p.resources, []string{"RESOURCE", "CAPACITY", "ALLOCATABLE", "REQUESTED", "USED"})
+	drawDetailHeader(p.pods, []string{"NAMESPACE", "POD", "READY", "STATUS", "RESTARTS", "AGE", "CPU REQ", "CPU USED", "MEM REQ", "MEM USED"})
+	drawDetailHeader(p.images, []string{"IMAGE", "SIZE"})
+	drawDetailHeader(p.volumes, []string{"VOLUME", "DEVICE", "IN USE"})
+	drawDetailHeader(p.metadata, []string{"KIND", "KEY", "VALUE"})
+}
+
+func (p *nodeDetailPanel) DrawBody(data interface{}) {
+	node, ok := data.(model.NodeDetailModel)
+	if !ok {
+		panic(fmt.Sprintf("nodeDetailPanel.DrawBody got unexpected type %T", data))
+	}
+	p.podModels = node.Pods
+
+	p.root.SetTitle(fmt.Sprintf("%s%s (q to close) ", p.GetTitle(), node.Name))
+	info := node.SystemInfo
+	p.info.SetText(fmt.Sprintf(
+		"[yellow]Status: [white]%s  [yellow]Roles: [white]%s  [yellow]Age: [white]%s  [yellow]IPs: [white]%s/%s  [yellow]Hostname: [white]%s\n"+
+			"[yellow]Kubelet: [white]%s  [yellow]Kube-Proxy: [white]%s  [yellow]Runtime: [white]%s  [yellow]OS: [white]%s (%s/%s)  [yellow]Kernel: [white]%s\n"+
+			"[yellow]Machine ID: [white]%s  [yellow]System UUID: [white]%s  [yellow]Boot ID: [white]%s",
+		node.Status, strings.Join(node.Roles, ","), node.TimeSinceStart, node.InternalIP, node.ExternalIP, node.Hostname,
+		info.KubeletVersion, info.KubeProxyVersion, info.ContainerRuntimeVersion, info.OSImage, info.OperatingSystem, info.Architecture, info.KernelVersion,
+		info.MachineID, info.SystemUUID, info.BootID,
+	))
+
+	for i, cond := range node.Conditions {
+		// Ready is the only condition that is healthy when true
+		healthy := (cond.Type == "Ready") == (cond.Status == "True")
+		color := tcell.ColorDarkGreen
+		if !healthy {
+			color = tcell.ColorDarkRed
+		}
+		reason := cond.Reason
+		if cond.Message != "" {
+			reason = strings.TrimSpace(reason + " " + cond.Message)
+		}
+		drawDetailRow(p.conditions, i+1, color, cond.Type, cond.Status, reason, timeSinceText(cond.LastTransitionTime.Time))
+	}
+
+	for i, taint := range node.Taints {
+		drawDetailRow(p.taints, i+1, tcell.ColorWhite, taint.Key, taint.Value, taint.Effect)
+	}
+
+	for i, res := range node.Resources {
+		drawDetailRow(p.resources, i+1, tcell.ColorWhite, res.Name,
+			resourceText(res.Name, res.Capacity), resourceText(res.Name, res.Allocatable),
+			resourceText(res.Name, res.Requested), resourceText(res.Name, res.Used),
+		)
+	}
+
+	for i, pod := range node.Pods {
+		color := tcell.ColorWhite
+		if pod.ReadyContainers < pod.TotalContainers {
+			color = tcell.ColorYellow
+		}
+		drawDetailRow(p.pods, i+1, color,
+			pod.Namespace, pod.Name, fmt.Sprintf("%d/%d", pod.ReadyContainers, pod.TotalContainers), pod.Status,
+			fmt.Sprintf("%d", pod.Restarts), pod.TimeSince,
+			cpuText(pod.PodRequestedCpuQty), cpuText(pod.PodUsageCpuQty),
+			memText(pod.PodRequestedMemQty), memText(pod.PodUsageMemQty),
+		)
+	}
+
+	for i, image := range node.Images {
+		drawDetailRow(p.images, i+1, tcell.ColorWhite, image.Name, memText(resource.NewQuantity(image.SizeBytes, resource.BinarySI)))
+	}
+
+	for i, vol := range node.Volumes {
+		drawDetailRow(p.volumes, i+1, tcell.ColorWhite, vol.Name, vol.DevicePath, fmt.Sprintf("%t", vol.InUse))
+	}
+
+	row := 1
+	for _, section := range []struct {
+		kind  string
+		pairs map[string]string
+	}{{"label", node.Labels}, {"annotation", node.Annotations}} {
+		keys := make([]string, 0, len(section.pairs))
+		for k := range section.pairs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			drawDetailRow(p.metadata, row, tcell.ColorWhite, section.kind, k, section.pairs[k])
+			row++
+		}
+	}
+}
+
+func (p *nodeDetailPanel) DrawFooter(_ interface{}) {}
+
+func (p *nodeDetailPanel) Clear() {
+	for _, table := range []*tview.Table{p.conditions, p.taints, p.resources, p.pods, p.images, p.volumes, p.metadata} {
+		clearDetailTable(table)
+	}
+	p.info.Clear()
+}
+
+func (p *nodeDetailPanel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+func (p *nodeDetailPanel) GetChildrenViews() []tview.Primitive {
+	return p.children
+}
+
+// resourceText formats a node resource quantity, cpu in millicores and
+// memory and storage in Mi
+func resourceText(name string, qty *resource.Quantity) string {
+	switch name {
+	case "cpu":
+		return cpuText(qty)
+	case "memory", "ephemeral-storage":
+		return memText(qty)
+	}
+	if qty == nil {
+		return "-"
+	}
+	return qty.String()
+}
@@ -1,7 +1,6 @@
 package overview
 
 import (
-	"strconv"
 	"math"
 	"fmt"
 
@@ -18,13 +17,22 @@ type nodePanel struct {
 	title    string
 	root     *tview.Flex
 	children []tview.Primitive
-	listCols []string
+	listCols []string // ids of the columns drawn, after the legend
+	cols     []nodeColumn
 	list     *tview.Table
 	laidout bool
+	services []string
+	all      []model.NodeModel // nodes drawn, before filtering
+	nodes    []model.NodeModel
+	filter   *model.Filter
+	sort     model.SortOrder
+	onSelect func(model.NodeModel)
+	onSort   func(key string)
 }
 
-func NewNodePanel(app *application.Application, title string) ui.Panel {
-	p := &nodePanel{app: app, title: title}
+// NewNodePanel returns a node panel with a status column for each of the named host services
+func NewNodePanel(app *application.Application, title string, services []string) ui.Panel {
+	p := &nodePanel{app: app, title: title, services: services}
 	p.Layout(nil)
 	return p
 }
@@ -44,6 +52,12 @@ func (p *nodePanel) Layout(_ interface{}) {
 		p.list.SetBlurFunc(func() {
 			p.list.SetSelectable(false, false)
 		})
+		p.list.SetSelectedFunc(func(row, _ int) {
+			if row < 1 || row > len(p.nodes) || p.onSelect == nil {
+				return
+			}
+			p.onSelect(p.nodes[row-1])
+		})
 
 		p.root = tview.NewFlex().SetDirection(tview.FlexRow).
 			AddItem(p.list, 0, 1, true)
@@ -72,14 +86,23 @@ func (p *nodePanel) DrawHeader(data interface{}) {
 	)
 
 	p.listCols = cols
-	for pos, col := range p.listCols {
+	p.cols = p.cols[:0]
+	registry := nodeColumns(p.services)
+	for pos, id := range p.listCols {
+		col, ok := findNodeColumn(registry, id)
+		if !ok {
+			panic(fmt.Sprintf("nodePanel.DrawHeader got unknown column %q", id))
+		}
+		p.cols = append(p.cols, col)
 		p.list.SetCell(0, pos+1,
-			tview.NewTableCell(col).
+			tview.NewTableCell(sortHeader(col.header, col.sortKey, p.sort)).
 				SetTextColor(tcell.ColorBlack).
 				SetAlign(tview.AlignLeft).
 				SetBackgroundColor(tcell.ColorDarkGray).
-				SetExpansion(100).
-				SetSelectable(false),
+				SetExpansion(col.width.expansion).
+				SetMaxWidth(col.width.maxWidth).
+				SetSelectable(false).
+				SetClickedFunc(p.sortClicked(col.sortKey)),
 		 )
 	}
 
@@ -90,15 +113,13 @@ func (p *nodePanel) DrawBody(data interface{}) {
 	if !ok {
 		panic(fmt.Sprintf("NodePanel.DrawBody: unexpected type %T", data))
 	}
+	p.all = nodes
+	nodes = model.FilterNodes(nodes, p.filter)
+	p.nodes = nodes
 
-	client := p.app.GetK8sClient()
-	metricsDiabled := client.AssertMetricsAvailable() != nil
-	var cpuRatio, memRatio ui.Ratio
-	var cpuGraph, memGraph string
-	var cpuMetrics, memMetrics string
-	colorKeys := ui.ColorKeys{0: "green", 50: "yellow", 90: "red"}
+	metricsDisabled := p.app.GetK8sClient().AssertMetricsAvailable() != nil
 
-	p.root.SetTitle(fmt.Sprintf("%s(%d) ", p.GetTitle(), len(nodes)))
+	p.root.SetTitle(fmt.Sprintf("%s(%s) ", p.GetTitle(), filteredCount(len(nodes), len(p.all), p.filter)))
 	p.root.SetTitleAlign(tview.AlignLeft)
 
 	for i, node := range nodes {
@@ -119,158 +140,98 @@ func (p *nodePanel) DrawBody(data interface{}) {
 			},
 		)
 
-		// name
-		p.list.SetCell(
-			i, 1,
-			&tview.TableCell{
-				Text:  node.Name,
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
-			},
-		)
-
-		statusColor := tcell.ColorYellow
-		if node.Status == "Ready" {
-			statusColor = tcell.ColorDarkGreen
-		} else if node.Status == "Error" {
-			statusColor = tcell.ColorDarkRed
+		cell := nodeCell{node: node, filter: p.filter, metricsDisabled: metricsDisabled}
+		for pos, column := range p.cols {
+			color := tcell.ColorWhite
+			if column.color != nil {
+				color = column.color(cell)
+			}
+			p.list.SetCell(
+				i, pos+1,
+				&tview.TableCell{
+					Text:     column.text(cell),
+					Color:    color,
+					Align:    tview.AlignLeft,
+					MaxWidth: column.width.maxWidth,
+				},
+			)
 		}
+	}
+}
 
-		p.list.SetCell(
-			i, 2,
-			&tview.TableCell{
-				Text:  node.Status,
-				Color: statusColor,
-				Align: tview.AlignLeft,
-			},
-		)
-
-		p.list.SetCell(
-			i, 3,
-			&tview.TableCell{
-				Text:  node.TimeSinceStart,
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
-			},
-		)
+func serviceStateColor(state model.ServiceState) tcell.Color {
+	switch state {
+	case model.ServiceActive:
+		return tcell.ColorDarkGreen
+	case model.ServiceInactive:
+		return tcell.ColorDarkRed
+	default:
+		return tcell.ColorGray
+	}
+}
 
-		p.list.SetCell(
-			i, 4,
-			&tview.TableCell{
-				Text:  fmt.Sprintf("%s/%s", node.InternalIP, node.ExternalIP),
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
-			},
-		)
+func convertMilliValueToGigabytes(milliValue int64) float64 {
+	gigabytes := float64(milliValue) / math.Pow(1024, 4)
+	return gigabytes
+}
 
+// Selected returns the node of the selected row
+func (p *nodePanel) Selected() (model.NodeModel, bool) {
+	row, _ := p.list.GetSelection()
+	if row < 1 || row > len(p.nodes) {
+		return model.NodeModel{}, false
+	}
+	return p.nodes[row-1], true
+}
 
-		p.list.SetCell(
-			i, 5,
-			&tview.TableCell{
-				Text:  fmt.Sprintf("%d/%d", node.PodsCount, node.ContainerImagesCount),
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
-			},
-		)
+// SetFilter narrows the nodes drawn to those matching filter, or to all nodes when nil
+func (p *nodePanel) SetFilter(filter *model.Filter) {
+	p.filter = filter
+	p.Clear()
+	p.DrawBody(p.all)
+}
 
-		if node.Kubelet {
-			statusColor = tcell.ColorDarkGreen
-		} else {
-			statusColor = tcell.ColorDarkRed
+// sortClicked returns the click func of the header of a column sorted by key
+func (p *nodePanel) sortClicked(key string) func() bool {
+	return func() bool {
+		if key != "" && p.onSort != nil {
+			p.onSort(clickedSortKey(key, p.app.GetK8sClient().AssertMetricsAvailable() != nil))
 		}
+		return true
+	}
+}
 
-                p.list.SetCell(
-                        i, 6,
-                        &tview.TableCell{
-                                Text:  strconv.FormatBool(node.Kubelet),
-                                Color: statusColor,
-                                Align: tview.AlignLeft,
-                        },
-                )
-
-                if node.Containerd {
-                        statusColor = tcell.ColorDarkGreen
-                } else {
-                        statusColor = tcell.ColorDarkRed
-                }
-
-                p.list.SetCell(
-                        i, 7,
-                        &tview.TableCell{
-                                Text:  strconv.FormatBool(node.Containerd),
-                                Color: statusColor,
-                                Align: tview.AlignLeft,
-                        },
-                )
-
-                if node.Scini {
-                        statusColor = tcell.ColorDarkGreen
-                } else {
-                        statusColor = tcell.ColorDarkRed
-                }
-
-                p.list.SetCell(
-                        i, 8,
-                        &tview.TableCell{
-                                Text:  strconv.FormatBool(node.Scini),
-                                Color: statusColor,
-                                Align: tview.AlignLeft,
-                        },
-                )
-
-		if metricsDiabled {
-			cpuRatio = ui.GetRatio(float64(node.RequestedPodCpuQty.MilliValue()), float64(node.AllocatableCpuQty.MilliValue()))
-			cpuGraph = ui.BarGraph(10, cpuRatio, colorKeys)
-			cpuMetrics = fmt.Sprintf(
-				"[white][%s[white]] %dm/%dm (%.1f%%)",
-				cpuGraph, node.RequestedPodCpuQty.MilliValue(), node.AllocatableCpuQty.MilliValue(), cpuRatio*100,
-			)
+// SetSort draws the sort arrow of order in the header, the nodes being sorted by the caller
+func (p *nodePanel) SetSort(order model.SortOrder) {
+	p.sort = order
+	p.DrawHeader(p.listCols)
+}
 
-			memRatio = ui.GetRatio(float64(node.RequestedPodMemQty.MilliValue()), float64(node.AllocatableMemQty.MilliValue()))
-			memGraph = ui.BarGraph(10, memRatio, colorKeys)
-			memMetrics = fmt.Sprintf(
-				"[white][%s[white]] %.1fGi/%.1fGi (%.1f%%)",
-				memGraph, convertMilliValueToGigabytes(node.RequestedPodMemQty.MilliValue()), convertMilliValueToGigabytes(node.AllocatableMemQty.MilliValue()), memRatio*100,
-			)
-		} else {
-			cpuRatio = ui.GetRatio(float64(node.UsageCpuQty.MilliValue()), float64(node.AllocatableCpuQty.MilliValue()))
-			cpuGraph = ui.BarGraph(10, cpuRatio, colorKeys)
-			cpuMetrics = fmt.Sprintf(
-				"[white][%s[white]] %dm/%dm (%.1f%%)",
-				cpuGraph, node.UsageCpuQty.MilliValue(), node.AllocatableCpuQty.MilliValue(), cpuRatio*100,
-			)
+// SetSortFunc sets the function called with the sort key of a clicked column header
+func (p *nodePanel) SetSortFunc(fn func(key string)) {
+	p.onSort = fn
+}
 
-			memRatio = ui.GetRatio(float64(node.UsageMemQty.MilliValue()), float64(node.AllocatableMemQty.MilliValue()))
-			memGraph = ui.BarGraph(10, memRatio, colorKeys)
-			memMetrics = fmt.Sprintf(
-				"[white][%s[white]] %.1fGi/%.1fGi (%.1f%%)",
-				memGraph,convertMilliValueToGigabytes(node.UsageMemQty.MilliValue()), convertMilliValueToGigabytes(node.AllocatableMemQty.MilliValue()), memRatio*100,
-			)
-		}
+// Columns returns the ids of the columns drawn
+func (p *nodePanel) Columns() []string {
+	return p.listCols
+}
 
-		p.list.SetCell(
-			i, 9,
-			&tview.TableCell{
-				Text:  cpuMetrics,
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
-			},
-		)
+// ColumnIDs returns the ids of the columns the panel can draw, see nodeColumns
+func (p *nodePanel) ColumnIDs() []string {
+	return nodeColumnIDs(p.services)
+}
 
-		p.list.SetCell(
-			i, 10,
-			&tview.TableCell{
-				Text:  memMetrics,
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
-			},
-		)
-	}
+// SetColumns draws the columns of ids
+func (p *nodePanel) SetColumns(ids []string) {
+	p.listCols = ids
+	p.Clear()
+	p.DrawBody(p.all)
 }
 
-func convertMilliValueToGigabytes(milliValue int64) float64 {
-	gigabytes := float64(milliValue) / math.Pow(1024, 4)
-	return gigabytes
+// SetSelectedFunc sets the function called with the node of a row selected with Enter
+func (p *nodePanel) SetSelectedFunc(fn func(model.NodeModel)) {
+	p.onSelect = fn
 }
 
 func (p *nodePanel) DrawFooter(_ interface{}) {}
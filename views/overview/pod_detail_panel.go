@@ -0,0 +1,186 @@
+package overview
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/ui"
+	"github.com/pjy0381/ktop/views/model"
+)
+
+// podDetailPanel describes a single pod: its containers with their resources
+// and usage, conditions, owners, volumes and recent events. Pressing q or
+// Backspace calls onClose.
+type podDetailPanel struct {
+	app        *application.Application
+	title      string
+	root       *tview.Flex
+	children   []tview.Primitive
+	info       *tview.TextView
+	containers *tview.Table
+	conditions *tview.Table
+	owners     *tview.Table
+	volumes    *tview.Table
+	events     *tview.Table
+	laidout    bool
+	pod        model.PodModel
+	onClose    func()
+	onLogs     func(model.PodModel)
+}
+
+func NewPodDetailPanel(app *application.Application, title string, onClose func(), onLogs func(model.PodModel)) ui.Panel {
+	p := &podDetailPanel{app: app, title: title, onClose: onClose, onLogs: onLogs}
+	p.Layout(nil)
+	p.DrawHeader(nil)
+	return p
+}
+
+func (p *podDetailPanel) GetTitle() string {
+	return p.title
+}
+
+func (p *podDetailPanel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.info = tview.NewTextView().SetDynamicColors(true).SetWrap(true)
+	p.containers = newDetailTable(" Containers ")
+	p.conditions = newDetailTable(" Conditions ")
+	p.owners = newDetailTable(" Owners ")
+	p.volumes = newDetailTable(" Volumes ")
+	p.events = newDetailTable(" Events ")
+	p.children = []tview.Primitive{p.containers, p.conditions, p.owners, p.volumes, p.events}
+
+	sections := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(p.conditions, 0, 2, false).
+		AddItem(p.owners, 0, 1, false).
+		AddItem(p.volumes, 0, 2, false)
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.info, 2, 0, false).
+		AddItem(p.containers, 0, 2, true).
+		AddItem(sections, 0, 1, false).
+		AddItem(p.events, 0, 1, false)
+	p.root.SetBorder(true)
+	p.root.SetTitle(p.GetTitle())
+	p.root.SetTitleAlign(tview.AlignLeft)
+	p.root.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2 || event.Rune() == 'q' {
+			if p.onClose != nil {
+				p.onClose()
+			}
+			return nil
+		}
+		if event.Rune() == 'l' && p.onLogs != nil && p.pod.Name != "" {
+			p.onLogs(p.pod)
+			return nil
+		}
+		return event
+	})
+	p.laidout = true
+}
+
+func (p *podDetailPanel) DrawHeader(_ interface{}) {
+	drawDetailHeader(p.containers, []string{"CONTAINER", "IMAGE", "STATE", "READY", "RESTARTS", "LAST TERMINATION", "CPU REQ/LIM", "CPU USED", "MEM REQ/LIM", "MEM USED"})
+	drawDetailHeader(p.conditions, []string{"CONDITION", "STATUS", "REASON", "AGE"})
+	drawDetailHeader(p.owners, []string{"KIND", "NAME"})
+	drawDetailHeader(p.volumes, []string{"VOLUME", "KIND", "SOURCE"})
+	drawDetailHeader(p.events, []string{"TYPE", "REASON", "AGE", "COUNT", "MESSAGE"})
+}
+
+func (p *podDetailPanel) DrawBody(data interface{}) {
+	pod, ok := data.(model.PodDetailModel)
+	if !ok {
+		panic(fmt.Sprintf("podDetailPanel.DrawBody got unexpected type %T", data))
+	}
+	p.pod = pod.PodModel
+
+	p.root.SetTitle(fmt.Sprintf("%s%s/%s (l logs, q to close) ", p.GetTitle(), pod.Namespace, pod.Name))
+	p.info.SetText(fmt.Sprintf(
+		"[yellow]Node: [white]%s  [yellow]IP: [white]%s  [yellow]Status: [white]%s  [yellow]QoS: [white]%s  [yellow]Service Account: [white]%s  [yellow]Age: [white]%s\n[yellow]Labels: [white]%s",
+		pod.Node, pod.IP, pod.Status, pod.QOSClass, pod.ServiceAccount, pod.TimeSince, labelsText(pod.Labels),
+	))
+
+	for i, c := range pod.Containers {
+		name := c.Name
+		if c.Init {
+			name += " (init)"
+		}
+		color := tcell.ColorWhite
+		if !c.Ready && !c.Init {
+			color = tcell.ColorYellow
+		}
+		drawDetailRow(p.containers, i+1, color,
+			name, c.Image, c.State, fmt.Sprintf("%t", c.Ready), fmt.Sprintf("%d", c.Restarts), c.LastTermination,
+			cpuText(c.RequestedCpuQty)+"/"+cpuText(c.LimitCpuQty), cpuText(c.UsageCpuQty),
+			memText(c.RequestedMemQty)+"/"+memText(c.LimitMemQty), memText(c.UsageMemQty),
+		)
+	}
+
+	for i, cond := range pod.Conditions {
+		color := tcell.ColorDarkGreen
+		if cond.Status != "True" {
+			color = tcell.ColorDarkRed
+		}
+		reason := cond.Reason
+		if cond.Message != "" {
+			reason = strings.TrimSpace(reason + " " + cond.Message)
+		}
+		drawDetailRow(p.conditions, i+1, color, cond.Type, cond.Status, reason, timeSinceText(cond.LastTransitionTime.Time))
+	}
+
+	for i, owner := range pod.Owners {
+		kind := owner.Kind
+		if owner.Controller {
+			kind += " *"
+		}
+		drawDetailRow(p.owners, i+1, tcell.ColorWhite, kind, owner.Name)
+	}
+
+	for i, vol := range pod.Volumes {
+		drawDetailRow(p.volumes, i+1, tcell.ColorWhite, vol.Name, vol.Kind, vol.Source)
+	}
+
+	drawEventRows(p.events, pod.Events)
+}
+
+func (p *podDetailPanel) DrawFooter(_ interface{}) {}
+
+func (p *podDetailPanel) Clear() {
+	for _, table := range []*tview.Table{p.containers, p.conditions, p.owners, p.volumes, p.events} {
+		clearDetailTable(table)
+	}
+	p.info.Clear()
+}
+
+func (p *podDetailPanel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+func (p *podDetailPanel) GetChildrenViews() []tview.Primitive {
+	return p.children
+}
+
+func drawEventRows(table *tview.Table, events []model.EventModel) {
+	for i, event := range events {
+		color := tcell.ColorWhite
+		if event.Type == "Warning" {
+			color = tcell.ColorYellow
+		}
+		drawDetailRow(table, i+1, color, event.Type, event.Reason, event.Age, fmt.Sprintf("%d", event.Count), event.Message)
+	}
+}
+
+func labelsText(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
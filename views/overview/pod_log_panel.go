@@ -0,0 +1,416 @@
+package overview
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/k8s"
+	"github.com/pjy0381/ktop/ui"
+	"github.com/pjy0381/ktop/views/model"
+	"github.com/rivo/tview"
+)
+
+const (
+	// maxLogLines is the number of most recent lines kept by the log panel
+	maxLogLines       = 5000
+	logRenderInterval = 250 * time.Millisecond
+)
+
+// logTailLimits are the tail line limits cycled with + and -, 0 for all lines
+var logTailLimits = []int64{100, 500, 1000, 5000, 0}
+
+// podLogPanel streams the logs of a pod container. Keys: f follow,
+// p previous container instance, c next container, t timestamps, +/- tail
+// limit, / search, n/N next and previous match, q or Backspace close.
+type podLogPanel struct {
+	app      *application.Application
+	title    string
+	root     *tview.Flex
+	children []tview.Primitive
+	status   *tview.TextView
+	text     *tview.TextView
+	search   *tview.InputField
+	laidout  bool
+	onClose  func()
+
+	lock       sync.Mutex
+	ctrl       *k8s.Controller
+	ctx        context.Context
+	cancel     context.CancelFunc
+	namespace  string
+	pod        string
+	containers []string
+	container  int
+	tailIdx    int
+	opts       k8s.LogOptions
+	lines      []string
+	dirty      bool
+	pattern    *regexp.Regexp
+	matches    int
+	match      int
+	streamErr  string
+}
+
+func NewPodLogPanel(app *application.Application, title string, onClose func()) ui.Panel {
+	p := &podLogPanel{app: app, title: title, onClose: onClose, opts: k8s.LogOptions{Follow: true, TailLines: logTailLimits[0]}}
+	p.Layout(nil)
+	return p
+}
+
+func (p *podLogPanel) GetTitle() string {
+	return p.title
+}
+
+func (p *podLogPanel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.status = tview.NewTextView().SetDynamicColors(true)
+	p.text = tview.NewTextView().SetDynamicColors(true).SetRegions(true).SetWrap(true)
+	p.text.SetInputCapture(p.handleKey)
+	p.search = tview.NewInputField().SetLabel("/")
+	p.search.SetChangedFunc(p.setPattern)
+	p.search.SetDoneFunc(func(_ tcell.Key) {
+		p.app.Focus(p.text)
+	})
+	p.children = []tview.Primitive{p.text, p.search}
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.status, 1, 0, false).
+		AddItem(p.text, 0, 1, true).
+		AddItem(p.search, 1, 0, false)
+	p.root.SetBorder(true)
+	p.root.SetTitle(p.GetTitle())
+	p.root.SetTitleAlign(tview.AlignLeft)
+	p.laidout = true
+}
+
+func (p *podLogPanel) DrawHeader(_ interface{}) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.drawStatus()
+}
+
+// DrawBody appends log lines, dropping the oldest beyond maxLogLines
+func (p *podLogPanel) DrawBody(data interface{}) {
+	lines, ok := data.([]string)
+	if !ok {
+		panic(fmt.Sprintf("podLogPanel.DrawBody got unexpected type %T", data))
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.appendLines(lines)
+}
+
+func (p *podLogPanel) DrawFooter(_ interface{}) {}
+
+func (p *podLogPanel) Clear() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.lines = nil
+	p.matches, p.match = 0, 0
+	p.dirty = false
+	p.text.Clear()
+}
+
+func (p *podLogPanel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+func (p *podLogPanel) GetChildrenViews() []tview.Primitive {
+	return p.children
+}
+
+// Open streams the logs of pod with ctrl until Close or ctx is done
+func (p *podLogPanel) Open(ctx context.Context, ctrl *k8s.Controller, pod model.PodModel) {
+	p.Close()
+	// clears the search pattern through setPattern, which takes the lock
+	p.search.SetText("")
+	containers, err := ctrl.GetPodContainerNames(ctx, pod.Namespace, pod.Name)
+
+	p.lock.Lock()
+	p.ctrl, p.ctx = ctrl, ctx
+	p.namespace, p.pod = pod.Namespace, pod.Name
+	p.containers, p.container = containers, 0
+	p.streamErr = ""
+	if err != nil {
+		p.streamErr = err.Error()
+	}
+	p.lock.Unlock()
+
+	p.restart()
+}
+
+// Close stops the log stream. The stream goroutines exit once they observe
+// the cancellation, without Close waiting for them on the UI goroutine.
+func (p *podLogPanel) Close() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+}
+
+// restart replaces the log stream with one using the current options
+func (p *podLogPanel) restart() {
+	p.Close()
+	p.Clear()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.ctrl == nil {
+		return
+	}
+	if len(p.containers) > 0 {
+		p.opts.Container = p.containers[p.container]
+	}
+	p.opts.TailLines = logTailLimits[p.tailIdx]
+
+	ctx, cancel := context.WithCancel(p.ctx)
+	p.cancel = cancel
+	p.root.SetTitle(fmt.Sprintf("%s%s/%s (q to close) ", p.GetTitle(), p.namespace, p.pod))
+	p.drawStatus()
+
+	go p.stream(ctx, p.ctrl, p.namespace, p.pod, p.opts)
+	go p.render(ctx)
+}
+
+func (p *podLogPanel) stream(ctx context.Context, ctrl *k8s.Controller, namespace, pod string, opts k8s.LogOptions) {
+	logs, err := ctrl.GetPodLogs(ctx, namespace, pod, opts)
+	if err != nil {
+		p.setStreamErr(ctx, err)
+		return
+	}
+	defer logs.Close()
+
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		p.lock.Lock()
+		// a restarted stream must not append to the lines of its replacement
+		if ctx.Err() != nil {
+			p.lock.Unlock()
+			return
+		}
+		p.appendLines([]string{scanner.Text()})
+		p.lock.Unlock()
+	}
+	if err := scanner.Err(); err != nil {
+		p.setStreamErr(ctx, err)
+	}
+}
+
+func (p *podLogPanel) setStreamErr(ctx context.Context, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+	p.streamErr = err.Error()
+	p.dirty = true
+}
+
+// render redraws the lines received since the last render, batching the
+// redraws of busy streams
+func (p *podLogPanel) render(ctx context.Context) {
+	ticker := time.NewTicker(logRenderInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		p.lock.Lock()
+		if !p.dirty || ctx.Err() != nil {
+			p.lock.Unlock()
+			continue
+		}
+		p.drawLines()
+		p.lock.Unlock()
+		p.app.Refresh()
+	}
+}
+
+func (p *podLogPanel) appendLines(lines []string) {
+	p.lines = append(p.lines, lines...)
+	if drop := len(p.lines) - maxLogLines; drop > 0 {
+		p.lines = append(p.lines[:0], p.lines[drop:]...)
+	}
+	p.dirty = true
+}
+
+// drawLines writes the lines with the search matches highlighted, keeping
+// the current match or, when following, the end of the logs in view
+func (p *podLogPanel) drawLines() {
+	p.dirty = false
+	var text strings.Builder
+	p.matches = 0
+	for _, line := range p.lines {
+		text.WriteString(p.highlight(line))
+		text.WriteByte('\n')
+	}
+	if p.match >= p.matches {
+		p.match = 0
+	}
+	p.text.SetText(text.String())
+
+	if p.matches > 0 {
+		p.text.Highlight(fmt.Sprintf("m%d", p.match))
+		p.text.ScrollToHighlight()
+	} else if p.opts.Follow {
+		p.text.ScrollToEnd()
+	}
+	p.drawStatus()
+}
+
+// highlight escapes line and marks each match of the search pattern with a
+// region numbered in order of appearance
+func (p *podLogPanel) highlight(line string) string {
+	if p.pattern == nil {
+		return tview.Escape(line)
+	}
+	var text strings.Builder
+	last := 0
+	for _, loc := range p.pattern.FindAllStringIndex(line, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		text.WriteString(tview.Escape(line[last:loc[0]]))
+		text.WriteString(fmt.Sprintf(`["m%d"][black:yellow]%s[-:-][""]`, p.matches, tview.Escape(line[loc[0]:loc[1]])))
+		p.matches++
+		last = loc[1]
+	}
+	text.WriteString(tview.Escape(line[last:]))
+	return text.String()
+}
+
+func (p *podLogPanel) drawStatus() {
+	var status strings.Builder
+	status.WriteString("[yellow]Container: ")
+	for i, name := range p.containers {
+		if i == p.container {
+			status.WriteString(fmt.Sprintf("[black:white]%s[-:-] ", name))
+		} else {
+			status.WriteString(fmt.Sprintf("[white]%s ", name))
+		}
+	}
+	tail := "all"
+	if limit := logTailLimits[p.tailIdx]; limit > 0 {
+		tail = fmt.Sprintf("%d", limit)
+	}
+	status.WriteString(fmt.Sprintf(
+		" [yellow]Tail: [white]%s  [yellow]Follow: [white]%t  [yellow]Previous: [white]%t  [yellow]Timestamps: [white]%t",
+		tail, p.opts.Follow, p.opts.Previous, p.opts.Timestamps,
+	))
+	if p.pattern != nil {
+		current := 0
+		if p.matches > 0 {
+			current = p.match + 1
+		}
+		status.WriteString(fmt.Sprintf("  [yellow]Matches: [white]%d/%d", current, p.matches))
+	}
+	if p.streamErr != "" {
+		status.WriteString(fmt.Sprintf("  [red]%s", tview.Escape(p.streamErr)))
+	}
+	p.status.SetText(status.String())
+}
+
+// setPattern searches the logs as the search text is typed, as a regular
+// expression or, when it does not compile, as plain text
+func (p *podLogPanel) setPattern(text string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.pattern = nil
+	if text != "" {
+		pattern, err := regexp.Compile(text)
+		if err != nil {
+			pattern = regexp.MustCompile(regexp.QuoteMeta(text))
+		}
+		p.pattern = pattern
+	}
+	p.match = 0
+	p.drawLines()
+}
+
+func (p *podLogPanel) nextMatch(step int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.matches == 0 {
+		return
+	}
+	p.match = (p.match + step + p.matches) % p.matches
+	p.text.Highlight(fmt.Sprintf("m%d", p.match))
+	p.text.ScrollToHighlight()
+	p.drawStatus()
+}
+
+func (p *podLogPanel) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2 {
+		p.close()
+		return nil
+	}
+	if event.Key() != tcell.KeyRune {
+		return event
+	}
+
+	p.lock.Lock()
+	restart := true
+	switch event.Rune() {
+	case 'f':
+		p.opts.Follow = !p.opts.Follow
+	case 'p':
+		p.opts.Previous = !p.opts.Previous
+	case 't':
+		p.opts.Timestamps = !p.opts.Timestamps
+	case 'c':
+		if len(p.containers) < 2 {
+			restart = false
+			break
+		}
+		p.container = (p.container + 1) % len(p.containers)
+	case '+':
+		p.tailIdx = (p.tailIdx + 1) % len(logTailLimits)
+	case '-':
+		p.tailIdx = (p.tailIdx + len(logTailLimits) - 1) % len(logTailLimits)
+	default:
+		restart = false
+	}
+	p.lock.Unlock()
+
+	if restart {
+		p.restart()
+		return nil
+	}
+
+	switch event.Rune() {
+	case 'q':
+		p.close()
+	case '/':
+		p.app.Focus(p.search)
+	case 'n':
+		p.nextMatch(1)
+	case 'N':
+		p.nextMatch(-1)
+	default:
+		return event
+	}
+	return nil
+}
+
+func (p *podLogPanel) close() {
+	p.Close()
+	if p.onClose != nil {
+		p.onClose()
+	}
+}
@@ -0,0 +1,42 @@
+package overview
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/k8s"
+	"github.com/pjy0381/ktop/ui"
+)
+
+func TestPodLogPanelKeys(t *testing.T) {
+	client, err := k8s.NewOffline(k8s.OfflineConfig{Context: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := application.New(client)
+	filtered := 0
+	filter := ui.Command{Name: "filter", Keys: []string{"/"}, Run: func([]string) { filtered++ }}
+	if err := app.Commands().Register(filter); err != nil {
+		t.Fatal(err)
+	}
+	app.SetKeyBindings(map[string]string{"x": "filter"})
+	if err := app.BindKeys(); err != nil {
+		t.Fatal(err)
+	}
+	p := NewPodLogPanel(app, "logs", nil).(*podLogPanel)
+	app.Focus(p.text)
+
+	if app.HandleKey(tcell.NewEventKey(tcell.KeyRune, '/', tcell.ModNone)) != nil {
+		t.Error("expecting / to be handled")
+	}
+	if !p.search.HasFocus() || filtered != 0 {
+		t.Errorf("expecting / to focus the search, search focused %t, filter run %d times", p.search.HasFocus(), filtered)
+	}
+
+	app.Focus(p.text)
+	app.HandleKey(tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone))
+	if filtered != 1 {
+		t.Errorf("expecting x to run the bound filter once, got %d", filtered)
+	}
+}
@@ -2,14 +2,12 @@ package overview
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/pjy0381/ktop/application"
 	"github.com/pjy0381/ktop/ui"
 	"github.com/pjy0381/ktop/views/model"
-	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 type podPanel struct {
@@ -17,13 +15,34 @@ type podPanel struct {
 	title    string
 	root     *tview.Flex
 	children []tview.Primitive
-	listCols []string
+	listCols []string // ids of the columns drawn
+	cols     []podColumn
 	list     *tview.Table
 	laidout bool
+	all      []model.PodModel // pods drawn, before filtering
+	pods     []model.PodModel
+	filter   *model.Filter
+	sort     model.SortOrder
+	onSelect func(model.PodModel)
+	onLogs   func(model.PodModel)
+	onSort   func(key string)
+
+	// tree groups the pods under their top-level controllers, which are
+	// collapsed by group key
+	tree      bool
+	collapsed map[string]bool
+	rows      []podRow
+}
+
+// podRow is what a list row shows: the pod at index pod, or when pod is -1
+// the group with key group
+type podRow struct {
+	pod   int
+	group string
 }
 
 func NewPodPanel(app *application.Application, title string) ui.Panel {
-	p := &podPanel{app: app, title: title}
+	p := &podPanel{app: app, title: title, collapsed: make(map[string]bool)}
 	p.Layout(nil)
 
 	return p
@@ -46,6 +65,36 @@ func (p *podPanel) Layout(_ interface{}) {
 		p.list.SetBlurFunc(func() {
 			p.list.SetSelectable(false, false)
 		})
+		p.list.SetSelectedFunc(func(row, _ int) {
+			if group, ok := p.groupAt(row); ok {
+				p.collapsed[group] = !p.collapsed[group]
+				p.redraw()
+				return
+			}
+			if pod, ok := p.podAt(row); ok && p.onSelect != nil {
+				p.onSelect(pod)
+			}
+		})
+		p.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch event.Rune() {
+			case 't':
+				// nothing to group before the first pods are drawn
+				if p.all == nil {
+					return event
+				}
+				p.SetTree(!p.tree)
+				return nil
+			case 'l':
+				if p.onLogs == nil {
+					return event
+				}
+				if pod, ok := p.Selected(); ok {
+					p.onLogs(pod)
+				}
+				return nil
+			}
+			return event
+		})
 
 		p.root = tview.NewFlex().SetDirection(tview.FlexRow).
 			AddItem(p.list, 0, 1, true)
@@ -63,19 +112,59 @@ func (p *podPanel) DrawHeader(data interface{}) {
 	}
 
 	p.listCols = cols
-	for i, col := range p.listCols {
+	p.cols = p.cols[:0]
+	for i, id := range p.listCols {
+		col, ok := findPodColumn(id)
+		if !ok {
+			panic(fmt.Sprintf("podPanel.DrawHeader got unknown column %q", id))
+		}
+		p.cols = append(p.cols, col)
 		p.list.SetCell(0, i,
-			tview.NewTableCell(col).
+			tview.NewTableCell(sortHeader(col.header, col.sortKey, p.sort)).
 				SetTextColor(tcell.ColorBlack).
 				SetBackgroundColor(tcell.ColorDarkGray).
 				SetAlign(tview.AlignLeft).
-				SetExpansion(100).
-				SetSelectable(false),
+				SetExpansion(col.width.expansion).
+				SetMaxWidth(col.width.maxWidth).
+				SetSelectable(false).
+				SetClickedFunc(p.sortClicked(col.sortKey)),
 		)
 	}
 	p.list.SetFixed(1, 0)
 }
 
+// Columns returns the ids of the columns drawn
+func (p *podPanel) Columns() []string {
+	return p.listCols
+}
+
+// SetColumns draws the columns of ids, see podColumns
+func (p *podPanel) SetColumns(ids []string) {
+	p.listCols = ids
+	p.redraw()
+}
+
+// sortClicked returns the click func of the header of a column sorted by key
+func (p *podPanel) sortClicked(key string) func() bool {
+	return func() bool {
+		if key != "" && p.onSort != nil {
+			p.onSort(clickedSortKey(key, p.app.GetK8sClient().AssertMetricsAvailable() != nil))
+		}
+		return true
+	}
+}
+
+// SetSort draws the sort arrow of order in the header, the pods being sorted by the caller
+func (p *podPanel) SetSort(order model.SortOrder) {
+	p.sort = order
+	p.DrawHeader(p.listCols)
+}
+
+// SetSortFunc sets the function called with the sort key of a clicked column header
+func (p *podPanel) SetSortFunc(fn func(key string)) {
+	p.onSort = fn
+}
+
 func (p *podPanel) DrawBodyP (data interface{}) {
 
 }
@@ -86,154 +175,145 @@ func (p *podPanel) DrawBody(data interface{}) {
 		panic(fmt.Sprintf("PodPanel.DrawBody got unexpected type %T", data))
 	}
 
+	p.all = pods
+	pods = model.FilterPods(pods, p.filter)
+	p.pods = pods
+	p.rows = p.rows[:0]
+
 	client := p.app.GetK8sClient()
 	metricsDisabled := client.AssertMetricsAvailable() != nil
-	colorKeys := ui.ColorKeys{0: "green", 50: "yellow", 90: "red"}
-	var cpuRatio, memRatio ui.Ratio
-	var cpuGraph, memGraph string
-	var cpuMetrics, memMetrics string
 
-	p.root.SetTitle(fmt.Sprintf("%s(%d) ", p.GetTitle(), len(pods)))
+	p.root.SetTitle(fmt.Sprintf("%s(%s) ", p.GetTitle(), filteredCount(len(pods), len(p.all), p.filter)))
 	p.root.SetTitleAlign(tview.AlignLeft)
 
+	if p.tree {
+		p.drawTree(pods, metricsDisabled)
+		return
+	}
 	for i, pod := range pods {
-		i++ // offset to n+1
-		p.list.SetCell(
-			i, 0,
-			&tview.TableCell{
-				Text:  pod.Namespace,
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
-			},
-		)
+		p.rows = append(p.rows, podRow{pod: i})
+		p.drawPod(len(p.rows), pod, "", metricsDisabled)
+	}
+}
 
-                p.list.SetCell(
-                        i, 1,
-                        &tview.TableCell{
-                                Text:  pod.Node,
-                                Color: tcell.ColorWhite,
-                                Align: tview.AlignLeft,
-                        },
-                )
+// drawTree draws a row per owner group followed by its pods, unless collapsed
+func (p *podPanel) drawTree(pods []model.PodModel, metricsDisabled bool) {
+	index := make(map[string]int, len(pods))
+	for i, pod := range pods {
+		index[pod.Namespace+"/"+pod.Name] = i
+	}
 
+	for _, group := range model.GroupPodModelsByOwner(pods) {
+		key := group.Key()
+		p.rows = append(p.rows, podRow{pod: -1, group: key})
+		p.drawGroup(len(p.rows), group, metricsDisabled)
+		if p.collapsed[key] {
+			continue
+		}
+		for _, pod := range group.Pods {
+			p.rows = append(p.rows, podRow{pod: index[pod.Namespace+"/"+pod.Name]})
+			p.drawPod(len(p.rows), pod, "  ", metricsDisabled)
+		}
+	}
+}
 
+// drawGroup draws the row of a group with the ready pods, restarts and
+// resources of its pods rolled up
+func (p *podPanel) drawGroup(i int, group model.PodGroupModel, metricsDisabled bool) {
+	cell := podGroupCell{group: group, marker: "▼", metricsDisabled: metricsDisabled}
+	if p.collapsed[group.Key()] {
+		cell.marker = "▶"
+	}
+	for col, column := range p.cols {
+		text := ""
+		if column.group != nil {
+			text = column.group(cell)
+		}
 		p.list.SetCell(
-			i, 2,
+			i, col,
 			&tview.TableCell{
-				Text:  pod.Name,
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
+				Text:     text,
+				Color:    tcell.ColorAqua,
+				Align:    tview.AlignLeft,
+				MaxWidth: column.width.maxWidth,
 			},
 		)
+	}
+}
 
-		podReadyColor := "[green]"
-		if pod.ReadyContainers != pod.TotalContainers {
-			podReadyColor = "[red]"
+// drawPod draws the row of pod, its name prefixed by indent
+func (p *podPanel) drawPod(i int, pod model.PodModel, indent string, metricsDisabled bool) {
+	cell := podCell{pod: pod, filter: p.filter, indent: indent, metricsDisabled: metricsDisabled}
+	for col, column := range p.cols {
+		color := tcell.ColorWhite
+		if column.color != nil {
+			color = column.color(cell)
 		}
-
 		p.list.SetCell(
-			i, 3,
+			i, col,
 			&tview.TableCell{
-				Text:  fmt.Sprintf(podReadyColor + "%d[white]/%d", pod.ReadyContainers, pod.TotalContainers),
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
+				Text:     column.text(cell),
+				Color:    color,
+				Align:    tview.AlignLeft,
+				MaxWidth: column.width.maxWidth,
 			},
 		)
+	}
+}
 
-		podStatusColor := tcell.ColorYellow
-                if strings.Contains(pod.Status, "Running") {
-                        podStatusColor = tcell.ColorDarkGreen
-                } else if strings.Contains(pod.Status, "Error") {
-                        podStatusColor = tcell.ColorDarkRed
-                }
+// Selected returns the pod of the selected row
+func (p *podPanel) Selected() (model.PodModel, bool) {
+	row, _ := p.list.GetSelection()
+	return p.podAt(row)
+}
 
-		p.list.SetCell(
-			i, 4,
-			&tview.TableCell{
-				Text:  pod.Status,
-				Color: podStatusColor,
-				Align: tview.AlignLeft,
-			},
-		)
+// podAt returns the pod drawn at row, if row is not a header or group row
+func (p *podPanel) podAt(row int) (model.PodModel, bool) {
+	if row < 1 || row > len(p.rows) || p.rows[row-1].pod < 0 {
+		return model.PodModel{}, false
+	}
+	return p.pods[p.rows[row-1].pod], true
+}
 
-		p.list.SetCell(
-			i, 5,
-			&tview.TableCell{
-				Text:  fmt.Sprintf("%d", pod.Restarts),
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
-			},
-		)
+// groupAt returns the key of the group drawn at row, if row is a group row
+func (p *podPanel) groupAt(row int) (string, bool) {
+	if row < 1 || row > len(p.rows) || p.rows[row-1].pod >= 0 {
+		return "", false
+	}
+	return p.rows[row-1].group, true
+}
 
-		p.list.SetCell(
-			i, 6,
-			&tview.TableCell{
-				Text:  pod.TimeSince,
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
-			},
-		)
+// SetTree switches between the pod list and the pods grouped under their
+// top-level controllers
+func (p *podPanel) SetTree(tree bool) {
+	p.tree = tree
+	p.redraw()
+}
 
-		// Volume
-		p.list.SetCell(
-			i, 7,
-			&tview.TableCell{
-				Text:  fmt.Sprintf("%d/%d", pod.Volumes, pod.VolMounts),
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
-			},
-		)
+// Tree reports whether the pods are grouped under their controllers
+func (p *podPanel) Tree() bool {
+	return p.tree
+}
 
-		p.list.SetCell(
-			i, 8,
-			&tview.TableCell{
-				Text:  pod.IP,
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
-			},
-		)
+// SetFilter narrows the pods drawn to those matching filter, or to all pods when nil
+func (p *podPanel) SetFilter(filter *model.Filter) {
+	p.filter = filter
+	p.redraw()
+}
 
-		if metricsDisabled {
-			cpuRatio = ui.GetRatio(float64(pod.PodRequestedCpuQty.MilliValue()), float64(pod.NodeAllocatableCpuQty.MilliValue()))
-			cpuGraph = ui.BarGraph(10, cpuRatio, colorKeys)
-			cpuMetrics = fmt.Sprintf(
-				"[white][%s[white]] %dm %02.1f%%",
-				cpuGraph, pod.PodRequestedCpuQty.MilliValue(), cpuRatio*100,
-			)
-
-			memRatio = ui.GetRatio(float64(pod.PodRequestedMemQty.MilliValue()), float64(pod.NodeAllocatableMemQty.MilliValue()))
-			memGraph = ui.BarGraph(10, memRatio, colorKeys)
-			memMetrics = fmt.Sprintf(
-				"[white][%s[white]] %dGi %02.1f%%", memGraph, pod.PodRequestedMemQty.ScaledValue(resource.Giga), memRatio*100,
-			)
-		} else {
-			cpuRatio = ui.GetRatio(float64(pod.PodUsageCpuQty.MilliValue()), float64(pod.NodeAllocatableCpuQty.MilliValue()))
-			cpuGraph = ui.BarGraph(10, cpuRatio, colorKeys)
-			cpuMetrics = fmt.Sprintf("[white][%s[white]] %dm %02.1f%%", cpuGraph, pod.PodUsageCpuQty.MilliValue(), cpuRatio*100)
-
-			memRatio = ui.GetRatio(float64(pod.PodUsageMemQty.MilliValue()), float64(pod.NodeUsageMemQty.MilliValue()))
-			memGraph = ui.BarGraph(10, memRatio, colorKeys)
-			memMetrics = fmt.Sprintf("[white][%s[white]] %dMi %02.1f%%", memGraph, pod.PodUsageMemQty.ScaledValue(resource.Mega), memRatio*100)
-		}
+func (p *podPanel) redraw() {
+	p.Clear()
+	p.DrawBody(p.all)
+}
 
-		p.list.SetCell(
-			i, 9,
-			&tview.TableCell{
-				Text:  cpuMetrics,
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
-			},
-		)
+// SetSelectedFunc sets the function called with the pod of a row selected with Enter
+func (p *podPanel) SetSelectedFunc(fn func(model.PodModel)) {
+	p.onSelect = fn
+}
 
-		p.list.SetCell(
-			i, 10,
-			&tview.TableCell{
-				Text:  memMetrics,
-				Color: tcell.ColorWhite,
-				Align: tview.AlignLeft,
-			},
-		)
-	}
+// SetLogsFunc sets the function called with the pod of the selected row when l is pressed
+func (p *podPanel) SetLogsFunc(fn func(model.PodModel)) {
+	p.onLogs = fn
 }
 
 func (p *podPanel) DrawFooter(data interface{}) {}
@@ -0,0 +1,151 @@
+package overview
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pjy0381/ktop/session"
+	"github.com/pjy0381/ktop/ui"
+	"github.com/pjy0381/ktop/views/model"
+	"github.com/rivo/tview"
+)
+
+// SetPlayer makes the panel replay a recorded session with player instead of
+// watching the cluster of the application client
+func (p *MainPanel) SetPlayer(player *session.Player) {
+	p.player = player
+}
+
+func (p *MainPanel) replaying() bool {
+	return p.player != nil
+}
+
+// runReplay draws the recorded models as the player moves
+func (p *MainPanel) runReplay(ctx context.Context) {
+	p.replayStatus = tview.NewTextView().SetDynamicColors(true)
+	p.root.AddItem(p.replayStatus, 1, 0, false)
+	go p.player.Run(ctx, p.showReplay)
+}
+
+func (p *MainPanel) showReplay(state session.State) {
+	ctx := context.Background()
+	if state.Summary != nil {
+		p.refreshWorkloadSummary(ctx, *state.Summary)
+	}
+	// the models are sorted in place, the frames of the session are not
+	p.refreshNodeView(ctx, append([]model.NodeModel(nil), state.Nodes...))
+	p.refreshPods(ctx, append([]model.PodModel(nil), state.Pods...))
+
+	playback := "[yellow]paused"
+	if state.Playing {
+		playback = fmt.Sprintf("[green]playing x%g", state.Speed)
+	}
+	p.replayStatus.SetText(fmt.Sprintf(
+		"[yellow]Replay of [white]%s [yellow]at [white]%s [yellow]frame [white]%d/%d %s[white]  pause | step [n] | seek [±]<duration>|<hh:mm:ss> | speed <x>",
+		p.player.Header().Context, state.Time.Local().Format("2006-01-02 15:04:05"), state.Frame+1, state.Frames, playback,
+	))
+	if p.refresh != nil {
+		p.refresh()
+	}
+}
+
+// replayCommands returns the playback commands, which tell they need a
+// replay when run live but are registered for the keys bound to them
+func (p *MainPanel) replayCommands() []ui.Command {
+	return p.replayOnly([]ui.Command{
+		{Name: "pause", Help: "pause or resume the replay", Run: func([]string) { p.player.TogglePause() }},
+		{Name: "step", Args: "[n]", Help: "move the replay by n frames, 1 by default", Run: p.stepReplay},
+		{Name: "seek", Args: "<[±]duration|hh:mm:ss>", MinArgs: 1, Help: "move the replay by a duration, such as -5m, or to a time", Run: func(args []string) {
+			if err := p.seekReplay(args[0]); err != nil {
+				p.commandInput.SetPlaceholder(err.Error())
+			}
+		}},
+		{Name: "speed", Args: "<factor>", MinArgs: 1, Help: "replay faster or slower, such as 2 or 0.5", Run: p.setReplaySpeed},
+	}...)
+}
+
+// liveOnly makes cmds tell they need a live cluster when run in replays
+func (p *MainPanel) liveOnly(cmds ...ui.Command) []ui.Command {
+	for i := range cmds {
+		name, run := cmds[i].Name, cmds[i].Run
+		cmds[i].Run = func(args []string) {
+			if p.replaying() {
+				p.commandInput.SetPlaceholder(fmt.Sprintf("%s needs a live cluster, it is not available in replays", name))
+				return
+			}
+			run(args)
+		}
+	}
+	return cmds
+}
+
+// replayOnly makes cmds tell they need a replay when run live
+func (p *MainPanel) replayOnly(cmds ...ui.Command) []ui.Command {
+	for i := range cmds {
+		name, run := cmds[i].Name, cmds[i].Run
+		cmds[i].Run = func(args []string) {
+			if !p.replaying() {
+				p.commandInput.SetPlaceholder(fmt.Sprintf("%s needs a replay, it is only available with --replay", name))
+				return
+			}
+			run(args)
+		}
+	}
+	return cmds
+}
+
+func (p *MainPanel) stepReplay(args []string) {
+	n := 1
+	if len(args) > 0 {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil {
+			p.commandInput.SetPlaceholder(fmt.Sprintf("invalid step %q", args[0]))
+			return
+		}
+	}
+	p.player.Step(n)
+}
+
+func (p *MainPanel) setReplaySpeed(args []string) {
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(args[0], "x"), 64)
+	if err == nil {
+		err = p.player.SetSpeed(speed)
+	}
+	if err != nil {
+		p.commandInput.SetPlaceholder(fmt.Sprintf("invalid speed %q: %v", args[0], err))
+	}
+}
+
+// seekReplay moves the player by a signed duration from the current frame,
+// or to a time of day on the day of the current frame
+func (p *MainPanel) seekReplay(arg string) error {
+	if strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-") {
+		d, err := time.ParseDuration(arg)
+		if err != nil {
+			return fmt.Errorf("invalid seek duration %q", arg)
+		}
+		p.player.Seek(d)
+		return nil
+	}
+
+	clock, err := time.Parse("15:04:05", arg)
+	if err != nil {
+		return fmt.Errorf("invalid seek time %q, expecting hh:mm:ss", arg)
+	}
+	current := p.player.State().Time.Local()
+	p.player.SeekTo(time.Date(current.Year(), current.Month(), current.Day(),
+		clock.Hour(), clock.Minute(), clock.Second(), 0, current.Location()))
+	return nil
+}
+
+// needsLive reports, in the command input, that what is not available in replays
+func (p *MainPanel) needsLive(what string) bool {
+	if !p.replaying() {
+		return false
+	}
+	p.commandInput.SetPlaceholder(fmt.Sprintf("%s need a live cluster, they are not available in replays", what))
+	return true
+}
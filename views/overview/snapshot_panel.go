@@ -0,0 +1,126 @@
+package overview
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/ui"
+	"github.com/pjy0381/ktop/views/model"
+)
+
+// snapshotPanel lists the saved snapshots above the pods of the shown one,
+// the last saved unless another is picked with Enter or Show
+type snapshotPanel struct {
+	app       *application.Application
+	title     string
+	root      *tview.Flex
+	children  []tview.Primitive
+	list      *tview.Table
+	pods      *podPanel
+	snapshots []*model.Snapshot
+	shown     string
+	laidout   bool
+}
+
+func NewSnapshotPanel(app *application.Application, title string) ui.Panel {
+	p := &snapshotPanel{app: app, title: title}
+	p.Layout(nil)
+	p.DrawHeader(nil)
+	return p
+}
+
+func (p *snapshotPanel) GetTitle() string {
+	return p.title
+}
+
+func (p *snapshotPanel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.list = newDetailTable(" Snapshots ")
+	p.list.SetSelectedFunc(func(row, _ int) {
+		if row > 0 && row <= len(p.snapshots) {
+			p.Show(p.snapshots[row-1].Name)
+		}
+	})
+	p.pods = NewPodPanel(p.app, " Pods ").(*podPanel)
+	p.pods.DrawHeader(defaultPodColumns)
+	p.children = []tview.Primitive{p.list, p.pods.list}
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.list, 0, 1, true).
+		AddItem(p.pods.GetRootView(), 0, 3, false)
+	p.root.SetBorder(true)
+	p.root.SetTitle(p.GetTitle())
+	p.root.SetTitleAlign(tview.AlignLeft)
+	p.laidout = true
+}
+
+func (p *snapshotPanel) DrawHeader(_ interface{}) {
+	drawDetailHeader(p.list, []string{"NAME", "TIME", "PODS", "NODES", "WORKLOADS"})
+	p.pods.DrawHeader(p.pods.Columns())
+}
+
+func (p *snapshotPanel) DrawBody(data interface{}) {
+	snapshots, ok := data.([]*model.Snapshot)
+	if !ok {
+		panic(fmt.Sprintf("snapshotPanel.DrawBody got unexpected type %T", data))
+	}
+
+	p.snapshots = snapshots
+	for i, snapshot := range snapshots {
+		drawDetailRow(p.list, i+1, tcell.ColorWhite,
+			snapshot.Name, snapshot.Time.Local().Format("2006-01-02 15:04:05"),
+			fmt.Sprintf("%d", len(snapshot.Pods)), fmt.Sprintf("%d", len(snapshot.Nodes)), fmt.Sprintf("%d", len(snapshot.Workloads)),
+		)
+	}
+	p.list.SetTitle(fmt.Sprintf(" Snapshots(%d) ", len(snapshots)))
+	p.drawPods()
+}
+
+// Show draws the pods of the snapshot name, reporting whether it exists
+func (p *snapshotPanel) Show(name string) bool {
+	for _, snapshot := range p.snapshots {
+		if snapshot.Name == name {
+			p.shown = name
+			p.drawPods()
+			return true
+		}
+	}
+	return false
+}
+
+func (p *snapshotPanel) drawPods() {
+	p.pods.Clear()
+	if len(p.snapshots) == 0 {
+		p.pods.root.SetTitle(" Pods ")
+		return
+	}
+	snapshot := p.snapshots[len(p.snapshots)-1]
+	for _, s := range p.snapshots {
+		if s.Name == p.shown {
+			snapshot = s
+		}
+	}
+	p.pods.DrawBody(snapshot.Pods)
+	p.pods.root.SetTitle(fmt.Sprintf(" Pods of %s(%d) ", snapshot.Name, len(snapshot.Pods)))
+}
+
+func (p *snapshotPanel) DrawFooter(_ interface{}) {}
+
+func (p *snapshotPanel) Clear() {
+	p.list.Clear()
+	p.DrawHeader(nil)
+	p.pods.Clear()
+}
+
+func (p *snapshotPanel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+func (p *snapshotPanel) GetChildrenViews() []tview.Primitive {
+	return p.children
+}
@@ -0,0 +1,47 @@
+package overview
+
+import (
+	"fmt"
+
+	"github.com/pjy0381/ktop/ui"
+	"github.com/pjy0381/ktop/views/model"
+)
+
+// sortHeader returns the header of col, whose sort key is key, with an arrow
+// when order sorts by key. Request keys sort the columns showing usage.
+func sortHeader(col, key string, order model.SortOrder) string {
+	if key == "" || usageSortKey(order.Key) != key {
+		return col
+	}
+	arrow := ui.Icons.SortAsc
+	if order.Desc {
+		arrow = ui.Icons.SortDesc
+	}
+	return fmt.Sprintf("%s %c", col, arrow)
+}
+
+// usageSortKey returns the usage key of a request key, or key itself
+func usageSortKey(key string) string {
+	switch key {
+	case model.SortCpuRequest:
+		return model.SortCpu
+	case model.SortMemRequest:
+		return model.SortMem
+	}
+	return key
+}
+
+// clickedSortKey returns the key sorting by the column of key when its header
+// is clicked, requests instead of usage when the column shows requests
+func clickedSortKey(key string, metricsDisabled bool) string {
+	if !metricsDisabled {
+		return key
+	}
+	switch key {
+	case model.SortCpu:
+		return model.SortCpuRequest
+	case model.SortMem:
+		return model.SortMemRequest
+	}
+	return key
+}
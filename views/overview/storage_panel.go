@@ -0,0 +1,138 @@
+package overview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/ui"
+	"github.com/pjy0381/ktop/views/model"
+)
+
+// storagePanel lists the persistent volume claims with their volumes and the
+// pods mounting them, the persistent volumes and the storage classes. Pending
+// and lost claims are highlighted, as are released and failed volumes.
+type storagePanel struct {
+	app      *application.Application
+	title    string
+	root     *tview.Flex
+	children []tview.Primitive
+	claims   *tview.Table
+	volumes  *tview.Table
+	classes  *tview.Table
+	laidout  bool
+}
+
+func NewStoragePanel(app *application.Application, title string) ui.Panel {
+	p := &storagePanel{app: app, title: title}
+	p.Layout(nil)
+	p.DrawHeader(nil)
+	return p
+}
+
+func (p *storagePanel) GetTitle() string {
+	return p.title
+}
+
+func (p *storagePanel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.claims = newDetailTable(" Claims ")
+	p.volumes = newDetailTable(" Volumes ")
+	p.classes = newDetailTable(" Storage Classes ")
+	p.children = []tview.Primitive{p.claims, p.volumes, p.classes}
+
+	cluster := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(p.volumes, 0, 3, false).
+		AddItem(p.classes, 0, 2, false)
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.claims, 0, 3, true).
+		AddItem(cluster, 0, 2, false)
+	p.root.SetBorder(true)
+	p.root.SetTitle(p.GetTitle())
+	p.root.SetTitleAlign(tview.AlignLeft)
+	p.laidout = true
+}
+
+func (p *storagePanel) DrawHeader(_ interface{}) {
+	drawDetailHeader(p.claims, []string{"NAMESPACE", "CLAIM", "STATUS", "VOLUME", "CLASS", "PROVISIONER", "REQUESTED", "CAPACITY", "ACCESS MODES", "AGE", "PODS"})
+	drawDetailHeader(p.volumes, []string{"VOLUME", "CAPACITY", "ACCESS MODES", "RECLAIM POLICY", "STATUS", "CLAIM", "CLASS", "REASON", "AGE"})
+	drawDetailHeader(p.classes, []string{"CLASS", "PROVISIONER", "RECLAIM POLICY", "BINDING MODE", "EXPANSION"})
+}
+
+func (p *storagePanel) DrawBody(data interface{}) {
+	storage, ok := data.(model.StorageModel)
+	if !ok {
+		panic(fmt.Sprintf("storagePanel.DrawBody got unexpected type %T", data))
+	}
+
+	unhealthy := 0
+	for i, claim := range storage.Claims {
+		color := tcell.ColorWhite
+		switch {
+		case claim.Status == "Lost":
+			color = tcell.ColorRed
+		case claim.Unhealthy():
+			color = tcell.ColorYellow
+		}
+		if claim.Unhealthy() {
+			unhealthy++
+		}
+		pods := "-"
+		if len(claim.Pods) > 0 {
+			pods = strings.Join(claim.Pods, ",")
+		}
+		drawDetailRow(p.claims, i+1, color,
+			claim.Namespace, claim.Name, claim.Status, model.OrDash(claim.Volume), model.OrDash(claim.StorageClass), model.OrDash(claim.Provisioner),
+			storageText(claim.RequestedQty), storageText(claim.CapacityQty), model.OrDash(claim.AccessModes), claim.TimeSince, pods,
+		)
+	}
+
+	for i, volume := range storage.Volumes {
+		color := tcell.ColorWhite
+		switch {
+		case volume.Status == "Failed":
+			color = tcell.ColorRed
+		case volume.Unhealthy():
+			color = tcell.ColorYellow
+		}
+		drawDetailRow(p.volumes, i+1, color,
+			volume.Name, storageText(volume.CapacityQty), model.OrDash(volume.AccessModes), volume.ReclaimPolicy, volume.Status,
+			model.OrDash(volume.Claim), model.OrDash(volume.StorageClass), model.OrDash(volume.Reason), volume.TimeSince,
+		)
+	}
+
+	for i, class := range storage.Classes {
+		name := class.Name
+		if class.Default {
+			name += " (default)"
+		}
+		drawDetailRow(p.classes, i+1, tcell.ColorWhite,
+			name, class.Provisioner, class.ReclaimPolicy, class.VolumeBindingMode, fmt.Sprintf("%t", class.AllowExpansion),
+		)
+	}
+
+	p.claims.SetTitle(fmt.Sprintf(" Claims(%d, %d pending or lost) ", len(storage.Claims), unhealthy))
+	p.volumes.SetTitle(fmt.Sprintf(" Volumes(%d) ", len(storage.Volumes)))
+	p.classes.SetTitle(fmt.Sprintf(" Storage Classes(%d) ", len(storage.Classes)))
+}
+
+func (p *storagePanel) DrawFooter(_ interface{}) {}
+
+func (p *storagePanel) Clear() {
+	for _, table := range []*tview.Table{p.claims, p.volumes, p.classes} {
+		clearDetailTable(table)
+	}
+}
+
+func (p *storagePanel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+func (p *storagePanel) GetChildrenViews() []tview.Primitive {
+	return p.children
+}
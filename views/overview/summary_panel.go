@@ -2,10 +2,12 @@ package overview
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/k8s"
 	"github.com/pjy0381/ktop/ui"
 	"github.com/pjy0381/ktop/views/model"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -45,7 +47,7 @@ func (p *clusterSummaryPanel) Layout(data interface{}) {
 	p.graphTable.SetBorderColor(tcell.ColorWhite)
 
 	root := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(p.summaryTable, 1, 1, true).
+		AddItem(p.summaryTable, 2, 1, true).
 		AddItem(p.graphTable, 1, 1, true)
 	root.SetBorder(true)
 	root.SetTitle(p.GetTitle())
@@ -94,6 +96,8 @@ func (p *clusterSummaryPanel) DrawBody(data interface{}) {
 				"Memory: [white][%s[white]] %dGi/%dGi (%02.1f%% used)",
 				memGraph, summary.UsageNodeMemTotal.ScaledValue(resource.Giga), summary.AllocatableNodeMemTotal.ScaledValue(resource.Giga), memRatio*100,
 			)
+			cpuMetrics += historyText(summary.History.Cpu(), float64(summary.AllocatableNodeCpuTotal.MilliValue()))
+			memMetrics += historyText(summary.History.Mem(), float64(summary.AllocatableNodeMemTotal.Value()))
 		}
 
 		p.graphTable.SetCell(
@@ -113,14 +117,9 @@ func (p *clusterSummaryPanel) DrawBody(data interface{}) {
 		)
 
 		// -=-=-=-=-=-=-=-=-=-=-=-=- cluster summary table -=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-
-		namespace := p.app.GetK8sClient().Namespace()
-		if namespace == "" {
-			namespace = "[Yellow](all)"
-		}
-
 		p.summaryTable.SetCell(
                         0, 0,
-                        tview.NewTableCell(fmt.Sprintf("Selected Namespace: [white]%s", namespace)).
+                        tview.NewTableCell(fmt.Sprintf("Selected Namespace: [white]%s", namespaceScopeText(client, summary.ActiveNamespaces))).
                                 SetTextColor(tcell.ColorYellow).
                                 SetAlign(tview.AlignLeft).
                                 SetExpansion(100),
@@ -144,43 +143,80 @@ func (p *clusterSummaryPanel) DrawBody(data interface{}) {
 				SetExpansion(100),
 		)
 
+		col := 3
+		for _, svc := range summary.Services {
+			p.summaryTable.SetCell(
+				0, col,
+				tview.NewTableCell(fmt.Sprintf("%s: " + getCountColor(svc.Ready, svc.Count) + "%d[white]/%d", serviceTitle(svc.Name), svc.Ready, svc.Count)).
+					SetTextColor(tcell.ColorYellow).
+					SetAlign(tview.AlignLeft).
+					SetExpansion(100),
+			)
+			col++
+		}
+
                 p.summaryTable.SetCell(
-                        0, 3,
-                        tview.NewTableCell(fmt.Sprintf("Kubelet: " + getCountColor(summary.KubeletReady, summary.KubeletCount) + "%d[white]/%d", summary.KubeletReady, summary.KubeletCount)).
+                        0, col,
+                        tview.NewTableCell(fmt.Sprintf("ETCD: " + getCountColor(summary.EtcdReady, summary.EtcdCount)  + "%d[white]/%d", summary.EtcdReady, summary.EtcdCount)).
                                 SetTextColor(tcell.ColorYellow).
                                 SetAlign(tview.AlignLeft).
                                 SetExpansion(100),
                 )
 
 		p.summaryTable.SetCell(
-                        0, 4,
-                        tview.NewTableCell(fmt.Sprintf("Containerd: " + getCountColor(summary.ContainerdReady, summary.ContainerdCount) + "%d[white]/%d", summary.ContainerdReady, summary.ContainerdCount)).
-                                SetTextColor(tcell.ColorYellow).
-                                SetAlign(tview.AlignLeft).
-                                SetExpansion(100),
-                )
+			0, col+1,
+			tview.NewTableCell(fmt.Sprintf("Control Plane: " + getCountColor(summary.ControlPlaneHealthy, summary.ControlPlaneHealthy+summary.ControlPlaneUnhealthy) + "%d[white]/%d", summary.ControlPlaneHealthy, summary.ControlPlaneHealthy+summary.ControlPlaneUnhealthy)).
+				SetTextColor(tcell.ColorYellow).
+				SetAlign(tview.AlignLeft).
+				SetExpansion(100),
+		)
 
-		p.summaryTable.SetCell(
-                        0, 5,
-                        tview.NewTableCell(fmt.Sprintf("Scini: " + getCountColor(summary.SciniReady, summary.SciniCount)  + "%d[white]/%d", summary.SciniReady, summary.SciniCount)).
-                                SetTextColor(tcell.ColorYellow).
-                                SetAlign(tview.AlignLeft).
-                                SetExpansion(100),
-                )
+		// workloads
+		workloadCounts := []string{
+			fmt.Sprintf("Deployments: %s%d[white]/%d", getCountColor(summary.DeploymentsReady, summary.DeploymentsTotal), summary.DeploymentsReady, summary.DeploymentsTotal),
+			fmt.Sprintf("StatefulSets: %s%d[white]/%d", getCountColor(summary.StatefulSetsReady, summary.StatefulSetsDesired), summary.StatefulSetsReady, summary.StatefulSetsDesired),
+			fmt.Sprintf("DaemonSets: %s%d[white]/%d", getCountColor(summary.DaemonSetsReady, summary.DaemonSetsDesired), summary.DaemonSetsReady, summary.DaemonSetsDesired),
+			fmt.Sprintf("ReplicaSets: %s%d[white]/%d", getCountColor(summary.ReplicaSetsReady, summary.ReplicaSetsDesired), summary.ReplicaSetsReady, summary.ReplicaSetsDesired),
+			fmt.Sprintf("Jobs: [white]%d", summary.JobsCount),
+			fmt.Sprintf("CronJobs: [white]%d", summary.CronJobsCount),
+			fmt.Sprintf("PVCs: %s%d[white]/%d (%s)", getCountColor(summary.PVCsBound, summary.PVCCount), summary.PVCsBound, summary.PVCCount, storageText(summary.PVCsTotal)),
+			fmt.Sprintf("PVs: [white]%d (%s)", summary.PVCount, storageText(summary.PVsTotal)),
+		}
+		for i, text := range workloadCounts {
+			p.summaryTable.SetCell(
+				1, i,
+				tview.NewTableCell(text).
+					SetTextColor(tcell.ColorYellow).
+					SetAlign(tview.AlignLeft).
+					SetExpansion(100),
+			)
+		}
 
-                p.summaryTable.SetCell(
-                        0, 6,
-                        tview.NewTableCell(fmt.Sprintf("ETCD: " + getCountColor(summary.EtcdReady, summary.EtcdCount)  + "%d[white]/%d", summary.EtcdReady, summary.EtcdCount)).
-                                SetTextColor(tcell.ColorYellow).
-                                SetAlign(tview.AlignLeft).
-                                SetExpansion(100),
-                )
+		warningColor := "[green]"
+		if summary.WarningEvents > 0 {
+			warningColor = "[red]"
+		}
+		p.summaryTable.SetCell(
+			0, col+2,
+			tview.NewTableCell(fmt.Sprintf("Warnings: %s%d", warningColor, summary.WarningEvents)).
+				SetTextColor(tcell.ColorYellow).
+				SetAlign(tview.AlignLeft).
+				SetExpansion(100),
+		)
 
 	default:
 		panic(fmt.Sprintf("SummaryPanel.DrawBody: unexpected type %T", data))
 	}
 }
 
+// serviceTitle returns a host service name as displayed in headers and counters
+func serviceTitle(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
 func getCountColor(ready, total int) string {
 	if ready != total {
 		return "[red]"
@@ -200,3 +236,22 @@ func (p *clusterSummaryPanel) GetChildrenViews() []tview.Primitive {
 	return p.children
 }
 
+// namespaceScopeText describes the watched namespaces, prefixed by the
+// namespace selector and followed by the object selectors when used
+func namespaceScopeText(client *k8s.Client, active []string) string {
+	text := "[Yellow](all)"
+	switch {
+	case active == nil:
+	case len(active) == 0:
+		text = "[Yellow](none)"
+	default:
+		text = strings.Join(active, ",")
+	}
+	if selector := client.NamespaceSelector(); selector != nil {
+		text = fmt.Sprintf("[Yellow]%s[white] %s", selector.String(), text)
+	}
+	if sel := client.Selectors(); !sel.Empty() {
+		text = fmt.Sprintf("%s [Yellow]sel %s[white]", text, tview.Escape(sel.String()))
+	}
+	return text
+}
@@ -0,0 +1,172 @@
+package overview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/pjy0381/ktop/application"
+	"github.com/pjy0381/ktop/ui"
+	"github.com/pjy0381/ktop/views/model"
+)
+
+// workloadPanel lists the workloads of one kind with their rollout status
+// and the resources of their pods
+type workloadPanel struct {
+	app      *application.Application
+	title    string
+	kind     string
+	root     *tview.Flex
+	children []tview.Primitive
+	listCols []string
+	list     *tview.Table
+	laidout  bool
+}
+
+func NewWorkloadPanel(app *application.Application, title, kind string) ui.Panel {
+	p := &workloadPanel{app: app, title: title, kind: kind}
+	p.Layout(nil)
+	return p
+}
+
+func (p *workloadPanel) GetTitle() string {
+	return p.title
+}
+
+func (p *workloadPanel) Layout(_ interface{}) {
+	if !p.laidout {
+		p.list = tview.NewTable()
+		p.list.SetFixed(1, 0)
+		p.list.SetBorder(false)
+		p.list.SetBorders(false)
+		p.list.SetFocusFunc(func() {
+			p.list.SetSelectable(true, false)
+			p.list.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorYellow).Foreground(tcell.ColorBlue))
+		})
+		p.list.SetBlurFunc(func() {
+			p.list.SetSelectable(false, false)
+		})
+
+		p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(p.list, 0, 1, true)
+		p.root.SetBorder(true)
+		p.root.SetTitle(p.GetTitle())
+		p.root.SetTitleAlign(tview.AlignLeft)
+		p.laidout = true
+	}
+}
+
+func (p *workloadPanel) DrawHeader(data interface{}) {
+	cols, ok := data.([]string)
+	if !ok {
+		panic(fmt.Sprintf("workloadPanel.DrawHeader got unexpected data type %T", data))
+	}
+
+	p.listCols = cols
+	for i, col := range p.listCols {
+		p.list.SetCell(0, i,
+			tview.NewTableCell(col).
+				SetTextColor(tcell.ColorBlack).
+				SetBackgroundColor(tcell.ColorDarkGray).
+				SetAlign(tview.AlignLeft).
+				SetExpansion(100).
+				SetSelectable(false),
+		)
+	}
+	p.list.SetFixed(1, 0)
+}
+
+// DrawBody lists the workloads of the panel kind, those not complete in yellow
+func (p *workloadPanel) DrawBody(data interface{}) {
+	workloads, ok := data.([]model.WorkloadModel)
+	if !ok {
+		panic(fmt.Sprintf("workloadPanel.DrawBody got unexpected type %T", data))
+	}
+
+	row := 0
+	for _, workload := range workloads {
+		if workload.Kind != p.kind {
+			continue
+		}
+		row++
+		color := tcell.ColorWhite
+		switch workload.Status {
+		case "Stalled", "Failed":
+			color = tcell.ColorRed
+		case "Progressing", "Running", "Active":
+			color = tcell.ColorYellow
+		}
+		for col, text := range workloadCells(workload) {
+			p.list.SetCell(
+				row, col,
+				&tview.TableCell{
+					Text:  text,
+					Color: color,
+					Align: tview.AlignLeft,
+				},
+			)
+		}
+	}
+	p.root.SetTitle(fmt.Sprintf("%s(%d) ", p.GetTitle(), row))
+}
+
+func (p *workloadPanel) DrawFooter(_ interface{}) {}
+
+func (p *workloadPanel) Clear() {
+	p.list.Clear()
+	p.Layout(nil)
+	p.DrawHeader(p.listCols)
+}
+
+func (p *workloadPanel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+func (p *workloadPanel) GetChildrenViews() []tview.Primitive {
+	return p.children
+}
+
+// workloadColumns returns the header of the workload panel of kind
+func workloadColumns(kind string) []string {
+	var counts []string
+	switch kind {
+	case model.KindDeployment:
+		counts = []string{"READY", "UP-TO-DATE", "AVAILABLE"}
+	case model.KindStatefulSet:
+		counts = []string{"READY", "UPDATED"}
+	case model.KindDaemonSet:
+		counts = []string{"DESIRED", "READY", "UP-TO-DATE", "AVAILABLE"}
+	case model.KindJob:
+		counts = []string{"COMPLETIONS", "ACTIVE", "FAILED"}
+	case model.KindCronJob:
+		counts = []string{"SCHEDULE", "ACTIVE", "LAST SCHEDULE"}
+	}
+	cols := append([]string{"NAMESPACE", "NAME"}, counts...)
+	return append(cols, "STATUS", "AGE", "PODS", "RESTARTS", "IMAGES", "CPU USED/REQ", "MEM USED/REQ")
+}
+
+// workloadCells returns the row of workload under workloadColumns
+func workloadCells(w model.WorkloadModel) []string {
+	var counts []string
+	switch w.Kind {
+	case model.KindDeployment:
+		counts = []string{fmt.Sprintf("%d/%d", w.Ready, w.Desired), fmt.Sprintf("%d", w.Updated), fmt.Sprintf("%d", w.Available)}
+	case model.KindStatefulSet:
+		counts = []string{fmt.Sprintf("%d/%d", w.Ready, w.Desired), fmt.Sprintf("%d", w.Updated)}
+	case model.KindDaemonSet:
+		counts = []string{fmt.Sprintf("%d", w.Desired), fmt.Sprintf("%d", w.Ready), fmt.Sprintf("%d", w.Updated), fmt.Sprintf("%d", w.Available)}
+	case model.KindJob:
+		counts = []string{fmt.Sprintf("%d/%d", w.Succeeded, w.Desired), fmt.Sprintf("%d", w.Active), fmt.Sprintf("%d", w.Failed)}
+	case model.KindCronJob:
+		counts = []string{w.Schedule, fmt.Sprintf("%d", w.Active), w.LastSchedule}
+	}
+	cells := append([]string{w.Namespace, w.Name}, counts...)
+	return append(cells,
+		w.Status, w.TimeSince,
+		fmt.Sprintf("%d/%d", w.ReadyPods, w.Pods), fmt.Sprintf("%d", w.Restarts),
+		strings.Join(w.Images, ","),
+		cpuText(w.UsageCpuQty)+"/"+cpuText(w.RequestedCpuQty),
+		memText(w.UsageMemQty)+"/"+memText(w.RequestedMemQty),
+	)
+}